@@ -0,0 +1,30 @@
+package blog
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Gateway posle validacije tokena dodaje ove header-e.
+const (
+	headerUsername = "X-Username"
+	headerUserRole = "X-User-Role"
+)
+
+var errAdminOnly = Forbidden("auth.admin_only", "Samo administratori mogu pristupiti ovoj funkciji.")
+
+func isAdmin(r *http.Request) bool {
+	role := strings.ToUpper(r.Header.Get(headerUserRole))
+	return role == "ROLE_ADMIN" || role == "ADMIN"
+}
+
+// RequireAdmin propušta zahtev samo ako je gateway označio korisnika kao admina.
+func RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !isAdmin(r) {
+			writeError(w, r, errAdminOnly)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,94 @@
+package blog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// blogETag je jak ETag jednog bloga: njegova verzija. Isti blog izgleda
+// različito za različite korisnike (myReaction), pa odgovori nose
+// Vary: X-Username.
+func blogETag(blog Blog) string {
+	return `"` + strconv.FormatInt(blog.Version, 10) + `"`
+}
+
+// listETag je slab ETag liste: heš ID-jeva i verzija svih blogova i
+// korisnika za koga je lista napravljena.
+func listETag(blogs []Blog, userID string) string {
+	h := sha256.New()
+	h.Write([]byte(userID))
+	for _, blog := range blogs {
+		h.Write([]byte("\x00" + blog.ID + ":" + strconv.FormatInt(blog.Version, 10)))
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil)[:12]) + `"`
+}
+
+// writeValidators postavlja ETag i Last-Modified i javlja da li klijent
+// već ima ovu verziju (If-None-Match, a bez njega If-Modified-Since);
+// tada je odgovor 304 već poslat.
+func writeValidators(w http.ResponseWriter, r *http.Request, etag string, modified time.Time) bool {
+	h := w.Header()
+	h.Set("ETag", etag)
+	h.Set("Vary", headerUsername)
+	if !modified.IsZero() {
+		h.Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+
+	notModified := false
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		notModified = noneMatch(inm, etag)
+	} else if ims := r.Header.Get("If-Modified-Since"); ims != "" && !modified.IsZero() {
+		since, err := http.ParseTime(ims)
+		notModified = err == nil && !modified.Truncate(time.Second).After(since)
+	}
+	if notModified {
+		h.Del("Content-Type")
+		w.WriteHeader(http.StatusNotModified)
+	}
+	return notModified
+}
+
+// anyVersion je verzija za If-Match: *, koji se poklapa sa bilo kojom
+// postojećom verzijom bloga.
+const anyVersion int64 = -1
+
+// ifMatchVersion čita verziju bloga iz If-Match. Izmena bez If-Match se
+// odbija sa 428; If-Match koji nije ETag bloga (npr. slab) ne može da se
+// poklopi i daje 412, a * vraća anyVersion.
+func ifMatchVersion(r *http.Request) (int64, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		return 0, ErrPreconditionRequired
+	}
+	if header == "*" {
+		return anyVersion, nil
+	}
+	unquoted, ok := strings.CutPrefix(header, `"`)
+	if ok {
+		unquoted, ok = strings.CutSuffix(unquoted, `"`)
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if !ok || err != nil {
+		return 0, ErrVersionMismatch
+	}
+	return version, nil
+}
+
+// noneMatch poredi etag sa listom iz If-None-Match slabim poređenjem, u
+// kome se W/ prefiks zanemaruje.
+func noneMatch(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package blog
+
+import (
+	"log"
+	"time"
+
+	"blog-service/internal/contentfilter"
+)
+
+// ContentFilter proverava tekst pre čuvanja; u produkciji je to
+// contentfilter.Pipeline sa pravilima iz BLOG_FILTER_RULES.
+type ContentFilter interface {
+	Check(c contentfilter.Content) contentfilter.Decision
+}
+
+func errContentRejected(decision contentfilter.Decision) *Error {
+	return &Error{Kind: KindInvalid, Code: "content.rejected", Message: "Sadržaj je odbijen: " + decision.Reason + "."}
+}
+
+// screen propušta sadržaj kroz filter; odbijen sadržaj je greška, a
+// Title i Body iz odluke su tekst koji treba sačuvati. Bez moderacije se
+// i zadržan sadržaj odbija.
+func (s *Service) screen(kind, author, title, body string) (contentfilter.Decision, error) {
+	if s.filter == nil {
+		return contentfilter.Decision{Action: contentfilter.Allow, Title: title, Body: body}, nil
+	}
+
+	activity, err := s.repo.Activity(author)
+	if err != nil {
+		return contentfilter.Decision{}, err
+	}
+	var age time.Duration
+	if !activity.FirstSeenAt.IsZero() {
+		age = time.Since(activity.FirstSeenAt)
+	}
+
+	decision := s.filter.Check(contentfilter.Content{
+		Kind:       kind,
+		Author:     author,
+		Title:      title,
+		Body:       body,
+		AccountAge: age,
+		Posts:      activity.Posts,
+	})
+	if decision.Action == contentfilter.Reject || decision.Action == contentfilter.Hold && s.moderation == nil {
+		return decision, errContentRejected(decision)
+	}
+	return decision, nil
+}
+
+// settle posle čuvanja šalje zadržan sadržaj u red za moderaciju, a
+// objavljen upisuje u istoriju autora. Greške se samo loguju: sadržaj je
+// već sačuvan i ponovljen zahtev bi napravio duplikat.
+func (s *Service) settle(target Target, author string, decision contentfilter.Decision) {
+	if decision.Action == contentfilter.Hold {
+		if err := s.moderation.Hold(target, author, decision); err != nil {
+			log.Printf("❌ Zadržan sadržaj %+v nije dodat u red za moderaciju: %s", target, err)
+		}
+		return
+	}
+	if err := s.repo.RecordActivity(author); err != nil {
+		log.Printf("❌ Aktivnost autora %s nije upisana: %s", author, err)
+	}
+}
@@ -0,0 +1,99 @@
+package blog
+
+import (
+	"fmt"
+	"time"
+
+	"shared/validation"
+)
+
+// Tag u zahtevu ima istu granicu dužine kao posle normalizacije; broj
+// tagova proverava normalizeTags, jer se duplikati ne računaju.
+func init() {
+	validation.RegisterAlias("tags", fmt.Sprintf("dive,required,notblank,max=%d", maxTagLength))
+}
+
+// CreateBlogRequest je jedini oblik koji klijent sme da pošalje pri kreiranju
+// bloga; ID, CreatedAt, Likes i Comments postavlja servis, a tagove
+// normalizuje pre čuvanja. Autor je pozivalac, pa polje author nije
+// dozvoljeno. Blog sa PublishAt se objavljuje tek tada.
+type CreateBlogRequest struct {
+	Title       string     `json:"title" validate:"required,notblank,max=200"`
+	Description string     `json:"description" validate:"required,notblank,max=20000"`
+	Images      []string   `json:"images" validate:"max=10,dive,required,imageurl"`
+	Tags        []string   `json:"tags" validate:"tags"`
+	TourID      int64      `json:"tourId" validate:"gte=0"`
+	KeyPointIDs []int64    `json:"keyPointIds" validate:"max=20,dive,gt=0"`
+	PublishAt   *time.Time `json:"publishAt"`
+}
+
+func (req CreateBlogRequest) toBlog(author string) Blog {
+	return Blog{
+		Author:      author,
+		Title:       req.Title,
+		Description: req.Description,
+		Images:      req.Images,
+		Tags:        req.Tags,
+		TourID:      req.TourID,
+		KeyPointIDs: req.KeyPointIDs,
+		PublishAt:   req.PublishAt,
+	}
+}
+
+// UpdateBlogRequest zamenjuje sadržaj bloga; autor i datum kreiranja se ne
+// menjaju. PublishAt pomera objavu bloga koji je još zakazan; bez njega
+// ostaje postojeće vreme.
+type UpdateBlogRequest struct {
+	Title       string     `json:"title" validate:"required,notblank,max=200"`
+	Description string     `json:"description" validate:"required,notblank,max=20000"`
+	Images      []string   `json:"images" validate:"max=10,dive,required,imageurl"`
+	Tags        []string   `json:"tags" validate:"tags"`
+	TourID      int64      `json:"tourId" validate:"gte=0"`
+	KeyPointIDs []int64    `json:"keyPointIds" validate:"max=20,dive,gt=0"`
+	PublishAt   *time.Time `json:"publishAt"`
+}
+
+func (req UpdateBlogRequest) toBlog(id string) Blog {
+	return Blog{
+		ID:          id,
+		Title:       req.Title,
+		Description: req.Description,
+		Images:      req.Images,
+		Tags:        req.Tags,
+		TourID:      req.TourID,
+		KeyPointIDs: req.KeyPointIDs,
+		PublishAt:   req.PublishAt,
+	}
+}
+
+// CreateCommentRequest: autor komentara je pozivalac, pa polje userId nije
+// dozvoljeno.
+type CreateCommentRequest struct {
+	Text string `json:"text" validate:"required,notblank,max=2000"`
+}
+
+func (req CreateCommentRequest) toComment(userID string) Comment {
+	return Comment{
+		UserID: userID,
+		Text:   req.Text,
+	}
+}
+
+// EditCommentRequest menja samo tekst; autor i datum kreiranja ostaju.
+type EditCommentRequest struct {
+	Text string `json:"text" validate:"required,notblank,max=2000"`
+}
+
+type ReactionRequest struct {
+	Type string `json:"type" validate:"required,notblank,max=32"`
+}
+
+type CreateReportRequest struct {
+	Reason string `json:"reason" validate:"required,oneof=spam harassment hate_speech violence misinformation inappropriate other"`
+	Note   string `json:"note" validate:"max=500"`
+}
+
+type ModerationActionRequest struct {
+	Action string `json:"action" validate:"required,oneof=hide delete approve dismiss warn"`
+	Note   string `json:"note" validate:"max=500"`
+}
@@ -0,0 +1,166 @@
+package blog
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"shared/problem"
+	"shared/validation"
+)
+
+type ErrorKind int
+
+const (
+	KindInternal ErrorKind = iota
+	KindNotFound
+	KindInvalid
+	KindConflict
+	KindForbidden
+	KindPayloadTooLarge
+	KindUnavailable
+	KindPreconditionFailed
+	KindPreconditionRequired
+)
+
+// Error je domenska greška blog paketa. Message se šalje klijentu,
+// a Err (ako postoji) samo završava u logu.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Fields  []problem.FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+func Invalid(code, message string) *Error {
+	return &Error{Kind: KindInvalid, Code: code, Message: message}
+}
+
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+var (
+	ErrBlogNotFound  = NotFound("blog.not_found", "Blog nije pronađen.")
+	ErrInvalidBlogID = Invalid("blog.invalid_id", "Neispravan ID bloga.")
+	ErrNotBlogAuthor = Forbidden("blog.not_author", "Samo autor može da menja ili briše blog.")
+
+	ErrPublishAtPast    = Invalid("blog.publish_at_past", "Vreme objave mora biti u budućnosti.")
+	ErrAlreadyPublished = Conflict("blog.already_published", "Blog je već objavljen; vreme objave se ne može menjati.")
+
+	ErrVersionMismatch      = &Error{Kind: KindPreconditionFailed, Code: "blog.version_mismatch", Message: "Blog je u međuvremenu izmenjen; učitajte ga ponovo."}
+	ErrPreconditionRequired = &Error{Kind: KindPreconditionRequired, Code: "request.precondition_required", Message: "Izmena zahteva If-Match sa ETag-om bloga."}
+
+	ErrRevisionNotFound = NotFound("revision.not_found", "Revizija nije pronađena.")
+	ErrInvalidRevision  = Invalid("revision.invalid_number", "Broj revizije mora biti pozitivan ceo broj.")
+	errRevisionConflict = Conflict("revision.conflict", "Blog je istovremeno menjan, pokušajte ponovo.")
+
+	ErrCommentNotFound  = NotFound("comment.not_found", "Komentar nije pronađen.")
+	ErrNotCommentAuthor = Forbidden("comment.not_author", "Komentar može da obriše njegov autor ili autor bloga.")
+	ErrNotCommentEditor = Forbidden("comment.not_editor", "Samo autor može da izmeni komentar.")
+	ErrUnknownReaction  = Invalid("reaction.unknown_type", "Nepoznat tip reakcije.")
+	errReactionConflict = Conflict("reaction.conflict", "Reakcija je istovremeno menjana, pokušajte ponovo.")
+
+	ErrInvalidTag  = Invalid("tag.invalid", fmt.Sprintf("Tag sme da sadrži samo slova, cifre i '-' i ima najviše %d znaka.", maxTagLength))
+	ErrTooManyTags = Invalid("tag.too_many", fmt.Sprintf("Blog može da ima najviše %d tagova.", MaxTagsPerBlog))
+
+	ErrTourNotFound         = Invalid("tour.not_found", "Tura ne postoji.")
+	ErrKeyPointNotOnTour    = Invalid("tour.keypoint_not_found", "Ključna tačka ne pripada turi.")
+	ErrKeyPointsWithoutTour = Invalid("tour.keypoints_without_tour", "Ključne tačke zahtevaju turu.")
+	ErrInvalidTourID        = Invalid("tour.invalid_id", "Neispravan ID ture.")
+
+	ErrReportNotFound          = NotFound("report.not_found", "Prijava nije pronađena.")
+	ErrInvalidReportID         = Invalid("report.invalid_id", "Neispravan ID prijave.")
+	ErrInvalidReportStatus     = Invalid("report.invalid_status", "Status prijave mora biti open ili resolved.")
+	ErrReportNoteRequired      = Invalid("report.note_required", "Za razlog other opišite problem u polju note.")
+	ErrDuplicateReport         = Conflict("report.duplicate", "Već ste prijavili ovaj sadržaj.")
+	ErrReportResolved          = Conflict("report.already_resolved", "Prijava je već obrađena.")
+	ErrUnknownModerationAction = Invalid("moderation.unknown_action", "Nepoznata akcija moderatora.")
+)
+
+func (k ErrorKind) status() int {
+	switch k {
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindInvalid:
+		return http.StatusBadRequest
+	case KindConflict:
+		return http.StatusConflict
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindPayloadTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case KindUnavailable:
+		return http.StatusServiceUnavailable
+	case KindPreconditionFailed:
+		return http.StatusPreconditionFailed
+	case KindPreconditionRequired:
+		return http.StatusPreconditionRequired
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeError je jedino mesto gde se greške prevode u HTTP odgovor.
+// Sve što nije domenska greška loguje se i vraća kao generički 500.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var domainErr *Error
+	if errors.As(err, &domainErr) && domainErr.Kind != KindInternal {
+		if domainErr.Err != nil {
+			log.Printf("%s %s: %v", r.Method, r.URL.Path, domainErr.Err)
+		}
+		p := problem.New(domainErr.Kind.status(), domainErr.Code, domainErr.Message)
+		p.Errors = domainErr.Fields
+		problem.Write(w, r, p)
+		return
+	}
+
+	log.Printf("❌ %s %s: %v", r.Method, r.URL.Path, err)
+	problem.Write(w, r, problem.New(http.StatusInternalServerError, "internal_error", "Došlo je do greške na serveru."))
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	problem.Write(w, r, problem.New(http.StatusMethodNotAllowed, "method_not_allowed", "Metoda nije dozvoljena."))
+}
+
+func errToursUnavailable(err error) *Error {
+	return &Error{Kind: KindUnavailable, Code: "tour.service_unavailable", Message: "Servis tura trenutno nije dostupan.", Err: err}
+}
+
+func errMalformedBody(err error) *Error {
+	return &Error{Kind: KindInvalid, Code: "request.malformed_body", Message: "Telo zahteva nije ispravan JSON.", Err: err}
+}
+
+// requestError prevodi greške iz validation paketa u domenske greške.
+func requestError(err error) *Error {
+	var fieldErrs validation.Errors
+	switch {
+	case errors.As(err, &fieldErrs):
+		return &Error{Kind: KindInvalid, Code: "request.validation_failed", Message: "Zahtev nije prošao validaciju.", Fields: fieldErrs}
+	case errors.Is(err, validation.ErrBodyTooLarge):
+		return &Error{Kind: KindPayloadTooLarge, Code: "request.body_too_large", Message: "Telo zahteva je preveliko."}
+	default:
+		return errMalformedBody(err)
+	}
+}
@@ -0,0 +1,73 @@
+package blog
+
+import (
+	"log"
+	"time"
+)
+
+// Blog servis objavljuje svoje događaje na topic exchange EventsExchange,
+// kao što service-tours objavljuje tour.*; telo poruke je JSON.
+// Notification servis prati sve osim blog.published, a blog.mentioned se
+// objavljuje jednom za svakog pomenutog korisnika.
+const (
+	EventsExchange = "blog-events-exchange"
+
+	RoutingKeyBlogPublished = "blog.published"
+	RoutingKeyBlogLiked     = "blog.liked"
+	RoutingKeyBlogCommented = "blog.commented"
+	RoutingKeyBlogMentioned = "blog.mentioned"
+)
+
+// EventPublisher šalje događaje bloga drugim servisima; u produkciji je to
+// events.Publisher iz shared/events, za obaveštenja obavijen u
+// events.Background.
+type EventPublisher interface {
+	Publish(routingKey string, event any) error
+}
+
+// BlogPublished je telo događaja blog.published.
+type BlogPublished struct {
+	BlogID      string    `json:"blogId"`
+	Author      string    `json:"author"`
+	Title       string    `json:"title"`
+	Tags        []string  `json:"tags,omitempty"`
+	TourID      int64     `json:"tourId,omitempty"`
+	PublishedAt time.Time `json:"publishedAt"`
+}
+
+// BlogActivity je telo događaja blog.liked, blog.commented i
+// blog.mentioned: korisnik UserID je lajkovao ili komentarisao blog autora
+// BlogAuthor, ili je u blogu ili komentaru pomenuo korisnika Mentioned.
+type BlogActivity struct {
+	BlogID     string    `json:"blogId"`
+	BlogAuthor string    `json:"blogAuthor"`
+	BlogTitle  string    `json:"blogTitle"`
+	UserID     string    `json:"userId"`
+	CommentID  string    `json:"commentId,omitempty"`
+	Mentioned  string    `json:"mentioned,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// notify objavljuje aktivnost na blogu. Lajk ili komentar je već sačuvan,
+// pa greška samo završava u logu.
+func (s *Service) notify(routingKey string, blogID, userID, commentID string) {
+	if s.events == nil {
+		return
+	}
+	blog, err := s.repo.Get(blogID, userID)
+	if err != nil {
+		log.Printf("❌ Događaj %s za blog %s nije poslat: %s", routingKey, blogID, err)
+		return
+	}
+	event := BlogActivity{
+		BlogID:     blogID,
+		BlogAuthor: blog.Author,
+		BlogTitle:  blog.Title,
+		UserID:     userID,
+		CommentID:  commentID,
+		At:         time.Now(),
+	}
+	if err := s.events.Publish(routingKey, event); err != nil {
+		log.Printf("❌ Događaj %s za blog %s nije poslat: %s", routingKey, blogID, err)
+	}
+}
@@ -3,14 +3,29 @@ package blog
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"shared/validation"
+)
+
+// maxBodyBytes ograničava veličinu JSON tela za sve rute bloga.
+const maxBodyBytes = 1 << 20
+
+var (
+	errInvalidPagination = Invalid("request.invalid_pagination", "offset i limit moraju biti nenegativni celi brojevi.")
+	errMissingUser       = Invalid("request.missing_parameter", "Nedostaje korisnik (X-Username ili ?user=).")
+	errInvalidWindow     = Invalid("request.invalid_window", "window mora biti trajanje, npr. 24h ili 7d.")
 )
 
 type Handler struct {
-	service *Service
+	service    *Service
+	moderation *ModerationService
 }
 
-func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service *Service, moderation *ModerationService) *Handler {
+	return &Handler{service: service, moderation: moderation}
 }
 
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
@@ -26,7 +41,251 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 		case "GET":
 			h.getBlogs(w, r)
 		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/blogs/{id}", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			h.GetBlog(w, r)
+		case "PUT":
+			h.UpdateBlog(w, r)
+		case "DELETE":
+			h.DeleteBlog(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/blogs/{id}/revisions", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			h.GetRevisions(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/blogs/{id}/revisions/diff", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			h.GetRevisionDiff(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/blogs/{id}/revisions/{rev}", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			h.GetRevision(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/blogs/{id}/revisions/{rev}/restore", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "POST":
+			h.RestoreRevision(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/blogs/{id}/restore", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "POST":
+			h.RestoreBlog(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/blogs/{id}/events", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			h.StreamEvents(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/blogs/{id}/comments/{commentId}", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "PUT":
+			h.EditComment(w, r)
+		case "DELETE":
+			h.DeleteComment(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/blogs/{id}/comments/{commentId}/restore", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "POST":
+			h.RestoreComment(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/me/trash", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			h.GetTrash(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/tags", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			h.GetTags(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/tags/trending", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			h.GetTrendingTags(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/tours/{id}/blogs", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			h.GetTourBlogs(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/blogs/{id}/reports", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "POST":
+			h.CreateReport(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/blogs/{id}/comments/{commentId}/reports", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "POST":
+			h.CreateReport(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/moderation/reports", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			RequireAdmin(h.GetReports)(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/moderation/reports/{id}/actions", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "POST":
+			RequireAdmin(h.Moderate)(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
 		}
 	})
 
@@ -42,7 +301,69 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 		case "DELETE":
 			h.RemoveLike(w, r)
 		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/blogs/{id}/likes", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			h.GetLikes(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/blogs/reactions", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			h.GetReactionTypes(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/blogs/{id}/reactions", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			h.GetReactions(w, r)
+		case "PUT":
+			h.React(w, r)
+		case "DELETE":
+			h.Unreact(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/blogs/{id}/comments/{commentId}/reactions", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "PUT":
+			h.React(w, r)
+		case "DELETE":
+			h.Unreact(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
 		}
 	})
 
@@ -56,51 +377,438 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 		case "POST":
 			h.AddComment(w, r)
 		default:
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			writeMethodNotAllowed(w, r)
 		}
 	})
 }
 
 func (h *Handler) createBlog(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	var blog Blog
-	if err := json.NewDecoder(r.Body).Decode(&blog); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	user := callerID(r)
+	if user == "" {
+		writeError(w, r, errMissingUser)
+		return
+	}
+	var req CreateBlogRequest
+	if err := decodeAndValidate(w, r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if err := h.service.Create(blog); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	blog, err := h.service.Create(req.toBlog(user))
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
+	// 202: blog je sačuvan, ali čeka odobrenje moderatora.
+	if blog.Hidden {
+		w.WriteHeader(http.StatusAccepted)
+	} else {
+		w.Header().Set("ETag", blogETag(blog))
+		w.WriteHeader(http.StatusCreated)
+	}
 	json.NewEncoder(w).Encode(blog)
 }
 
+// getBlogs: GET /blogs; ETag liste se menja kada se promeni bilo koji blog,
+// a Last-Modified i kada blog nestane iz liste.
 func (h *Handler) getBlogs(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
-	blogs, err := h.service.GetAll()
+	// Vreme se čita pre liste, pa izmena između dva čitanja ne može da
+	// ostane skrivena iza If-Modified-Since.
+	modified, err := h.service.LastModified()
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
+	}
+	filter := BlogFilter{Tag: r.URL.Query().Get("tag")}
+	blogs, err := h.service.GetAll(callerID(r), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if writeValidators(w, r, listETag(blogs, callerID(r)), modified) {
 		return
 	}
 	json.NewEncoder(w).Encode(blogs)
 }
 
+// GetBlog: GET /blogs/{id}; If-None-Match sa trenutnim ETag-om daje 304.
+func (h *Handler) GetBlog(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	blog, err := h.service.Get(r.PathValue("id"), callerID(r))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if writeValidators(w, r, blogETag(blog), blog.UpdatedAt) {
+		return
+	}
+	json.NewEncoder(w).Encode(blog)
+}
+
+// UpdateBlog: PUT /blogs/{id} sa If-Match: "<verzija>" ili *; zastareo ETag daje 412.
+func (h *Handler) UpdateBlog(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	user := callerID(r)
+	if user == "" {
+		writeError(w, r, errMissingUser)
+		return
+	}
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req UpdateBlogRequest
+	if err := decodeAndValidate(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	blog, err := h.service.Update(req.toBlog(r.PathValue("id")), user, version)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeUpdatedBlog(w, blog)
+}
+
+// writeUpdatedBlog: izmenjen blog nosi novi ETag, a zadržan (202) ga nema
+// jer se do odobrenja ne može čitati.
+func writeUpdatedBlog(w http.ResponseWriter, blog Blog) {
+	if blog.Hidden {
+		w.WriteHeader(http.StatusAccepted)
+	} else {
+		w.Header().Set("ETag", blogETag(blog))
+		w.Header().Set("Last-Modified", blog.UpdatedAt.UTC().Format(http.TimeFormat))
+	}
+	json.NewEncoder(w).Encode(blog)
+}
+
+// DeleteBlog: DELETE /blogs/{id} sa If-Match, kao UpdateBlog.
+func (h *Handler) DeleteBlog(w http.ResponseWriter, r *http.Request) {
+	user := callerID(r)
+	if user == "" {
+		writeError(w, r, errMissingUser)
+		return
+	}
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.service.Delete(r.PathValue("id"), user, isAdmin(r), version); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetRevisions: GET /blogs/{id}/revisions?offset=0&limit=20, samo za autora i administratore.
+func (h *Handler) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	offset, limit, ok := pagination(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.service.Revisions(r.PathValue("id"), callerID(r), isAdmin(r), offset, limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(page)
+}
+
+// GetRevision: GET /blogs/{id}/revisions/{rev}, revizija sa markdown tekstom.
+func (h *Handler) GetRevision(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	number, err := strconv.Atoi(r.PathValue("rev"))
+	if err != nil || number <= 0 {
+		writeError(w, r, ErrInvalidRevision)
+		return
+	}
+
+	rev, err := h.service.Revision(r.PathValue("id"), callerID(r), isAdmin(r), number)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(rev)
+}
+
+// GetRevisionDiff: GET /blogs/{id}/revisions/diff?from=1&to=3; bez to se
+// poredi poslednja revizija, a bez from revizija pre to.
+func (h *Handler) GetRevisionDiff(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	from, err1 := queryInt(r, "from")
+	to, err2 := queryInt(r, "to")
+	if err1 != nil || err2 != nil || from < 0 || to < 0 {
+		writeError(w, r, ErrInvalidRevision)
+		return
+	}
+
+	diff, err := h.service.Diff(r.PathValue("id"), callerID(r), isAdmin(r), from, to)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(diff)
+}
+
+// RestoreRevision: POST /blogs/{id}/revisions/{rev}/restore sa If-Match,
+// kao PUT /blogs/{id}; vraćanje je nova revizija.
+func (h *Handler) RestoreRevision(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	user := callerID(r)
+	if user == "" {
+		writeError(w, r, errMissingUser)
+		return
+	}
+	number, err := strconv.Atoi(r.PathValue("rev"))
+	if err != nil || number <= 0 {
+		writeError(w, r, ErrInvalidRevision)
+		return
+	}
+	version, err := ifMatchVersion(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	blog, err := h.service.Restore(r.PathValue("id"), number, user, version)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	writeUpdatedBlog(w, blog)
+}
+
+// RestoreBlog: POST /blogs/{id}/restore vraća blog iz korpe; ETag vraćenog
+// bloga klijent čita sa GET /blogs/{id}.
+func (h *Handler) RestoreBlog(w http.ResponseWriter, r *http.Request) {
+	h.restore(w, r, Target{BlogID: r.PathValue("id")})
+}
+
+// DeleteComment: DELETE /blogs/{id}/comments/{commentId} premešta komentar u korpu.
+func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	user := callerID(r)
+	if user == "" {
+		writeError(w, r, errMissingUser)
+		return
+	}
+
+	target := Target{BlogID: r.PathValue("id"), CommentID: r.PathValue("commentId")}
+	if err := h.service.DeleteComment(target, user, isAdmin(r)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EditComment: PUT /blogs/{id}/comments/{commentId} vraća izmenjen
+// komentar; 202 ako je izmena zadržana za moderaciju.
+func (h *Handler) EditComment(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	user := callerID(r)
+	if user == "" {
+		writeError(w, r, errMissingUser)
+		return
+	}
+
+	var req EditCommentRequest
+	if err := decodeAndValidate(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	target := Target{BlogID: r.PathValue("id"), CommentID: r.PathValue("commentId")}
+	comment, err := h.service.EditComment(target, user, req.Text)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if comment.Hidden {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	json.NewEncoder(w).Encode(comment)
+}
+
+// RestoreComment: POST /blogs/{id}/comments/{commentId}/restore
+func (h *Handler) RestoreComment(w http.ResponseWriter, r *http.Request) {
+	h.restore(w, r, Target{BlogID: r.PathValue("id"), CommentID: r.PathValue("commentId")})
+}
+
+func (h *Handler) restore(w http.ResponseWriter, r *http.Request, target Target) {
+	user := callerID(r)
+	if user == "" {
+		writeError(w, r, errMissingUser)
+		return
+	}
+
+	if err := h.service.RestoreDeleted(target, user, isAdmin(r)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetTrash: GET /me/trash vraća blogove i komentare koje je korisnik obrisao.
+func (h *Handler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	user := callerID(r)
+	if user == "" {
+		writeError(w, r, errMissingUser)
+		return
+	}
+
+	trash, err := h.service.Trash(user)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(trash)
+}
+
+// GetTourBlogs: GET /tours/{id}/blogs
+func (h *Handler) GetTourBlogs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	tourID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil || tourID <= 0 {
+		writeError(w, r, ErrInvalidTourID)
+		return
+	}
+
+	blogs, err := h.service.GetAll(callerID(r), BlogFilter{TourID: tourID})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(blogs)
+}
+
+// CreateReport: POST /blogs/{id}/reports i POST /blogs/{id}/comments/{commentId}/reports
+func (h *Handler) CreateReport(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	user := callerID(r)
+	if user == "" {
+		writeError(w, r, errMissingUser)
+		return
+	}
+
+	var req CreateReportRequest
+	if err := decodeAndValidate(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	target := Target{BlogID: r.PathValue("id"), CommentID: r.PathValue("commentId")}
+	report, err := h.moderation.Report(target, user, req.Reason, req.Note)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetReports: GET /moderation/reports?status=open&offset=0&limit=20
+func (h *Handler) GetReports(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	offset, limit, ok := pagination(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.moderation.Queue(r.URL.Query().Get("status"), offset, limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(page)
+}
+
+// Moderate: POST /moderation/reports/{id}/actions
+func (h *Handler) Moderate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	moderator := r.Header.Get(headerUsername)
+	if moderator == "" {
+		writeError(w, r, errMissingUser)
+		return
+	}
+
+	var req ModerationActionRequest
+	if err := decodeAndValidate(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	report, err := h.moderation.Act(r.PathValue("id"), moderator, req.Action, req.Note)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// GetTags: GET /tags?limit=100
+func (h *Handler) GetTags(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	limit, err := queryInt(r, "limit")
+	if err != nil || limit < 0 {
+		writeError(w, r, errInvalidPagination)
+		return
+	}
+
+	tags, err := h.service.Tags(limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(tags)
+}
+
+// GetTrendingTags: GET /tags/trending?window=7d&limit=10
+func (h *Handler) GetTrendingTags(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	limit, err := queryInt(r, "limit")
+	if err != nil || limit < 0 {
+		writeError(w, r, errInvalidPagination)
+		return
+	}
+	window, err := parseWindow(r.URL.Query().Get("window"))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	tags, err := h.service.TrendingTags(window, limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(tags)
+}
+
 func (h *Handler) AddLike(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	id := r.URL.Query().Get("id")
 	user := r.URL.Query().Get("user")
 
 	if id == "" || user == "" {
-		http.Error(w, "Missing id or user", http.StatusBadRequest)
+		writeError(w, r, Invalid("request.missing_parameter", "Nedostaje id ili user."))
 		return
 	}
 
 	if err := h.service.AddLike(id, user); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -113,46 +821,195 @@ func (h *Handler) RemoveLike(w http.ResponseWriter, r *http.Request) {
 	user := r.URL.Query().Get("user")
 
 	if id == "" || user == "" {
-		http.Error(w, "Missing id or user", http.StatusBadRequest)
+		writeError(w, r, Invalid("request.missing_parameter", "Nedostaje id ili user."))
 		return
 	}
 
 	if err := h.service.RemoveLike(id, user); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
 	json.NewEncoder(w).Encode(map[string]string{"message": "Lajk uklonjen."})
 }
 
+// GetLikes: GET /blogs/{id}/likes?offset=0&limit=20
+func (h *Handler) GetLikes(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	offset, limit, ok := pagination(w, r)
+	if !ok {
+		return
+	}
+
+	page, err := h.service.GetLikes(r.PathValue("id"), offset, limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(page)
+}
+
+// GetReactionTypes: GET /blogs/reactions
+func (h *Handler) GetReactionTypes(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]string{"types": ReactionTypes()})
+}
+
+// GetReactions: GET /blogs/{id}/reactions?type=love&offset=0&limit=20
+func (h *Handler) GetReactions(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	offset, limit, ok := pagination(w, r)
+	if !ok {
+		return
+	}
+	reactionType := r.URL.Query().Get("type")
+	if reactionType == "" {
+		reactionType = ReactionLike
+	}
+
+	page, err := h.service.GetReactions(r.PathValue("id"), reactionType, offset, limit)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(page)
+}
+
+// React: PUT /blogs/{id}/reactions i PUT /blogs/{id}/comments/{commentId}/reactions
+func (h *Handler) React(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	user := callerID(r)
+	if user == "" {
+		writeError(w, r, errMissingUser)
+		return
+	}
+
+	var req ReactionRequest
+	if err := decodeAndValidate(w, r, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	target := Target{BlogID: r.PathValue("id"), CommentID: r.PathValue("commentId")}
+	if err := h.service.React(target, user, req.Type); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(Reaction{UserID: user, Type: req.Type})
+}
+
+// Unreact: DELETE /blogs/{id}/reactions i DELETE /blogs/{id}/comments/{commentId}/reactions
+func (h *Handler) Unreact(w http.ResponseWriter, r *http.Request) {
+	user := callerID(r)
+	if user == "" {
+		writeError(w, r, errMissingUser)
+		return
+	}
+
+	target := Target{BlogID: r.PathValue("id"), CommentID: r.PathValue("commentId")}
+	if err := h.service.Unreact(target, user); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (h *Handler) AddComment(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	id := r.URL.Query().Get("id")
 	if id == "" {
-		http.Error(w, "Missing blog id", http.StatusBadRequest)
+		writeError(w, r, Invalid("request.missing_parameter", "Nedostaje id bloga."))
 		return
 	}
-
-	var comment Comment
-	if err := json.NewDecoder(r.Body).Decode(&comment); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	user := callerID(r)
+	if user == "" {
+		writeError(w, r, errMissingUser)
 		return
 	}
 
-	if comment.UserID == "" || comment.Text == "" {
-		http.Error(w, "Missing userId or text", http.StatusBadRequest)
+	var req CreateCommentRequest
+	if err := decodeAndValidate(w, r, &req); err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	if err := h.service.AddComment(id, comment); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	comment, err := h.service.AddComment(id, req.toComment(user))
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
+	if comment.Hidden {
+		w.WriteHeader(http.StatusAccepted)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
 	json.NewEncoder(w).Encode(comment)
 }
 
+// callerID je korisnik koji šalje zahtev: X-Username od gateway-a, a za
+// klijente koji idu direktno na servis ?user= kao kod lajkova.
+func callerID(r *http.Request) string {
+	if user := r.Header.Get(headerUsername); user != "" {
+		return user
+	}
+	return r.URL.Query().Get("user")
+}
+
+// pagination čita ?offset= i ?limit=; nula znači podrazumevanu vrednost.
+func pagination(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	offset, err1 := queryInt(r, "offset")
+	limit, err2 := queryInt(r, "limit")
+	if err1 != nil || err2 != nil || offset < 0 || limit < 0 {
+		writeError(w, r, errInvalidPagination)
+		return 0, 0, false
+	}
+	return offset, limit, true
+}
+
+// queryInt vraća 0 ako parametar nije poslat.
+func queryInt(r *http.Request, name string) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(raw)
+}
+
+// parseWindow prihvata Go trajanje ("36h") ili broj dana ("7d");
+// prazan string znači podrazumevani prozor.
+func parseWindow(raw string) (time.Duration, error) {
+	if raw == "" {
+		return 0, nil
+	}
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, errInvalidWindow
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	window, err := time.ParseDuration(raw)
+	if err != nil || window <= 0 {
+		return 0, errInvalidWindow
+	}
+	return window, nil
+}
+
+func decodeAndValidate(w http.ResponseWriter, r *http.Request, dst any) error {
+	if err := validation.DecodeJSON(w, r, dst, maxBodyBytes); err != nil {
+		return requestError(err)
+	}
+	if err := validation.Struct(dst); err != nil {
+		return requestError(err)
+	}
+	return nil
+}
+
 func setCORSHeaders(w http.ResponseWriter) {
 	w.Header().Set("Access-Control-Allow-Origin", "http://localhost:4200")
 	w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
@@ -0,0 +1,1237 @@
+package blog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"blog-service/internal/contentfilter"
+	"blog-service/internal/realtime"
+	"shared/problem"
+)
+
+var (
+	_ BlogRepository   = (*MemoryRepository)(nil)
+	_ ReportRepository = (*MemoryReportRepository)(nil)
+)
+
+const missingBlogID = "000000000000000000000000"
+
+// testTourID je jedina tura u katalogu test servera, sa ključnim tačkama 1 i 2.
+const testTourID = 7
+
+// newTestServer vraća mux nad memorijskim skladištem sa jednim blogom.
+func newTestServer(t *testing.T) (*http.ServeMux, *MemoryRepository, string) {
+	t.Helper()
+	repo := NewMemoryRepository()
+	if _, err := repo.Create(Blog{Author: "ana", Title: "Prvi", Description: "<p>tekst</p>"}); err != nil {
+		t.Fatal(err)
+	}
+	blogs, _ := repo.GetAll("", BlogFilter{})
+
+	mux := http.NewServeMux()
+	moderation := NewModerationService(repo, NewMemoryReportRepository())
+	NewHandler(newTestService(repo, moderation), moderation).RegisterRoutes(mux)
+	return mux, repo, blogs[0].ID
+}
+
+// newTestService: filter odbija "kazino", maskira "budala" i zadržava
+// sadržaj sa više od jednog linka. Pomenuti mogu biti ana, marko i jova;
+// zoran je blokiran, a luka je blokirao anu.
+func newTestService(repo BlogRepository, moderation *ModerationService) *Service {
+	catalog := NewMemoryTourCatalog()
+	catalog.AddTour(testTourID, 1, 2)
+	filter := contentfilter.NewPipeline(
+		contentfilter.NewWordListRule("kocka", contentfilter.Reject, nil, []string{"kazino"}),
+		contentfilter.NewWordListRule("psovke", contentfilter.Mask, nil, []string{"budala"}),
+		contentfilter.NewLinkLimitRule("linkovi", contentfilter.Hold, nil, 1),
+	)
+	users := NewMemoryUserDirectory()
+	for i, username := range []string{"ana", "marko", "jova", "zoran", "luka"} {
+		users.AddUser(username, int64(i+1))
+	}
+	users.Block("zoran")
+	blocks := NewMemoryBlockList()
+	blocks.Block(5, 1)
+	return NewService(repo, NewMemoryRevisionRepository(), ServiceOptions{
+		Tours:      catalog,
+		Users:      users,
+		Blocks:     blocks,
+		Filter:     filter,
+		Moderation: moderation,
+		Live:       realtime.NewHub(realtime.DefaultHistory),
+	})
+}
+
+func TestHandlers(t *testing.T) {
+	// path može da sadrži {id}, koji se menja ID-jem postojećeg bloga.
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		headers    map[string]string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "lista blogova", method: http.MethodGet, path: "/blogs", wantStatus: http.StatusOK},
+		{name: "kreiranje", method: http.MethodPost, path: "/blogs?user=ana", body: `{"title":"Naslov","description":"# Opis"}`, wantStatus: http.StatusCreated},
+		{name: "kreiranje bez korisnika", method: http.MethodPost, path: "/blogs", body: `{"title":"Naslov","description":"Opis"}`, wantStatus: http.StatusBadRequest, wantCode: "request.missing_parameter"},
+		{name: "autor iz tela", method: http.MethodPost, path: "/blogs?user=ana", body: `{"author":"marko","title":"Naslov","description":"Opis"}`, wantStatus: http.StatusBadRequest, wantCode: "request.validation_failed"},
+		{name: "kreiranje sa slikom", method: http.MethodPost, path: "/blogs?user=ana", body: `{"title":"Naslov","description":"Opis","images":["https://cdn.example.com/a.png"]}`, wantStatus: http.StatusCreated},
+		{name: "prazan naslov", method: http.MethodPost, path: "/blogs?user=ana", body: `{"title":"  ","description":"Opis"}`, wantStatus: http.StatusBadRequest, wantCode: "request.validation_failed"},
+		{name: "neispravna slika", method: http.MethodPost, path: "/blogs?user=ana", body: `{"title":"Naslov","description":"Opis","images":["ftp://x/a.txt"]}`, wantStatus: http.StatusBadRequest, wantCode: "request.validation_failed"},
+		{name: "nepoznato polje", method: http.MethodPost, path: "/blogs?user=ana", body: `{"title":"Naslov","description":"Opis","likes":["x"]}`, wantStatus: http.StatusBadRequest, wantCode: "request.validation_failed"},
+		{name: "neispravan JSON", method: http.MethodPost, path: "/blogs?user=ana", body: `{"title":`, wantStatus: http.StatusBadRequest, wantCode: "request.malformed_body"},
+		{name: "kreiranje sa tagovima", method: http.MethodPost, path: "/blogs?user=ana", body: `{"title":"Naslov","description":"Opis","tags":["Kopaonik","#planinarenje"]}`, wantStatus: http.StatusCreated},
+		{name: "neispravan tag", method: http.MethodPost, path: "/blogs?user=ana", body: `{"title":"Naslov","description":"Opis","tags":["a/b"]}`, wantStatus: http.StatusBadRequest, wantCode: "tag.invalid"},
+		{name: "predugačak tag", method: http.MethodPost, path: "/blogs?user=ana", body: `{"title":"Naslov","description":"Opis","tags":["aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"]}`, wantStatus: http.StatusBadRequest, wantCode: "request.validation_failed"},
+		{name: "previše tagova", method: http.MethodPost, path: "/blogs?user=ana", body: `{"title":"Naslov","description":"Opis","tags":["a","b","c","d","e","f","g","h","i","j","k"]}`, wantStatus: http.StatusBadRequest, wantCode: "tag.too_many"},
+		{name: "filter po tagu", method: http.MethodGet, path: "/blogs?tag=Kopaonik", wantStatus: http.StatusOK},
+		{name: "filter po neispravnom tagu", method: http.MethodGet, path: "/blogs?tag=a/b", wantStatus: http.StatusBadRequest, wantCode: "tag.invalid"},
+		{name: "tagovi", method: http.MethodGet, path: "/tags", wantStatus: http.StatusOK},
+		{name: "popularni tagovi", method: http.MethodGet, path: "/tags/trending?window=30d&limit=5", wantStatus: http.StatusOK},
+		{name: "popularni tagovi sa neispravnim prozorom", method: http.MethodGet, path: "/tags/trending?window=nedelja", wantStatus: http.StatusBadRequest, wantCode: "request.invalid_window"},
+		{name: "blog o turi", method: http.MethodPost, path: "/blogs?user=ana", body: `{"title":"Naslov","description":"Opis","tourId":7,"keyPointIds":[1,2,1]}`, wantStatus: http.StatusCreated},
+		{name: "nepostojeća tura", method: http.MethodPost, path: "/blogs?user=ana", body: `{"title":"Naslov","description":"Opis","tourId":8}`, wantStatus: http.StatusBadRequest, wantCode: "tour.not_found"},
+		{name: "ključna tačka sa druge ture", method: http.MethodPost, path: "/blogs?user=ana", body: `{"title":"Naslov","description":"Opis","tourId":7,"keyPointIds":[3]}`, wantStatus: http.StatusBadRequest, wantCode: "tour.keypoint_not_found"},
+		{name: "ključne tačke bez ture", method: http.MethodPost, path: "/blogs?user=ana", body: `{"title":"Naslov","description":"Opis","keyPointIds":[1]}`, wantStatus: http.StatusBadRequest, wantCode: "tour.keypoints_without_tour"},
+		{name: "blogovi ture", method: http.MethodGet, path: "/tours/7/blogs", wantStatus: http.StatusOK},
+		{name: "blogovi ture sa neispravnim ID-jem", method: http.MethodGet, path: "/tours/abc/blogs", wantStatus: http.StatusBadRequest, wantCode: "tour.invalid_id"},
+		{name: "odbijen sadržaj", method: http.MethodPost, path: "/blogs?user=ana", body: `{"title":"Najbolji KAZINO","description":"Opis"}`, wantStatus: http.StatusBadRequest, wantCode: "content.rejected"},
+		{name: "zadržan sadržaj", method: http.MethodPost, path: "/blogs?user=ana", body: `{"title":"Naslov","description":"https://a.example.com i https://b.example.com"}`, wantStatus: http.StatusAccepted},
+		{name: "blog po ID-ju", method: http.MethodGet, path: "/blogs/{id}", wantStatus: http.StatusOK},
+		{name: "nepostojeći blog", method: http.MethodGet, path: "/blogs/" + missingBlogID, wantStatus: http.StatusNotFound, wantCode: "blog.not_found"},
+		{name: "izmena bez If-Match", method: http.MethodPut, path: "/blogs/{id}", body: `{"title":"T","description":"D"}`, headers: map[string]string{"X-Username": "ana"}, wantStatus: http.StatusPreconditionRequired, wantCode: "request.precondition_required"},
+		{name: "brisanje sa slabim ETag-om", method: http.MethodDelete, path: "/blogs/{id}", headers: map[string]string{"X-Username": "ana", "If-Match": `W/"0"`}, wantStatus: http.StatusPreconditionFailed, wantCode: "blog.version_mismatch"},
+		{name: "nedozvoljen metod", method: http.MethodPut, path: "/blogs", wantStatus: http.StatusMethodNotAllowed},
+		{name: "lajk", method: http.MethodPost, path: "/blogs/like?id={id}&user=marko", wantStatus: http.StatusOK},
+		{name: "lajk bez korisnika", method: http.MethodPost, path: "/blogs/like?id={id}", wantStatus: http.StatusBadRequest, wantCode: "request.missing_parameter"},
+		{name: "lajk nepostojećeg bloga", method: http.MethodPost, path: "/blogs/like?id=" + missingBlogID + "&user=marko", wantStatus: http.StatusNotFound, wantCode: "blog.not_found"},
+		{name: "lajk sa neispravnim ID-jem", method: http.MethodPost, path: "/blogs/like?id=abc&user=marko", wantStatus: http.StatusBadRequest, wantCode: "blog.invalid_id"},
+		{name: "uklanjanje lajka", method: http.MethodDelete, path: "/blogs/like?id={id}&user=marko", wantStatus: http.StatusOK},
+		{name: "lajkovi", method: http.MethodGet, path: "/blogs/{id}/likes", wantStatus: http.StatusOK},
+		{name: "lajkovi sa neispravnim limitom", method: http.MethodGet, path: "/blogs/{id}/likes?limit=x", wantStatus: http.StatusBadRequest, wantCode: "request.invalid_pagination"},
+		{name: "lajkovi nepostojećeg bloga", method: http.MethodGet, path: "/blogs/" + missingBlogID + "/likes", wantStatus: http.StatusNotFound, wantCode: "blog.not_found"},
+		{name: "tipovi reakcija", method: http.MethodGet, path: "/blogs/reactions", wantStatus: http.StatusOK},
+		{name: "reakcija", method: http.MethodPut, path: "/blogs/{id}/reactions?user=marko", body: `{"type":"love"}`, wantStatus: http.StatusOK},
+		{name: "nepoznata reakcija", method: http.MethodPut, path: "/blogs/{id}/reactions?user=marko", body: `{"type":"angry"}`, wantStatus: http.StatusBadRequest, wantCode: "reaction.unknown_type"},
+		{name: "reakcija bez korisnika", method: http.MethodPut, path: "/blogs/{id}/reactions", body: `{"type":"love"}`, wantStatus: http.StatusBadRequest, wantCode: "request.missing_parameter"},
+		{name: "uklanjanje reakcije", method: http.MethodDelete, path: "/blogs/{id}/reactions?user=marko", wantStatus: http.StatusNoContent},
+		{name: "reakcije po tipu", method: http.MethodGet, path: "/blogs/{id}/reactions?type=insightful", wantStatus: http.StatusOK},
+		{name: "reakcije nepoznatog tipa", method: http.MethodGet, path: "/blogs/{id}/reactions?type=angry", wantStatus: http.StatusBadRequest, wantCode: "reaction.unknown_type"},
+		{name: "reakcija na nepostojeći komentar", method: http.MethodPut, path: "/blogs/{id}/comments/nema/reactions?user=marko", body: `{"type":"like"}`, wantStatus: http.StatusNotFound, wantCode: "comment.not_found"},
+		{name: "prijava bloga", method: http.MethodPost, path: "/blogs/{id}/reports?user=marko", body: `{"reason":"spam"}`, wantStatus: http.StatusCreated},
+		{name: "prijava sa nepoznatim razlogom", method: http.MethodPost, path: "/blogs/{id}/reports?user=marko", body: `{"reason":"dosadno"}`, wantStatus: http.StatusBadRequest, wantCode: "request.validation_failed"},
+		{name: "prijava other bez napomene", method: http.MethodPost, path: "/blogs/{id}/reports?user=marko", body: `{"reason":"other"}`, wantStatus: http.StatusBadRequest, wantCode: "report.note_required"},
+		{name: "prijava nepostojećeg komentara", method: http.MethodPost, path: "/blogs/{id}/comments/nema/reports?user=marko", body: `{"reason":"spam"}`, wantStatus: http.StatusNotFound, wantCode: "comment.not_found"},
+		{name: "red za moderaciju bez admina", method: http.MethodGet, path: "/moderation/reports", headers: map[string]string{headerUserRole: "ROLE_TOURIST"}, wantStatus: http.StatusForbidden, wantCode: "auth.admin_only"},
+		{name: "red za moderaciju", method: http.MethodGet, path: "/moderation/reports", headers: map[string]string{headerUserRole: "ROLE_ADMIN"}, wantStatus: http.StatusOK},
+		{name: "red sa neispravnim statusom", method: http.MethodGet, path: "/moderation/reports?status=x", headers: map[string]string{headerUserRole: "ADMIN"}, wantStatus: http.StatusBadRequest, wantCode: "report.invalid_status"},
+		{name: "akcija nad nepostojećom prijavom", method: http.MethodPost, path: "/moderation/reports/" + missingBlogID + "/actions", body: `{"action":"hide"}`, headers: map[string]string{headerUserRole: "ADMIN", headerUsername: "admin"}, wantStatus: http.StatusNotFound, wantCode: "report.not_found"},
+		{name: "nepoznata akcija", method: http.MethodPost, path: "/moderation/reports/" + missingBlogID + "/actions", body: `{"action":"ban"}`, headers: map[string]string{headerUserRole: "ADMIN", headerUsername: "admin"}, wantStatus: http.StatusBadRequest, wantCode: "request.validation_failed"},
+		{name: "komentar", method: http.MethodPost, path: "/blogs/comment?id={id}&user=marko", body: `{"text":"Odlično"}`, wantStatus: http.StatusCreated},
+		{name: "autor komentara iz tela", method: http.MethodPost, path: "/blogs/comment?id={id}&user=marko", body: `{"userId":"ana","text":"Odlično"}`, wantStatus: http.StatusBadRequest, wantCode: "request.validation_failed"},
+		{name: "prazan komentar", method: http.MethodPost, path: "/blogs/comment?id={id}&user=marko", body: `{"text":""}`, wantStatus: http.StatusBadRequest, wantCode: "request.validation_failed"},
+		{name: "komentar na nepostojeći blog", method: http.MethodPost, path: "/blogs/comment?id=" + missingBlogID + "&user=marko", body: `{"text":"Odlično"}`, wantStatus: http.StatusNotFound, wantCode: "blog.not_found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux, _, blogID := newTestServer(t)
+
+			path := strings.ReplaceAll(tt.path, "{id}", blogID)
+			req := httptest.NewRequest(tt.method, path, strings.NewReader(tt.body))
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, očekivano %d; telo: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			var p problem.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+				t.Fatalf("odgovor nije problem+json: %v", err)
+			}
+			if p.Code != tt.wantCode {
+				t.Errorf("code = %q, očekivano %q", p.Code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestCreateRendersMarkdown(t *testing.T) {
+	mux, repo, _ := newTestServer(t)
+
+	body := `{"title":"Markdown","description":"**podebljano**"}`
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/blogs?user=ana", strings.NewReader(body)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d", rec.Code)
+	}
+
+	blogs, _ := repo.GetAll("", BlogFilter{})
+	for _, blog := range blogs {
+		if blog.Title != "Markdown" {
+			continue
+		}
+		if !strings.Contains(blog.Description, "<strong>podebljano</strong>") {
+			t.Errorf("opis nije renderovan: %q", blog.Description)
+		}
+		if blog.CreatedAt.IsZero() {
+			t.Error("CreatedAt nije postavljen")
+		}
+		return
+	}
+	t.Fatal("blog nije sačuvan")
+}
+
+func TestLikeIsIdempotent(t *testing.T) {
+	mux, repo, blogID := newTestServer(t)
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/blogs/like?id="+blogID+"&user=marko", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d", rec.Code)
+		}
+	}
+
+	blogs, _ := newTestService(repo, nil).GetAll("marko", BlogFilter{})
+	if blogs[0].LikeCount != 1 || !blogs[0].LikedByMe {
+		t.Errorf("likeCount = %d, likedByMe = %t; očekivano 1, true", blogs[0].LikeCount, blogs[0].LikedByMe)
+	}
+}
+
+func TestGetLikesPaginates(t *testing.T) {
+	mux, repo, blogID := newTestServer(t)
+	for _, user := range []string{"a", "b", "c"} {
+		if err := repo.SetReaction(Target{BlogID: blogID}, user, ReactionLike); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		query     string
+		wantUsers string
+	}{
+		{"", `["a","b","c"]`},
+		{"?limit=2", `["a","b"]`},
+		{"?offset=2&limit=2", `["c"]`},
+		{"?offset=5", `[]`},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/blogs/"+blogID+"/likes"+tt.query, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d", tt.query, rec.Code)
+		}
+
+		var page struct {
+			Total int             `json:"total"`
+			Users json.RawMessage `json:"users"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != 3 || string(page.Users) != tt.wantUsers {
+			t.Errorf("%s: total = %d, users = %s; očekivano 3, %s", tt.query, page.Total, page.Users, tt.wantUsers)
+		}
+	}
+}
+
+func TestListHidesLikesAndReportsLikedByMe(t *testing.T) {
+	mux, repo, blogID := newTestServer(t)
+	if err := repo.SetReaction(Target{BlogID: blogID}, "marko", ReactionLike); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/blogs", nil)
+	req.Header.Set(headerUsername, "marko")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	var blogs []map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &blogs); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := blogs[0]["likes"]; ok {
+		t.Error("lista ne sme da sadrži niz likes")
+	}
+	if blogs[0]["likedByMe"] != true || blogs[0]["likeCount"] != float64(1) {
+		t.Errorf("likedByMe = %v, likeCount = %v", blogs[0]["likedByMe"], blogs[0]["likeCount"])
+	}
+}
+
+func TestReactionSwitchKeepsOnePerUser(t *testing.T) {
+	mux, repo, blogID := newTestServer(t)
+
+	for _, reactionType := range []string{"like", "love", "love"} {
+		req := httptest.NewRequest(http.MethodPut, "/blogs/"+blogID+"/reactions", strings.NewReader(`{"type":"`+reactionType+`"}`))
+		req.Header.Set(headerUsername, "marko")
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d; telo: %s", reactionType, rec.Code, rec.Body.String())
+		}
+	}
+
+	blogs, _ := newTestService(repo, nil).GetAll("marko", BlogFilter{})
+	blog := blogs[0]
+	if blog.ReactionCounts["love"] != 1 || blog.ReactionCounts["like"] != 0 || blog.MyReaction != "love" {
+		t.Errorf("reactionCounts = %v, myReaction = %q; očekivano love:1, love", blog.ReactionCounts, blog.MyReaction)
+	}
+	if blog.LikedByMe || blog.LikeCount != 0 {
+		t.Errorf("likedByMe = %t, likeCount = %d; očekivano false, 0", blog.LikedByMe, blog.LikeCount)
+	}
+}
+
+func TestCommentReaction(t *testing.T) {
+	mux, repo, blogID := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/blogs/comment?id="+blogID+"&user=marko", strings.NewReader(`{"text":"Odlično"}`)))
+	var comment Comment
+	if err := json.Unmarshal(rec.Body.Bytes(), &comment); err != nil || comment.ID == "" {
+		t.Fatalf("komentar bez ID-ja: %s", rec.Body.String())
+	}
+
+	req := httptest.NewRequest(http.MethodPut, "/blogs/"+blogID+"/comments/"+comment.ID+"/reactions", strings.NewReader(`{"type":"insightful"}`))
+	req.Header.Set(headerUsername, "ana")
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d; telo: %s", rec.Code, rec.Body.String())
+	}
+
+	blogs, _ := repo.GetAll("ana", BlogFilter{})
+	got := blogs[0].Comments[0]
+	if got.ReactionCounts["insightful"] != 1 || got.MyReaction != "insightful" {
+		t.Errorf("reactionCounts = %v, myReaction = %q", got.ReactionCounts, got.MyReaction)
+	}
+	if blogs[0].ReactionCounts["insightful"] != 0 {
+		t.Error("reakcija na komentar ne sme da menja brojače bloga")
+	}
+}
+
+func TestTagsAreNormalizedAndCounted(t *testing.T) {
+	mux, repo, _ := newTestServer(t)
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	if _, err := repo.Create(Blog{Author: "ana", Title: "Stari", CreatedAt: old, Tags: []string{"zlatibor"}}); err != nil {
+		t.Fatal(err)
+	}
+	// Zakazan pre mesec dana, objavljen juče: u trendu je po vremenu objave.
+	yesterday := time.Now().Add(-24 * time.Hour)
+	if _, err := repo.Create(Blog{Author: "ana", Title: "Zakazan", CreatedAt: old, PublishAt: &yesterday, Tags: []string{"zlatibor"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tags := range []string{`["Zlatibor","#Stara Planina","stara_planina"]`, `["zlatibor"]`} {
+		rec := httptest.NewRecorder()
+		body := `{"title":"Naslov","description":"Opis","tags":` + tags + `}`
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/blogs?user=ana", strings.NewReader(body)))
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d; telo: %s", rec.Code, rec.Body.String())
+		}
+	}
+
+	get := func(path string, dst any) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d", path, rec.Code)
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), dst); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var blogs []Blog
+	get("/blogs?tag=Stara%20Planina", &blogs)
+	if len(blogs) != 1 || strings.Join(blogs[0].Tags, ",") != "zlatibor,stara-planina" {
+		t.Errorf("?tag=Stara Planina = %+v", blogs)
+	}
+
+	var tags []TagCount
+	get("/tags", &tags)
+	want := []TagCount{{Tag: "zlatibor", Count: 4}, {Tag: "stara-planina", Count: 1}}
+	if !slices.Equal(tags, want) {
+		t.Errorf("/tags = %v, očekivano %v", tags, want)
+	}
+
+	get("/tags/trending?window=7d", &tags)
+	want = []TagCount{{Tag: "zlatibor", Count: 3}, {Tag: "stara-planina", Count: 1}}
+	if !slices.Equal(tags, want) {
+		t.Errorf("/tags/trending = %v, očekivano %v", tags, want)
+	}
+}
+
+func TestTourBlogsAndUnlink(t *testing.T) {
+	repo := NewMemoryRepository()
+	moderation := NewModerationService(repo, NewMemoryReportRepository())
+	service := newTestService(repo, moderation)
+	mux := http.NewServeMux()
+	NewHandler(service, moderation).RegisterRoutes(mux)
+
+	body := `{"title":"Izveštaj","description":"Opis","tourId":7,"keyPointIds":[2,2]}`
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/blogs?user=ana", strings.NewReader(body)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d; telo: %s", rec.Code, rec.Body.String())
+	}
+
+	tourBlogs := func() []Blog {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tours/7/blogs", nil))
+		var blogs []Blog
+		if err := json.Unmarshal(rec.Body.Bytes(), &blogs); err != nil {
+			t.Fatal(err)
+		}
+		return blogs
+	}
+
+	blogs := tourBlogs()
+	if len(blogs) != 1 || !slices.Equal(blogs[0].KeyPointIDs, []int64{2}) {
+		t.Fatalf("/tours/7/blogs = %+v", blogs)
+	}
+
+	if n, err := service.UnlinkTour(testTourID); err != nil || n != 1 {
+		t.Fatalf("UnlinkTour = %d, %v", n, err)
+	}
+	if blogs := tourBlogs(); len(blogs) != 0 {
+		t.Errorf("posle brisanja ture /tours/7/blogs = %+v", blogs)
+	}
+	all, _ := repo.GetAll("", BlogFilter{})
+	if len(all) != 1 || all[0].TourID != 0 || all[0].KeyPointIDs != nil {
+		t.Errorf("blog posle brisanja ture = %+v", all)
+	}
+}
+
+func TestModerationFlow(t *testing.T) {
+	mux, repo, blogID := newTestServer(t)
+
+	do := func(method, path, user, role, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(headerUsername, user)
+		req.Header.Set(headerUserRole, role)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+	queue := func(status string) ReportPage {
+		t.Helper()
+		var page ReportPage
+		rec := do(http.MethodGet, "/moderation/reports?status="+status, "admin", "ROLE_ADMIN", "")
+		if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+			t.Fatal(err)
+		}
+		return page
+	}
+
+	var comment Comment
+	rec := do(http.MethodPost, "/blogs/comment?id="+blogID, "marko", "", `{"text":"Uvreda"}`)
+	json.Unmarshal(rec.Body.Bytes(), &comment)
+
+	commentReports := "/blogs/" + blogID + "/comments/" + comment.ID + "/reports"
+	for _, user := range []string{"ana", "petar"} {
+		if rec := do(http.MethodPost, commentReports, user, "", `{"reason":"harassment"}`); rec.Code != http.StatusCreated {
+			t.Fatalf("prijava (%s): status = %d; telo: %s", user, rec.Code, rec.Body.String())
+		}
+	}
+	if rec := do(http.MethodPost, commentReports, "ana", "", `{"reason":"spam"}`); rec.Code != http.StatusConflict {
+		t.Errorf("ponovljena prijava: status = %d, očekivano 409", rec.Code)
+	}
+
+	open := queue(ReportOpen)
+	if open.Total != 2 || open.Reports[0].ContentAuthor != "marko" {
+		t.Fatalf("otvorene prijave = %+v", open)
+	}
+
+	rec = do(http.MethodPost, "/moderation/reports/"+open.Reports[0].ID+"/actions", "admin", "ROLE_ADMIN", `{"action":"hide","note":"uvreda"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("hide: status = %d; telo: %s", rec.Code, rec.Body.String())
+	}
+	if open := queue(ReportOpen); open.Total != 0 {
+		t.Errorf("posle hide ostalo je %d otvorenih prijava", open.Total)
+	}
+	resolved := queue(ReportResolved)
+	for _, report := range resolved.Reports {
+		if report.Resolution == nil || report.Resolution.Action != ActionHide || report.Resolution.ModeratorID != "admin" || report.Resolution.At.IsZero() {
+			t.Errorf("zatvorena prijava = %+v", report)
+		}
+	}
+	if rec := do(http.MethodPost, "/moderation/reports/"+open.Reports[0].ID+"/actions", "admin", "ROLE_ADMIN", `{"action":"dismiss"}`); rec.Code != http.StatusConflict {
+		t.Errorf("akcija nad zatvorenom prijavom: status = %d, očekivano 409", rec.Code)
+	}
+
+	var blogs []Blog
+	json.Unmarshal(do(http.MethodGet, "/blogs", "", "", "").Body.Bytes(), &blogs)
+	if len(blogs[0].Comments) != 0 || blogs[0].CommentCount != 0 {
+		t.Errorf("sakriven komentar je i dalje u listi: %+v", blogs[0].Comments)
+	}
+	if rec := do(http.MethodPost, commentReports, "jovan", "", `{"reason":"spam"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("prijava sakrivenog komentara: status = %d, očekivano 404", rec.Code)
+	}
+
+	hiddenID, err := repo.Create(Blog{Author: "marko", Title: "Drugi", Description: "tekst", Tags: []string{"planine"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	do(http.MethodPost, "/blogs/"+hiddenID+"/reports", "ana", "", `{"reason":"spam"}`)
+	if rec := do(http.MethodPost, "/moderation/reports/"+queue(ReportOpen).Reports[0].ID+"/actions", "admin", "ROLE_ADMIN", `{"action":"hide"}`); rec.Code != http.StatusOK {
+		t.Fatalf("hide bloga: status = %d; telo: %s", rec.Code, rec.Body.String())
+	}
+	for _, path := range []string{"/tags", "/tags/trending"} {
+		var tags []TagCount
+		json.Unmarshal(do(http.MethodGet, path, "", "", "").Body.Bytes(), &tags)
+		if len(tags) != 0 {
+			t.Errorf("%s broji tagove sakrivenog bloga: %+v", path, tags)
+		}
+	}
+
+	do(http.MethodPost, "/blogs/"+blogID+"/reports", "ana", "", `{"reason":"spam"}`)
+	report := queue(ReportOpen).Reports[0]
+	if rec := do(http.MethodPost, "/moderation/reports/"+report.ID+"/actions", "admin", "ADMIN", `{"action":"delete"}`); rec.Code != http.StatusOK {
+		t.Fatalf("delete: status = %d; telo: %s", rec.Code, rec.Body.String())
+	}
+	json.Unmarshal(do(http.MethodGet, "/blogs", "", "", "").Body.Bytes(), &blogs)
+	if len(blogs) != 0 {
+		t.Errorf("obrisan blog je i dalje u listi: %+v", blogs)
+	}
+}
+
+func TestContentFilter(t *testing.T) {
+	mux, repo, blogID := newTestServer(t)
+
+	do := func(method, path, user, role, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(headerUsername, user)
+		req.Header.Set(headerUserRole, role)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var comment Comment
+	rec := do(http.MethodPost, "/blogs/comment?id="+blogID, "marko", "", `{"text":"Ti si budala."}`)
+	json.Unmarshal(rec.Body.Bytes(), &comment)
+	if rec.Code != http.StatusCreated || comment.Text != "Ti si ******." {
+		t.Errorf("maskiran komentar: status = %d, tekst %q", rec.Code, comment.Text)
+	}
+	if rec := do(http.MethodPost, "/blogs/comment?id="+blogID, "marko", "", `{"text":"kazino"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("odbijen komentar: status = %d, očekivano 400", rec.Code)
+	}
+	if activity, _ := repo.Activity("marko"); activity.Posts != 1 || activity.FirstSeenAt.IsZero() {
+		t.Errorf("aktivnost = %+v, očekivana jedna objava", activity)
+	}
+
+	rec = do(http.MethodPost, "/blogs", "jovan", "", `{"title":"Ponuda","description":"www.a.example.com www.b.example.com"}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("zadržan blog: status = %d; telo: %s", rec.Code, rec.Body.String())
+	}
+	listed := func() int {
+		t.Helper()
+		var blogs []Blog
+		json.Unmarshal(do(http.MethodGet, "/blogs", "", "", "").Body.Bytes(), &blogs)
+		return len(blogs)
+	}
+	if n := listed(); n != 1 {
+		t.Errorf("zadržan blog je u listi: %d blogova", n)
+	}
+
+	var page ReportPage
+	json.Unmarshal(do(http.MethodGet, "/moderation/reports", "admin", "ROLE_ADMIN", "").Body.Bytes(), &page)
+	if page.Total != 1 || page.Reports[0].Reason != ReasonAutomated || page.Reports[0].ReporterID != SystemReporter || page.Reports[0].ContentAuthor != "jovan" {
+		t.Fatalf("red za moderaciju = %+v", page)
+	}
+	if rec := do(http.MethodPost, "/moderation/reports/"+page.Reports[0].ID+"/actions", "admin", "ROLE_ADMIN", `{"action":"approve"}`); rec.Code != http.StatusOK {
+		t.Fatalf("approve: status = %d; telo: %s", rec.Code, rec.Body.String())
+	}
+	if n := listed(); n != 2 {
+		t.Errorf("odobren blog nije u listi: %d blogova", n)
+	}
+}
+
+// Servis bez opcionih zavisnosti čuva blogove, a ono što bez njih ne može
+// da proveri odbija umesto da padne.
+func TestServiceWithoutOptions(t *testing.T) {
+	repo := NewMemoryRepository()
+	service := NewService(repo, NewMemoryRevisionRepository(), ServiceOptions{})
+	blog, err := service.Create(Blog{Author: "ana", Title: "Naslov", Description: "Sa @marko"})
+	if err != nil || len(blog.Mentions) != 0 {
+		t.Fatalf("Create = %+v, %v", blog, err)
+	}
+
+	var domainErr *Error
+	_, err = service.Create(Blog{Author: "ana", Title: "Tura", Description: "d", TourID: testTourID})
+	if !errors.As(err, &domainErr) || domainErr.Code != "tour.service_unavailable" {
+		t.Errorf("blog sa turom bez kataloga: %v", err)
+	}
+
+	service = NewService(repo, NewMemoryRevisionRepository(), ServiceOptions{
+		Filter: contentfilter.NewPipeline(contentfilter.NewLinkLimitRule("linkovi", contentfilter.Hold, nil, 0)),
+	})
+	_, err = service.Create(Blog{Author: "ana", Title: "Link", Description: "https://example.com"})
+	if !errors.As(err, &domainErr) || domainErr.Code != "content.rejected" {
+		t.Errorf("zadržan sadržaj bez moderacije: %v", err)
+	}
+}
+
+func TestConditionalRequests(t *testing.T) {
+	mux, _, blogID := newTestServer(t)
+	path := "/blogs/" + blogID
+
+	do := func(method, path, user string, headers map[string]string, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(headerUsername, user)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(http.MethodGet, path, "marko", nil, "")
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" {
+		t.Fatalf("GET: status = %d, ETag = %q", rec.Code, etag)
+	}
+	if rec := do(http.MethodGet, path, "marko", map[string]string{"If-None-Match": etag}, ""); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("If-None-Match: status = %d, telo %q", rec.Code, rec.Body.String())
+	}
+
+	list := do(http.MethodGet, "/blogs", "marko", nil, "")
+	if rec := do(http.MethodGet, "/blogs", "marko", map[string]string{"If-None-Match": list.Header().Get("ETag")}, ""); rec.Code != http.StatusNotModified {
+		t.Errorf("lista sa If-None-Match: status = %d", rec.Code)
+	}
+
+	// Lajk je upis u blog, pa stari ETag više ne važi.
+	do(http.MethodPost, "/blogs/like?id="+blogID+"&user=marko", "", nil, "")
+	if rec := do(http.MethodGet, path, "marko", map[string]string{"If-None-Match": etag}, ""); rec.Code != http.StatusOK || rec.Header().Get("ETag") == etag {
+		t.Fatalf("posle lajka: status = %d, ETag = %q", rec.Code, rec.Header().Get("ETag"))
+	}
+	if rec := do(http.MethodGet, "/blogs", "marko", map[string]string{"If-None-Match": list.Header().Get("ETag")}, ""); rec.Code != http.StatusOK {
+		t.Errorf("lista posle lajka: status = %d", rec.Code)
+	}
+
+	update := `{"title":"Novi naslov","description":"**Novi** opis"}`
+	if rec := do(http.MethodPut, path, "ana", nil, update); rec.Code != http.StatusPreconditionRequired {
+		t.Errorf("izmena bez If-Match: status = %d, očekivano 428", rec.Code)
+	}
+	if rec := do(http.MethodPut, path, "ana", map[string]string{"If-Match": etag}, update); rec.Code != http.StatusPreconditionFailed || !strings.Contains(rec.Body.String(), "blog.version_mismatch") {
+		t.Errorf("izmena sa starim ETag-om: status = %d, očekivano 412", rec.Code)
+	}
+
+	current := do(http.MethodGet, path, "ana", nil, "").Header().Get("ETag")
+	if rec := do(http.MethodPut, path, "marko", map[string]string{"If-Match": current}, update); rec.Code != http.StatusForbidden {
+		t.Errorf("izmena tuđeg bloga: status = %d, očekivano 403", rec.Code)
+	}
+	rec = do(http.MethodPut, path, "ana", map[string]string{"If-Match": current}, update)
+	var updated Blog
+	json.Unmarshal(rec.Body.Bytes(), &updated)
+	if rec.Code != http.StatusOK || updated.Title != "Novi naslov" || !strings.Contains(updated.Description, "<strong>Novi</strong>") || rec.Header().Get("ETag") == current {
+		t.Fatalf("izmena: status = %d, blog %+v", rec.Code, updated)
+	}
+	if rec := do(http.MethodGet, path, "ana", nil, ""); rec.Header().Get("ETag") != blogETag(updated) || rec.Header().Get("Last-Modified") == "" {
+		t.Errorf("GET posle izmene: ETag = %q, očekivano %q", rec.Header().Get("ETag"), blogETag(updated))
+	}
+
+	if rec := do(http.MethodDelete, path, "ana", map[string]string{"If-Match": current}, ""); rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("brisanje sa starim ETag-om: status = %d, očekivano 412", rec.Code)
+	}
+	if rec := do(http.MethodDelete, path, "ana", map[string]string{"If-Match": blogETag(updated)}, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("brisanje: status = %d; telo: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodGet, path, "ana", nil, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("GET obrisanog bloga: status = %d", rec.Code)
+	}
+}
+
+func TestIfMatchAnyAndListLastModified(t *testing.T) {
+	mux, repo, blogID := newTestServer(t)
+	old := time.Now().Add(-time.Hour)
+	if _, err := repo.Create(Blog{Author: "marko", Title: "Drugi", Description: "<p>tekst</p>", UpdatedAt: old}); err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method, path, user string, headers map[string]string, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(headerUsername, user)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	modified := do(http.MethodGet, "/blogs", "ana", nil, "").Header().Get("Last-Modified")
+	if modified != old.UTC().Format(http.TimeFormat) {
+		t.Fatalf("Last-Modified liste = %q, očekivano %q", modified, old.UTC().Format(http.TimeFormat))
+	}
+
+	wildcard := map[string]string{"If-Match": "*"}
+	if rec := do(http.MethodPut, "/blogs/"+blogID, "ana", wildcard, `{"title":"Novi naslov","description":"opis"}`); rec.Code != http.StatusOK {
+		t.Fatalf("izmena sa If-Match: *: status = %d; telo: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodDelete, "/blogs/"+blogID, "ana", wildcard, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("brisanje sa If-Match: *: status = %d; telo: %s", rec.Code, rec.Body.String())
+	}
+
+	// Obrisan blog nestaje iz liste, pa keširana lista više ne važi.
+	rec := do(http.MethodGet, "/blogs", "ana", map[string]string{"If-Modified-Since": modified}, "")
+	if rec.Code != http.StatusOK || rec.Header().Get("Last-Modified") == modified {
+		t.Errorf("lista posle brisanja: status = %d, Last-Modified = %q", rec.Code, rec.Header().Get("Last-Modified"))
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	mux, _, _ := newTestServer(t)
+
+	do := func(method, path, user string, headers map[string]string, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(headerUsername, user)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	var blog Blog
+	rec := do(http.MethodPost, "/blogs", "ana", nil, `{"title":"Tara","description":"Prvi red\nDrugi red"}`)
+	json.Unmarshal(rec.Body.Bytes(), &blog)
+	path := "/blogs/" + blog.ID
+
+	rec = do(http.MethodPut, path, "ana", map[string]string{"If-Match": blogETag(blog)}, `{"title":"Tara i Drina","description":"Prvi red\nTreći red"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("izmena: status = %d; telo: %s", rec.Code, rec.Body.String())
+	}
+	json.Unmarshal(rec.Body.Bytes(), &blog)
+
+	var page RevisionPage
+	json.Unmarshal(do(http.MethodGet, path+"/revisions", "ana", nil, "").Body.Bytes(), &page)
+	if page.Total != 2 || page.Revisions[0].Number != 2 || page.Revisions[0].Editor != "ana" || page.Revisions[0].Markdown != "" {
+		t.Fatalf("istorija = %+v", page)
+	}
+	if rec := do(http.MethodGet, path+"/revisions", "marko", nil, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("istorija tuđeg bloga: status = %d, očekivano 403", rec.Code)
+	}
+
+	var diff RevisionDiff
+	json.Unmarshal(do(http.MethodGet, path+"/revisions/diff", "ana", nil, "").Body.Bytes(), &diff)
+	want := []DiffLine{{"=", "Prvi red"}, {"-", "Drugi red"}, {"+", "Treći red"}}
+	if diff.From != 1 || diff.To != 2 || diff.ToTitle != "Tara i Drina" || !slices.Equal(diff.Lines, want) {
+		t.Errorf("diff = %+v", diff)
+	}
+
+	if rec := do(http.MethodPost, path+"/revisions/1/restore", "ana", map[string]string{"If-Match": `"1"`}, ""); rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("vraćanje sa starim ETag-om: status = %d, očekivano 412", rec.Code)
+	}
+	rec = do(http.MethodPost, path+"/revisions/1/restore", "ana", map[string]string{"If-Match": blogETag(blog)}, "")
+	json.Unmarshal(rec.Body.Bytes(), &blog)
+	if rec.Code != http.StatusOK || blog.Title != "Tara" || !strings.Contains(blog.Description, "Drugi red") {
+		t.Fatalf("vraćanje: status = %d, blog %+v", rec.Code, blog)
+	}
+
+	var rev Revision
+	json.Unmarshal(do(http.MethodGet, path+"/revisions/3", "ana", nil, "").Body.Bytes(), &rev)
+	if rev.RestoredFrom != 1 || rev.Markdown != "Prvi red\nDrugi red" {
+		t.Errorf("revizija 3 = %+v", rev)
+	}
+	if rec := do(http.MethodGet, path+"/revisions/9", "ana", nil, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("nepostojeća revizija: status = %d", rec.Code)
+	}
+}
+
+// recordingPublisher pamti poslate događaje; dok je fail postavljen, vraća grešku.
+type recordingPublisher struct {
+	keys   []string
+	events []any
+	fail   error
+}
+
+func (p *recordingPublisher) Publish(routingKey string, event any) error {
+	if p.fail != nil {
+		return p.fail
+	}
+	p.keys = append(p.keys, routingKey)
+	p.events = append(p.events, event)
+	return nil
+}
+
+func TestScheduledPublishing(t *testing.T) {
+	mux, repo, _ := newTestServer(t)
+
+	do := func(method, path, user string, headers map[string]string, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(headerUsername, user)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+	listed := func(user, id string) bool {
+		t.Helper()
+		var blogs []Blog
+		json.Unmarshal(do(http.MethodGet, "/blogs", user, nil, "").Body.Bytes(), &blogs)
+		return slices.ContainsFunc(blogs, func(b Blog) bool { return b.ID == id })
+	}
+
+	past := time.Now().Add(-time.Hour).Format(time.RFC3339)
+	if rec := do(http.MethodPost, "/blogs", "ana", nil, `{"title":"Kasno","description":"d","publishAt":"`+past+`"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("publishAt u prošlosti: status = %d, očekivano 400", rec.Code)
+	}
+
+	publishAt := time.Now().Add(time.Hour)
+	var blog Blog
+	rec := do(http.MethodPost, "/blogs", "ana", nil, `{"title":"Sutra","description":"d","tags":["Tara"],"publishAt":"`+publishAt.Format(time.RFC3339)+`"}`)
+	json.Unmarshal(rec.Body.Bytes(), &blog)
+	if rec.Code != http.StatusCreated || !blog.Scheduled || blog.PublishAt == nil {
+		t.Fatalf("zakazan blog: status = %d, blog %+v", rec.Code, blog)
+	}
+	path := "/blogs/" + blog.ID
+
+	if listed("", blog.ID) || listed("marko", blog.ID) || !listed("ana", blog.ID) {
+		t.Error("zakazan blog sme da vidi samo autor")
+	}
+	if rec := do(http.MethodGet, path, "marko", nil, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("zakazan blog za drugog korisnika: status = %d, očekivano 404", rec.Code)
+	}
+	if tags, _ := repo.TagCounts(10); len(tags) != 0 {
+		t.Errorf("tagovi zakazanog bloga se broje: %+v", tags)
+	}
+
+	// Izmena bez publishAt zadržava zakazano vreme.
+	rec = do(http.MethodPut, path, "ana", map[string]string{"If-Match": blogETag(blog)}, `{"title":"Sutra ujutru","description":"Sa @marko"}`)
+	json.Unmarshal(rec.Body.Bytes(), &blog)
+	if rec.Code != http.StatusOK || !blog.Scheduled {
+		t.Fatalf("izmena zakazanog bloga: status = %d, blog %+v", rec.Code, blog)
+	}
+
+	events := &recordingPublisher{fail: errors.New("broker nedostupan")}
+	scheduler := NewPublishScheduler(repo, events)
+	if n, _ := scheduler.PublishDue(time.Now()); n != 0 {
+		t.Errorf("objavljeno pre vremena: %d", n)
+	}
+
+	// Blog je objavljen i kada događaj ne prođe; šalje se posle isteka zakupa.
+	due := publishAt.Add(time.Minute)
+	if _, err := scheduler.PublishDue(due); err == nil {
+		t.Error("greška brokera nije vraćena")
+	}
+	if !listed("marko", blog.ID) {
+		t.Error("blog nije objavljen")
+	}
+	events.fail = nil
+	if n, _ := scheduler.PublishDue(due); n != 0 {
+		t.Errorf("događaj poslat pre isteka zakupa: %d", n)
+	}
+	if n, err := scheduler.PublishDue(due.Add(DefaultPublishLease)); n != 1 || err != nil {
+		t.Fatalf("ponovno slanje: n = %d, err = %v", n, err)
+	}
+	event, _ := events.events[0].(BlogPublished)
+	if events.keys[0] != RoutingKeyBlogPublished || event.BlogID != blog.ID || event.Title != "Sutra ujutru" {
+		t.Errorf("događaj = %s %+v", events.keys[0], events.events[0])
+	}
+	// Pomen iz zakazanog bloga stiže tek sa objavom.
+	if mention, _ := events.events[len(events.events)-1].(BlogActivity); !slices.Equal(events.keys, []string{RoutingKeyBlogPublished, RoutingKeyBlogMentioned}) || mention.Mentioned != "marko" {
+		t.Errorf("događaji = %v, pomen %+v", events.keys, mention)
+	}
+	if n, _ := scheduler.PublishDue(due.Add(time.Hour)); n != 0 {
+		t.Errorf("blog objavljen dvaput: %d", n)
+	}
+
+	json.Unmarshal(do(http.MethodGet, path, "ana", nil, "").Body.Bytes(), &blog)
+	if rec := do(http.MethodPut, path, "ana", map[string]string{"If-Match": blogETag(blog)}, `{"title":"t","description":"d","publishAt":"`+time.Now().Add(time.Hour).Format(time.RFC3339)+`"}`); rec.Code != http.StatusConflict {
+		t.Errorf("novo vreme objave objavljenog bloga: status = %d, očekivano 409", rec.Code)
+	}
+}
+
+func TestTrash(t *testing.T) {
+	mux, repo, blogID := newTestServer(t)
+
+	do := func(method, path, user string, headers map[string]string, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(headerUsername, user)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+	getBlog := func() (Blog, int) {
+		t.Helper()
+		var blog Blog
+		rec := do(http.MethodGet, "/blogs/"+blogID, "ana", nil, "")
+		json.Unmarshal(rec.Body.Bytes(), &blog)
+		return blog, rec.Code
+	}
+
+	var comment Comment
+	json.Unmarshal(do(http.MethodPost, "/blogs/comment?id="+blogID, "marko", nil, `{"text":"Lepo"}`).Body.Bytes(), &comment)
+	commentPath := "/blogs/" + blogID + "/comments/" + comment.ID
+
+	if rec := do(http.MethodDelete, commentPath, "jova", nil, ""); rec.Code != http.StatusForbidden {
+		t.Errorf("brisanje tuđeg komentara: status = %d, očekivano 403", rec.Code)
+	}
+	if rec := do(http.MethodDelete, commentPath, "marko", nil, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("brisanje komentara: status = %d; telo: %s", rec.Code, rec.Body.String())
+	}
+	if blog, _ := getBlog(); len(blog.Comments) != 0 || blog.CommentCount != 0 {
+		t.Errorf("obrisan komentar je vidljiv: %+v", blog)
+	}
+	if rec := do(http.MethodPut, commentPath+"/reactions", "ana", nil, `{"type":"like"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("reakcija na obrisan komentar: status = %d, očekivano 404", rec.Code)
+	}
+
+	var trash Trash
+	json.Unmarshal(do(http.MethodGet, "/me/trash", "marko", nil, "").Body.Bytes(), &trash)
+	if len(trash.Comments) != 1 || trash.Comments[0].Comment.ID != comment.ID || trash.Comments[0].BlogTitle != "Prvi" {
+		t.Fatalf("korpa = %+v", trash)
+	}
+	if rec := do(http.MethodPost, commentPath+"/restore", "ana", nil, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("vraćanje tuđeg brisanja: status = %d, očekivano 404", rec.Code)
+	}
+	if rec := do(http.MethodPost, commentPath+"/restore", "marko", nil, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("vraćanje komentara: status = %d", rec.Code)
+	}
+	blog, _ := getBlog()
+	if len(blog.Comments) != 1 || blog.CommentCount != 1 {
+		t.Errorf("vraćen komentar = %+v", blog)
+	}
+
+	if rec := do(http.MethodDelete, "/blogs/"+blogID, "ana", map[string]string{"If-Match": blogETag(blog)}, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("brisanje bloga: status = %d", rec.Code)
+	}
+	if _, code := getBlog(); code != http.StatusNotFound {
+		t.Errorf("obrisan blog: status = %d, očekivano 404", code)
+	}
+	json.Unmarshal(do(http.MethodGet, "/me/trash", "ana", nil, "").Body.Bytes(), &trash)
+	if len(trash.Blogs) != 1 || trash.Blogs[0].ID != blogID || trash.Blogs[0].DeletedBy != "ana" {
+		t.Fatalf("korpa autora = %+v", trash)
+	}
+	if rec := do(http.MethodPost, "/blogs/"+blogID+"/restore", "ana", nil, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("vraćanje bloga: status = %d", rec.Code)
+	}
+	if blog, code := getBlog(); code != http.StatusOK || len(blog.Comments) != 1 {
+		t.Errorf("vraćen blog: status = %d, blog %+v", code, blog)
+	}
+
+	// Radnik briše samo ono što je u korpi duže od retention, sa istorijom.
+	blog, _ = getBlog()
+	do(http.MethodDelete, "/blogs/"+blogID, "ana", map[string]string{"If-Match": blogETag(blog)}, "")
+	revisions := NewMemoryRevisionRepository()
+	revisions.AddRevision(Revision{BlogID: blogID, Number: 1, Editor: "ana", Title: "Prvi"})
+	worker := NewRetentionWorker(repo, revisions, time.Hour)
+	if n, _ := worker.Purge(time.Now()); n != 0 {
+		t.Errorf("trajno obrisano pre isteka: %d", n)
+	}
+	if n, err := worker.Purge(time.Now().Add(2 * time.Hour)); n != 1 || err != nil {
+		t.Fatalf("Purge = %d, %v", n, err)
+	}
+	if _, err := revisions.LatestRevision(blogID); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("istorija trajno obrisanog bloga: %v", err)
+	}
+	if rec := do(http.MethodPost, "/blogs/"+blogID+"/restore", "ana", nil, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("vraćanje trajno obrisanog bloga: status = %d", rec.Code)
+	}
+}
+
+func TestActivityEvents(t *testing.T) {
+	_, repo, blogID := newTestServer(t)
+	events := &recordingPublisher{}
+	service := newTestService(repo, NewModerationService(repo, NewMemoryReportRepository()))
+	service.events = events
+
+	if err := service.AddLike(blogID, "marko"); err != nil {
+		t.Fatal(err)
+	}
+	if err := service.React(Target{BlogID: blogID}, "jova", "love"); err != nil {
+		t.Fatal(err)
+	}
+	comment, err := service.AddComment(blogID, Comment{UserID: "jova", Text: "Lepo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Zadržan komentar ne stiže do autora dok ga moderator ne odobri.
+	if _, err := service.AddComment(blogID, Comment{UserID: "jova", Text: "http://a.rs http://b.rs"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(events.keys, []string{RoutingKeyBlogLiked, RoutingKeyBlogCommented}) {
+		t.Fatalf("događaji = %v", events.keys)
+	}
+	liked, _ := events.events[0].(BlogActivity)
+	if liked.BlogID != blogID || liked.BlogAuthor != "ana" || liked.BlogTitle != "Prvi" || liked.UserID != "marko" {
+		t.Errorf("blog.liked = %+v", liked)
+	}
+	commented, _ := events.events[1].(BlogActivity)
+	if commented.UserID != "jova" || commented.CommentID != comment.ID {
+		t.Errorf("blog.commented = %+v", commented)
+	}
+}
+
+func TestMentions(t *testing.T) {
+	_, repo, _ := newTestServer(t)
+	events := &recordingPublisher{}
+	service := newTestService(repo, NewModerationService(repo, NewMemoryReportRepository()))
+	service.events = events
+	mentioned := func() []string {
+		t.Helper()
+		var names []string
+		for i, key := range events.keys {
+			if key == RoutingKeyBlogMentioned {
+				names = append(names, events.events[i].(BlogActivity).Mentioned)
+			}
+		}
+		events.keys, events.events = nil, nil
+		return names
+	}
+
+	blog, err := service.Create(Blog{Author: "ana", Title: "Tara", Description: "Pozdrav @marko, @zoran i @nepoznati! Ja sam @ana, a `@jova` je u kodu i [@jova](https://tara.rs) u linku."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Mention{{Username: "marko", UserID: 2}, {Username: "ana", UserID: 1}}; !slices.Equal(blog.Mentions, want) {
+		t.Errorf("pomeni = %+v", blog.Mentions)
+	}
+	for _, html := range []string{`<a href="/profile/2" class="mention">@marko</a>`, `, @zoran i @nepoznati!`, `<code>@jova</code>`, `>@jova</a>`} {
+		if !strings.Contains(blog.Description, html) {
+			t.Errorf("opis ne sadrži %s: %s", html, blog.Description)
+		}
+	}
+	if strings.Contains(blog.Description, "/profile/3") {
+		t.Errorf("pomen u kodu ili linku je link na profil: %s", blog.Description)
+	}
+	// Autor ne obaveštava sam sebe.
+	if got := mentioned(); !slices.Equal(got, []string{"marko"}) {
+		t.Errorf("pomenuti u blogu = %v", got)
+	}
+
+	blog, err = service.Update(Blog{ID: blog.ID, Title: "Tara", Description: "Pozdrav @marko i @jova"}, "ana", blog.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := mentioned(); !slices.Equal(got, []string{"jova"}) {
+		t.Errorf("pomenuti u izmeni = %v", got)
+	}
+
+	comment, err := service.AddComment(blog.ID, Comment{UserID: "jova", Text: "Hvala @marko!"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comment.Mentions) != 1 || comment.Mentions[0].Username != "marko" {
+		t.Errorf("pomeni u komentaru = %+v", comment.Mentions)
+	}
+	if got := mentioned(); !slices.Equal(got, []string{"marko"}) {
+		t.Errorf("pomenuti u komentaru = %v", got)
+	}
+
+	// Adresa nije pomen; obaveštava se samo novi pomenuti.
+	comment, err = service.EditComment(Target{BlogID: blog.ID, CommentID: comment.ID}, "jova", "Hvala @marko i @ana, pišite na jova@mail.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comment.Mentions) != 2 {
+		t.Errorf("pomeni posle izmene = %+v", comment.Mentions)
+	}
+	if got := mentioned(); !slices.Equal(got, []string{"ana"}) {
+		t.Errorf("pomenuti u izmeni komentara = %v", got)
+	}
+	stored, _ := repo.Get(blog.ID, "")
+	if i := commentIndex(stored.Comments, comment.ID); i < 0 || len(stored.Comments[i].Mentions) != 2 {
+		t.Errorf("sačuvan komentar = %+v", stored.Comments)
+	}
+}
+
+func TestMentionsRespectBlocks(t *testing.T) {
+	_, repo, _ := newTestServer(t)
+	service := newTestService(repo, NewModerationService(repo, NewMemoryReportRepository()))
+
+	// Blokada važi u oba smera: ni ana ni luka ne mogu da povežu pomen onog drugog.
+	blog, err := service.Create(Blog{Author: "ana", Title: "Tara", Description: "Pozdrav @luka i @marko"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Mention{{Username: "marko", UserID: 2}}; !slices.Equal(blog.Mentions, want) {
+		t.Errorf("pomeni = %+v", blog.Mentions)
+	}
+	comment, err := service.AddComment(blog.ID, Comment{UserID: "luka", Text: "Hvala @ana i @jova"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Mention{{Username: "jova", UserID: 3}}; !slices.Equal(comment.Mentions, want) {
+		t.Errorf("pomeni u komentaru = %+v", comment.Mentions)
+	}
+}
+
+// unavailableDirectory je service-stakeholders koji ne odgovara.
+type unavailableDirectory struct{}
+
+func (unavailableDirectory) UserID(ctx context.Context, username string) (int64, bool, error) {
+	return 0, false, errors.New("service-stakeholders nije dostupan")
+}
+
+func TestMentionsSurviveUnavailableUsers(t *testing.T) {
+	_, repo, _ := newTestServer(t)
+	service := newTestService(repo, NewModerationService(repo, NewMemoryReportRepository()))
+	service.users = unavailableDirectory{}
+
+	blog, err := service.Create(Blog{Author: "ana", Title: "Tara", Description: "Pozdrav @marko"})
+	if err != nil {
+		t.Fatalf("upis sa nedostupnim korisnicima: %v", err)
+	}
+	if len(blog.Mentions) != 0 || !strings.Contains(blog.Description, "Pozdrav @marko") {
+		t.Errorf("pomen nije ostao tekst: %+v, %s", blog.Mentions, blog.Description)
+	}
+}
+
+type sseEvent struct {
+	id, event, data string
+}
+
+// openStream čita SSE tok bloga i šalje događaje na kanal dok se test ne završi.
+func openStream(t *testing.T, server *httptest.Server, blogID, lastEventID string) <-chan sseEvent {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/blogs/"+blogID+"/events", nil)
+	req.Header.Set(headerUsername, "ana")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status = %d, Content-Type = %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	events := make(chan sseEvent, 16)
+	go func() {
+		defer resp.Body.Close()
+		scanner := bufio.NewScanner(resp.Body)
+		var current sseEvent
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				if current.event != "" {
+					events <- current
+				}
+				current = sseEvent{}
+			case strings.HasPrefix(line, "id: "):
+				current.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				current.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				current.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	return events
+}
+
+func nextEvent(t *testing.T, events <-chan sseEvent) sseEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("događaj nije stigao")
+		return sseEvent{}
+	}
+}
+
+func TestLiveEvents(t *testing.T) {
+	mux, _, blogID := newTestServer(t)
+	server := httptest.NewServer(mux)
+	// Close čeka otvorene tokove, pa se registruje pre otkazivanja iz openStream.
+	t.Cleanup(server.Close)
+
+	do := func(method, path, user, body string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(headerUsername, user)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodGet, "/blogs/"+missingBlogID+"/events", "ana", ""); rec.Code != http.StatusNotFound {
+		t.Errorf("nepostojeći blog: status = %d, očekivano 404", rec.Code)
+	}
+
+	events := openStream(t, server, blogID, "")
+
+	var comment Comment
+	json.Unmarshal(do(http.MethodPost, "/blogs/comment?id="+blogID, "marko", `{"text":"Lepo"}`).Body.Bytes(), &comment)
+	added := nextEvent(t, events)
+	if added.event != LiveCommentAdded || !strings.Contains(added.data, `"text":"Lepo"`) {
+		t.Errorf("comment-added = %+v", added)
+	}
+
+	do(http.MethodPost, "/blogs/like?id="+blogID+"&user=jova", "jova", "")
+	if liked := nextEvent(t, events); liked.event != LiveLikeCount || liked.data != `{"likeCount":1,"reactionCounts":{"like":1}}` {
+		t.Errorf("like-count = %+v", liked)
+	}
+
+	commentPath := "/blogs/" + blogID + "/comments/" + comment.ID
+	if rec := do(http.MethodPut, commentPath, "jova", `{"text":"Tuđe"}`); rec.Code != http.StatusForbidden {
+		t.Errorf("izmena tuđeg komentara: status = %d, očekivano 403", rec.Code)
+	}
+	if rec := do(http.MethodPut, commentPath, "marko", `{"text":"Lepo, budala"}`); rec.Code != http.StatusOK {
+		t.Fatalf("izmena komentara: status = %d; telo: %s", rec.Code, rec.Body.String())
+	}
+	if edited := nextEvent(t, events); edited.event != LiveCommentEdited || !strings.Contains(edited.data, `"text":"Lepo, ******"`) {
+		t.Errorf("comment-edited = %+v", edited)
+	}
+
+	// Zadržana izmena sakriva komentar i ne šalje se.
+	if rec := do(http.MethodPut, commentPath, "marko", `{"text":"http://a.rs http://b.rs"}`); rec.Code != http.StatusAccepted {
+		t.Errorf("zadržana izmena: status = %d, očekivano 202", rec.Code)
+	}
+
+	resumed := openStream(t, server, blogID, added.id)
+	for _, want := range []string{LiveLikeCount, LiveCommentEdited} {
+		if got := nextEvent(t, resumed); got.event != want {
+			t.Errorf("nastavak: %s, očekivano %s", got.event, want)
+		}
+	}
+	if reset := nextEvent(t, openStream(t, server, blogID, "nepoznat")); reset.event != liveReset {
+		t.Errorf("nepoznat Last-Event-ID: %+v", reset)
+	}
+	select {
+	case event := <-events:
+		t.Errorf("višak događaja: %+v", event)
+	default:
+	}
+}
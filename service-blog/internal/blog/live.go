@@ -0,0 +1,138 @@
+package blog
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"blog-service/internal/realtime"
+)
+
+// Tipovi događaja na GET /blogs/{id}/events. Posle reset-a klijent ponovo
+// učitava blog, jer događaji od kojih je hteo da nastavi više nisu sačuvani.
+const (
+	LiveCommentAdded  = "comment-added"
+	LiveCommentEdited = "comment-edited"
+	LiveLikeCount     = "like-count"
+	liveReset         = "reset"
+)
+
+// HeartbeatInterval je razmak između ping komentara na otvorenoj vezi;
+// proksiji i gateway tako ne zatvaraju vezu bez saobraćaja.
+var HeartbeatInterval = 15 * time.Second
+
+// LiveFeed prenosi promene bloga otvorenim vezama; u produkciji je to
+// realtime.Relay, a sa jednom instancom dovoljan je realtime.Hub.
+type LiveFeed interface {
+	Publish(event realtime.Event) error
+	Subscribe(blogID, lastEventID string) (*realtime.Subscription, []realtime.Event, bool)
+}
+
+// LikeCount je telo like-count događaja.
+type LikeCount struct {
+	LikeCount      int            `json:"likeCount"`
+	ReactionCounts map[string]int `json:"reactionCounts"`
+}
+
+var errLiveUnavailable = &Error{Kind: KindUnavailable, Code: "live.unavailable", Message: "Praćenje bloga uživo nije dostupno."}
+
+// broadcast šalje promenu bloga; promena je već sačuvana, pa greška samo
+// završava u logu.
+func (s *Service) broadcast(blogID, eventType string, data any) {
+	if s.live == nil {
+		return
+	}
+	event, err := realtime.NewEvent(blogID, eventType, data)
+	if err == nil {
+		err = s.live.Publish(event)
+	}
+	if err != nil {
+		log.Printf("❌ Događaj uživo %s za blog %s nije poslat: %s", eventType, blogID, err)
+	}
+}
+
+// broadcastLikeCount šalje nove brojeve reakcija bloga.
+func (s *Service) broadcastLikeCount(target Target) {
+	if s.live == nil || target.isComment() {
+		return
+	}
+	blog, err := s.Get(target.BlogID, "")
+	if err != nil {
+		log.Printf("❌ Događaj uživo %s za blog %s nije poslat: %s", LiveLikeCount, target.BlogID, err)
+		return
+	}
+	s.broadcast(target.BlogID, LiveLikeCount, LikeCount{LikeCount: blog.LikeCount, ReactionCounts: blog.ReactionCounts})
+}
+
+// Subscribe otvara praćenje bloga koji korisnik userID vidi.
+func (s *Service) Subscribe(blogID, userID, lastEventID string) (*realtime.Subscription, []realtime.Event, bool, error) {
+	if s.live == nil {
+		return nil, nil, false, errLiveUnavailable
+	}
+	if _, err := s.repo.Get(blogID, userID); err != nil {
+		return nil, nil, false, err
+	}
+	sub, replay, resumed := s.live.Subscribe(blogID, lastEventID)
+	return sub, replay, resumed, nil
+}
+
+// StreamEvents: GET /blogs/{id}/events je SSE tok promena bloga. Klijent
+// nastavlja od Last-Event-ID header-a koji EventSource šalje pri ponovnom
+// povezivanju, ili od ?lastEventId= pri prvom.
+func (h *Handler) StreamEvents(w http.ResponseWriter, r *http.Request) {
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("lastEventId")
+	}
+
+	sub, replay, resumed, err := h.service.Subscribe(r.PathValue("id"), callerID(r), lastEventID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if !resumed {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", liveReset)
+	}
+	for _, event := range replay {
+		writeLiveEvent(w, event)
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(HeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.Events:
+			// Zatvoren kanal znači da klijent nije stizao da čita; prekida
+			// vezu, a EventSource nastavlja od poslednjeg ID-ja.
+			if !ok {
+				return
+			}
+			writeLiveEvent(w, event)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeLiveEvent(w http.ResponseWriter, event realtime.Event) {
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+}
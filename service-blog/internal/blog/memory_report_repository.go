@@ -0,0 +1,101 @@
+package blog
+
+import (
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryReportRepository je ReportRepository u memoriji za testove.
+type MemoryReportRepository struct {
+	mu      sync.RWMutex
+	reports map[string]Report
+}
+
+func NewMemoryReportRepository() *MemoryReportRepository {
+	return &MemoryReportRepository{reports: map[string]Report{}}
+}
+
+func (m *MemoryReportRepository) CreateReport(report Report) (Report, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, existing := range m.reports {
+		if existing.Status == ReportOpen && existing.target() == report.target() && existing.ReporterID == report.ReporterID {
+			return Report{}, ErrDuplicateReport
+		}
+	}
+	report.ID = primitive.NewObjectID().Hex()
+	m.reports[report.ID] = report
+	return report, nil
+}
+
+func (m *MemoryReportRepository) GetReport(id string) (Report, error) {
+	if _, err := parseReportID(id); err != nil {
+		return Report{}, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	report, ok := m.reports[id]
+	if !ok {
+		return Report{}, ErrReportNotFound
+	}
+	return report, nil
+}
+
+func (m *MemoryReportRepository) ListReports(status string, offset, limit int) (ReportPage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var reports []Report
+	for _, report := range m.reports {
+		if report.Status == status {
+			reports = append(reports, report)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].ID < reports[j].ID })
+
+	page := ReportPage{Status: status, Total: len(reports), Limit: limit, Offset: offset, Reports: []Report{}}
+	if offset < len(reports) {
+		page.Reports = append(page.Reports, reports[offset:min(offset+limit, len(reports))]...)
+	}
+	return page, nil
+}
+
+func (m *MemoryReportRepository) ResolveReport(id string, resolution Resolution) error {
+	if _, err := parseReportID(id); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	report, ok := m.reports[id]
+	if !ok || report.Status != ReportOpen {
+		return ErrReportResolved
+	}
+	report.Status = ReportResolved
+	report.Resolution = &resolution
+	m.reports[id] = report
+	return nil
+}
+
+func (m *MemoryReportRepository) ResolveTarget(target Target, resolution Resolution) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var resolved int64
+	for id, report := range m.reports {
+		if report.Status != ReportOpen || report.target() != target {
+			continue
+		}
+		report.Status = ReportResolved
+		report.Resolution = &resolution
+		m.reports[id] = report
+		resolved++
+	}
+	return resolved, nil
+}
@@ -0,0 +1,603 @@
+package blog
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRepository čuva blogove u memoriji i vraća iste greške kao
+// Repository, pa handleri i servis mogu da se testiraju bez MongoDB-a.
+type MemoryRepository struct {
+	mu       sync.RWMutex
+	blogs    map[string]Blog
+	activity map[string]Activity
+	// publishLeases su blogovi čiji događaj objave još nije poslat, sa
+	// istekom zakupa; isto što i publishPending i publishLease u Mongu.
+	publishLeases map[string]time.Time
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{blogs: map[string]Blog{}, activity: map[string]Activity{}, publishLeases: map[string]time.Time{}}
+}
+
+func (m *MemoryRepository) Create(blog Blog) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if blog.ID == "" {
+		blog.ID = primitive.NewObjectID().Hex()
+	}
+	m.blogs[blog.ID] = cloneBlog(blog)
+	return blog.ID, nil
+}
+
+func (m *MemoryRepository) GetAll(userID string, filter BlogFilter) ([]Blog, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	blogs := make([]Blog, 0, len(m.blogs))
+	for _, blog := range m.blogs {
+		if blog.Hidden || blog.DeletedAt != nil || !visibleToUser(blog, userID) {
+			continue
+		}
+		if filter.Tag != "" && !slices.Contains(blog.Tags, filter.Tag) {
+			continue
+		}
+		if filter.TourID != 0 && blog.TourID != filter.TourID {
+			continue
+		}
+		blogs = append(blogs, visibleBlog(blog, userID))
+	}
+	// ObjectID počinje vremenom kreiranja, pa je redosled isti kao u Mongu.
+	sort.Slice(blogs, func(i, j int) bool { return blogs[i].ID < blogs[j].ID })
+	return blogs, nil
+}
+
+func (m *MemoryRepository) Get(blogID, userID string) (Blog, error) {
+	if _, err := parseBlogID(blogID); err != nil {
+		return Blog{}, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	blog, ok := m.blogs[blogID]
+	if !ok || blog.Hidden || blog.DeletedAt != nil || !visibleToUser(blog, userID) {
+		return Blog{}, ErrBlogNotFound
+	}
+	return visibleBlog(blog, userID), nil
+}
+
+// visibleToUser: zakazan blog vidi samo autor.
+func visibleToUser(blog Blog, userID string) bool {
+	return !blog.Scheduled || blog.Author == userID
+}
+
+// visibleBlog vraća kopiju bloga kakvu vraća Mongo upit: bez sakrivenih i
+// obrisanih komentara i nizova reakcija, sa reakcijom korisnika userID.
+func visibleBlog(blog Blog, userID string) Blog {
+	blog = cloneBlog(blog)
+	blog.MyReaction = userReaction(blog.Reactions, userID)
+	blog.Reactions = nil
+	comments := blog.Comments[:0]
+	for _, comment := range blog.Comments {
+		if comment.Hidden || comment.DeletedAt != nil {
+			continue
+		}
+		comment.MyReaction = userReaction(comment.Reactions, userID)
+		comment.Reactions = nil
+		comments = append(comments, comment)
+	}
+	blog.Comments = comments
+	return blog
+}
+
+func (m *MemoryRepository) Update(blog Blog, version int64) error {
+	return m.update(blog.ID, func(current *Blog) error {
+		if current.Hidden {
+			return ErrBlogNotFound
+		}
+		if current.Version != version {
+			return ErrVersionMismatch
+		}
+		current.Title = blog.Title
+		current.Description = blog.Description
+		current.Images = append([]string(nil), blog.Images...)
+		current.Tags = append([]string(nil), blog.Tags...)
+		current.TourID = blog.TourID
+		current.KeyPointIDs = append([]int64(nil), blog.KeyPointIDs...)
+		current.Mentions = append([]Mention(nil), blog.Mentions...)
+		current.Hidden = blog.Hidden
+		current.PublishAt = blog.PublishAt
+		current.Scheduled = blog.Scheduled
+		return nil
+	})
+}
+
+func (m *MemoryRepository) DeleteBlog(blogID, deletedBy string, version int64) error {
+	return m.update(blogID, func(blog *Blog) error {
+		if blog.Hidden {
+			return ErrBlogNotFound
+		}
+		if blog.Version != version {
+			return ErrVersionMismatch
+		}
+		blog.DeletedAt, blog.DeletedBy = deletedNow(deletedBy)
+		return nil
+	})
+}
+
+func (m *MemoryRepository) AddComment(blogID string, comment Comment) error {
+	return m.update(blogID, func(blog *Blog) error {
+		if blog.Hidden {
+			return ErrBlogNotFound
+		}
+		blog.Comments = append(blog.Comments, comment)
+		if !comment.Hidden {
+			blog.CommentCount++
+		}
+		return nil
+	})
+}
+
+func (m *MemoryRepository) EditComment(target Target, comment Comment) error {
+	return m.update(target.BlogID, func(blog *Blog) error {
+		if blog.Hidden {
+			return ErrBlogNotFound
+		}
+		i := commentIndex(blog.Comments, target.CommentID)
+		if i < 0 || blog.Comments[i].Hidden {
+			return ErrCommentNotFound
+		}
+		blog.Comments[i].Text = comment.Text
+		blog.Comments[i].Mentions = append([]Mention(nil), comment.Mentions...)
+		blog.Comments[i].ModifiedAt = comment.ModifiedAt
+		return nil
+	})
+}
+
+func (m *MemoryRepository) SetReaction(target Target, userID, reactionType string) error {
+	return m.updateReactions(target, func(reactions *[]Reaction, counts map[string]int) {
+		for i, reaction := range *reactions {
+			if reaction.UserID == userID {
+				counts[reaction.Type]--
+				counts[reactionType]++
+				(*reactions)[i].Type = reactionType
+				return
+			}
+		}
+		*reactions = append(*reactions, Reaction{UserID: userID, Type: reactionType})
+		counts[reactionType]++
+	})
+}
+
+func (m *MemoryRepository) RemoveReaction(target Target, userID, onlyType string) error {
+	return m.updateReactions(target, func(reactions *[]Reaction, counts map[string]int) {
+		for i, reaction := range *reactions {
+			if reaction.UserID != userID {
+				continue
+			}
+			if onlyType == "" || reaction.Type == onlyType {
+				counts[reaction.Type]--
+				*reactions = append((*reactions)[:i:i], (*reactions)[i+1:]...)
+			}
+			return
+		}
+	})
+}
+
+func (m *MemoryRepository) GetReactions(blogID, reactionType string, offset, limit int) (ReactionPage, error) {
+	if _, err := parseBlogID(blogID); err != nil {
+		return ReactionPage{}, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	blog, ok := m.blogs[blogID]
+	if !ok || blog.DeletedAt != nil {
+		return ReactionPage{}, ErrBlogNotFound
+	}
+	var users []string
+	for _, reaction := range blog.Reactions {
+		if reaction.Type == reactionType {
+			users = append(users, reaction.UserID)
+		}
+	}
+	page := ReactionPage{BlogID: blogID, Type: reactionType, Total: blog.ReactionCounts[reactionType], Limit: limit, Offset: offset, Users: []string{}}
+	if offset < len(users) {
+		end := min(offset+limit, len(users))
+		page.Users = append(page.Users, users[offset:end]...)
+	}
+	return page, nil
+}
+
+func (m *MemoryRepository) TagCounts(limit int) ([]TagCount, error) {
+	return m.countTags(time.Time{}, limit), nil
+}
+
+func (m *MemoryRepository) TrendingTags(since time.Time, limit int) ([]TagCount, error) {
+	return m.countTags(since, limit), nil
+}
+
+func (m *MemoryRepository) countTags(since time.Time, limit int) []TagCount {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := map[string]int{}
+	for _, blog := range m.blogs {
+		if blog.Scheduled || blog.Hidden || blog.DeletedAt != nil || publishedAt(blog).Before(since) {
+			continue
+		}
+		for _, tag := range blog.Tags {
+			counts[tag]++
+		}
+	}
+
+	tags := make([]TagCount, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	if len(tags) > limit {
+		tags = tags[:limit]
+	}
+	return tags
+}
+
+func (m *MemoryRepository) ClaimPublication(now time.Time, lease time.Duration) (Blog, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]string, 0, len(m.blogs))
+	for id := range m.blogs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		blog := m.blogs[id]
+		if blog.Hidden || blog.DeletedAt != nil {
+			continue
+		}
+		due := blog.Scheduled && !blog.PublishAt.After(now)
+		expiry, pending := m.publishLeases[id]
+		if !due && !(pending && !expiry.After(now)) {
+			continue
+		}
+		blog = cloneBlog(blog)
+		blog.Scheduled = false
+		blog.Version++
+		blog.UpdatedAt = time.Now()
+		m.blogs[id] = blog
+		m.publishLeases[id] = now.Add(lease)
+		return cloneBlog(blog), true, nil
+	}
+	return Blog{}, false, nil
+}
+
+func (m *MemoryRepository) CompletePublication(blogID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.publishLeases, blogID)
+	return nil
+}
+
+func (m *MemoryRepository) UnlinkTour(tourID int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var unlinked int64
+	for id, blog := range m.blogs {
+		if blog.TourID != tourID {
+			continue
+		}
+		blog.TourID = 0
+		blog.KeyPointIDs = nil
+		blog.Version++
+		blog.UpdatedAt = time.Now()
+		m.blogs[id] = blog
+		unlinked++
+	}
+	return unlinked, nil
+}
+
+func (m *MemoryRepository) ContentAuthor(target Target) (string, error) {
+	if _, err := parseBlogID(target.BlogID); err != nil {
+		return "", err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	blog, ok := m.blogs[target.BlogID]
+	if !ok || blog.Hidden || blog.DeletedAt != nil {
+		return "", ErrBlogNotFound
+	}
+	if !target.isComment() {
+		return blog.Author, nil
+	}
+	i := commentIndex(blog.Comments, target.CommentID)
+	if i < 0 || blog.Comments[i].Hidden {
+		return "", ErrCommentNotFound
+	}
+	return blog.Comments[i].UserID, nil
+}
+
+func (m *MemoryRepository) Hide(target Target) error {
+	return m.update(target.BlogID, func(blog *Blog) error {
+		if !target.isComment() {
+			if blog.Hidden {
+				return ErrBlogNotFound
+			}
+			blog.Hidden = true
+			return nil
+		}
+		i := commentIndex(blog.Comments, target.CommentID)
+		if i < 0 || blog.Comments[i].Hidden {
+			return ErrCommentNotFound
+		}
+		blog.Comments[i].Hidden = true
+		blog.CommentCount--
+		return nil
+	})
+}
+
+func (m *MemoryRepository) Delete(target Target, deletedBy string) error {
+	return m.update(target.BlogID, func(blog *Blog) error {
+		if !target.isComment() {
+			blog.DeletedAt, blog.DeletedBy = deletedNow(deletedBy)
+			return nil
+		}
+		i := commentIndex(blog.Comments, target.CommentID)
+		if i < 0 {
+			return ErrCommentNotFound
+		}
+		if !blog.Comments[i].Hidden {
+			blog.CommentCount--
+		}
+		blog.Comments[i].DeletedAt, blog.Comments[i].DeletedBy = deletedNow(deletedBy)
+		return nil
+	})
+}
+
+func (m *MemoryRepository) Restore(target Target, deletedBy string) error {
+	inTrash := func(deletedAt *time.Time, by string) bool {
+		return deletedAt != nil && (deletedBy == "" || by == deletedBy)
+	}
+	return m.modify(target.BlogID, func(blog *Blog) error {
+		if !target.isComment() {
+			if !inTrash(blog.DeletedAt, blog.DeletedBy) {
+				return ErrBlogNotFound
+			}
+			blog.DeletedAt, blog.DeletedBy = nil, ""
+			return nil
+		}
+		if blog.DeletedAt != nil {
+			return ErrBlogNotFound
+		}
+		i := slices.IndexFunc(blog.Comments, func(c Comment) bool { return c.ID == target.CommentID })
+		if i < 0 || !inTrash(blog.Comments[i].DeletedAt, blog.Comments[i].DeletedBy) {
+			return ErrCommentNotFound
+		}
+		if !blog.Comments[i].Hidden {
+			blog.CommentCount++
+		}
+		blog.Comments[i].DeletedAt, blog.Comments[i].DeletedBy = nil, ""
+		return nil
+	})
+}
+
+func (m *MemoryRepository) Trash(userID string) (Trash, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	trash := Trash{Blogs: []Blog{}, Comments: []TrashedComment{}}
+	for _, blog := range m.blogs {
+		if blog.DeletedAt != nil {
+			if blog.DeletedBy == userID {
+				blog = cloneBlog(blog)
+				blog.Reactions, blog.Comments = nil, nil
+				trash.Blogs = append(trash.Blogs, blog)
+			}
+			continue
+		}
+		for _, comment := range blog.Comments {
+			if comment.DeletedAt != nil && comment.DeletedBy == userID {
+				comment.Reactions = nil
+				trash.Comments = append(trash.Comments, TrashedComment{BlogID: blog.ID, BlogTitle: blog.Title, Comment: comment})
+			}
+		}
+	}
+	sort.Slice(trash.Blogs, func(i, j int) bool { return trash.Blogs[i].DeletedAt.After(*trash.Blogs[j].DeletedAt) })
+	sort.Slice(trash.Comments, func(i, j int) bool {
+		return trash.Comments[i].Comment.DeletedAt.After(*trash.Comments[j].Comment.DeletedAt)
+	})
+	return trash, nil
+}
+
+func (m *MemoryRepository) PurgeDeleted(before time.Time) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expired := func(deletedAt *time.Time) bool {
+		return deletedAt != nil && !deletedAt.After(before)
+	}
+	purged := []string{}
+	for id, blog := range m.blogs {
+		if expired(blog.DeletedAt) {
+			delete(m.blogs, id)
+			delete(m.publishLeases, id)
+			purged = append(purged, id)
+			continue
+		}
+		if slices.ContainsFunc(blog.Comments, func(c Comment) bool { return expired(c.DeletedAt) }) {
+			blog = cloneBlog(blog)
+			blog.Comments = slices.DeleteFunc(blog.Comments, func(c Comment) bool { return expired(c.DeletedAt) })
+			m.blogs[id] = blog
+		}
+	}
+	return purged, nil
+}
+
+func (m *MemoryRepository) Unhide(target Target) error {
+	return m.update(target.BlogID, func(blog *Blog) error {
+		if !target.isComment() {
+			if !blog.Hidden {
+				return ErrBlogNotFound
+			}
+			blog.Hidden = false
+			return nil
+		}
+		i := commentIndex(blog.Comments, target.CommentID)
+		if i < 0 || !blog.Comments[i].Hidden {
+			return ErrCommentNotFound
+		}
+		blog.Comments[i].Hidden = false
+		blog.CommentCount++
+		return nil
+	})
+}
+
+func (m *MemoryRepository) RecordActivity(username string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	activity, ok := m.activity[username]
+	if !ok {
+		activity.FirstSeenAt = time.Now()
+	}
+	activity.Posts++
+	m.activity[username] = activity
+	return nil
+}
+
+func (m *MemoryRepository) Activity(username string) (Activity, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.activity[username], nil
+}
+
+// updateReactions bira niz reakcija bloga ili komentara i njegove brojače.
+func (m *MemoryRepository) updateReactions(target Target, apply func(reactions *[]Reaction, counts map[string]int)) error {
+	return m.update(target.BlogID, func(blog *Blog) error {
+		reactions, counts := &blog.Reactions, &blog.ReactionCounts
+		if target.isComment() {
+			i := commentIndex(blog.Comments, target.CommentID)
+			if i < 0 {
+				return ErrCommentNotFound
+			}
+			reactions, counts = &blog.Comments[i].Reactions, &blog.Comments[i].ReactionCounts
+		}
+		if *counts == nil {
+			*counts = map[string]int{}
+		}
+		apply(reactions, *counts)
+		for reactionType, count := range *counts {
+			if count == 0 {
+				delete(*counts, reactionType)
+			}
+		}
+		return nil
+	})
+}
+
+// update menja blog koji nije u korpi, kao upiti kroz live u Repository.
+func (m *MemoryRepository) update(blogID string, apply func(blog *Blog) error) error {
+	return m.modify(blogID, func(blog *Blog) error {
+		if blog.DeletedAt != nil {
+			return ErrBlogNotFound
+		}
+		return apply(blog)
+	})
+}
+
+func (m *MemoryRepository) LastModified() (time.Time, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var latest time.Time
+	for _, blog := range m.blogs {
+		if blog.UpdatedAt.After(latest) {
+			latest = blog.UpdatedAt
+		}
+	}
+	return latest, nil
+}
+
+// modify menja kopiju bloga i čuva je samo ako apply uspe; kao bump u
+// Repository, svaki upis povećava verziju.
+func (m *MemoryRepository) modify(blogID string, apply func(blog *Blog) error) error {
+	if _, err := parseBlogID(blogID); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	blog, ok := m.blogs[blogID]
+	if !ok {
+		return ErrBlogNotFound
+	}
+	blog = cloneBlog(blog)
+	if err := apply(&blog); err != nil {
+		return err
+	}
+	blog.Version++
+	blog.UpdatedAt = time.Now()
+	m.blogs[blogID] = blog
+	return nil
+}
+
+func userReaction(reactions []Reaction, userID string) string {
+	for _, reaction := range reactions {
+		if reaction.UserID == userID {
+			return reaction.Type
+		}
+	}
+	return ""
+}
+
+// commentIndex preskače komentare u korpi.
+func commentIndex(comments []Comment, commentID string) int {
+	for i, comment := range comments {
+		if comment.ID == commentID && comment.DeletedAt == nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// cloneBlog kopira slice-ove i mape da pozivalac ne bi menjao sačuvano stanje.
+func cloneBlog(blog Blog) Blog {
+	blog.Reactions = append([]Reaction(nil), blog.Reactions...)
+	blog.ReactionCounts = maps.Clone(blog.ReactionCounts)
+	blog.Comments = append([]Comment(nil), blog.Comments...)
+	for i := range blog.Comments {
+		blog.Comments[i].Reactions = append([]Reaction(nil), blog.Comments[i].Reactions...)
+		blog.Comments[i].ReactionCounts = maps.Clone(blog.Comments[i].ReactionCounts)
+	}
+	blog.Images = append([]string(nil), blog.Images...)
+	blog.Tags = append([]string(nil), blog.Tags...)
+	blog.KeyPointIDs = append([]int64(nil), blog.KeyPointIDs...)
+	if blog.PublishAt != nil {
+		publishAt := *blog.PublishAt
+		blog.PublishAt = &publishAt
+	}
+	return blog
+}
+
+// deletedNow vraća DeletedAt i DeletedBy za sadržaj koji by upravo briše.
+func deletedNow(by string) (*time.Time, string) {
+	now := time.Now()
+	return &now, by
+}
@@ -0,0 +1,82 @@
+package blog
+
+import (
+	"slices"
+	"sync"
+)
+
+// MemoryRevisionRepository je RevisionRepository u memoriji za testove.
+type MemoryRevisionRepository struct {
+	mu        sync.RWMutex
+	revisions map[string][]Revision
+}
+
+func NewMemoryRevisionRepository() *MemoryRevisionRepository {
+	return &MemoryRevisionRepository{revisions: map[string][]Revision{}}
+}
+
+func (m *MemoryRevisionRepository) AddRevision(rev Revision) (Revision, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	history := m.revisions[rev.BlogID]
+	rev.Number = 1
+	if len(history) > 0 {
+		rev.Number = history[len(history)-1].Number + 1
+	}
+	m.revisions[rev.BlogID] = append(history, rev)
+	return rev, nil
+}
+
+func (m *MemoryRevisionRepository) GetRevision(blogID string, number int) (Revision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, rev := range m.revisions[blogID] {
+		if rev.Number == number {
+			return rev, nil
+		}
+	}
+	return Revision{}, ErrRevisionNotFound
+}
+
+func (m *MemoryRevisionRepository) LatestRevision(blogID string) (Revision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	history := m.revisions[blogID]
+	if len(history) == 0 {
+		return Revision{}, ErrRevisionNotFound
+	}
+	return history[len(history)-1], nil
+}
+
+func (m *MemoryRevisionRepository) ListRevisions(blogID string, offset, limit int) (RevisionPage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	history := m.revisions[blogID]
+	page := RevisionPage{BlogID: blogID, Total: len(history), Limit: limit, Offset: offset, Revisions: []Revision{}}
+	for i := len(history) - 1 - offset; i >= 0 && len(page.Revisions) < limit; i-- {
+		rev := history[i]
+		rev.Markdown = ""
+		page.Revisions = append(page.Revisions, rev)
+	}
+	return page, nil
+}
+
+func (m *MemoryRevisionRepository) DeleteRevision(blogID string, number int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.revisions[blogID] = slices.DeleteFunc(m.revisions[blogID], func(rev Revision) bool { return rev.Number == number })
+	return nil
+}
+
+func (m *MemoryRevisionRepository) DeleteRevisions(blogID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.revisions, blogID)
+	return nil
+}
@@ -0,0 +1,265 @@
+package blog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/html"
+)
+
+// MaxMentions je najveći broj korisnika koje blog ili komentar pominje;
+// dalji pomeni ostaju običan tekst.
+const MaxMentions = 10
+
+// mentionPattern prepoznaje @korisnik na početku teksta ili posle znaka
+// koji nije deo imena, pa adresa ana@mail.com nije pomen. Tačka i crtica
+// na kraju pripadaju rečenici, a ne imenu.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@])@([\p{L}\p{N}_](?:[\p{L}\p{N}_.-]*[\p{L}\p{N}_])?)`)
+
+// UserDirectory proverava pomenute korisnike; u produkciji je to
+// stakeholders.Client nad service-stakeholders.
+type UserDirectory interface {
+	// UserID vraća ID korisnika; ok je false ako korisnik ne postoji ili je
+	// blokiran, ali se ID blokiranog korisnika ipak vraća.
+	UserID(ctx context.Context, username string) (id int64, ok bool, err error)
+}
+
+// BlockList proverava blokade između korisnika; u produkciji je to
+// followers.Client nad service-follower.
+type BlockList interface {
+	// Blocks javlja da li jedan od korisnika blokira drugog.
+	Blocks(ctx context.Context, userID, otherID int64) (bool, error)
+}
+
+// MemoryUserDirectory je UserDirectory za testove i lokalni rad bez service-stakeholders.
+type MemoryUserDirectory struct {
+	mu      sync.RWMutex
+	users   map[string]int64
+	blocked map[string]bool
+}
+
+func NewMemoryUserDirectory() *MemoryUserDirectory {
+	return &MemoryUserDirectory{users: map[string]int64{}, blocked: map[string]bool{}}
+}
+
+func (d *MemoryUserDirectory) AddUser(username string, id int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.users[username] = id
+}
+
+func (d *MemoryUserDirectory) Block(username string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.blocked[username] = true
+}
+
+func (d *MemoryUserDirectory) UserID(ctx context.Context, username string) (int64, bool, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	id, ok := d.users[username]
+	return id, ok && !d.blocked[username], nil
+}
+
+// MemoryBlockList je BlockList za testove i lokalni rad bez service-follower.
+type MemoryBlockList struct {
+	mu     sync.RWMutex
+	blocks map[[2]int64]bool
+}
+
+func NewMemoryBlockList() *MemoryBlockList {
+	return &MemoryBlockList{blocks: map[[2]int64]bool{}}
+}
+
+// Block beleži da userID blokira targetID.
+func (b *MemoryBlockList) Block(userID, targetID int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.blocks[[2]int64{userID, targetID}] = true
+}
+
+func (b *MemoryBlockList) Blocks(ctx context.Context, userID, otherID int64) (bool, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.blocks[[2]int64{userID, otherID}] || b.blocks[[2]int64{otherID, userID}], nil
+}
+
+// mentionNames dodaje u names korisnička imena pomenuta u tekstu, bez
+// ponavljanja i najviše MaxMentions.
+func mentionNames(names []string, text string) []string {
+	for _, m := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		if len(names) == MaxMentions {
+			break
+		}
+		if !slices.Contains(names, m[1]) {
+			names = append(names, m[1])
+		}
+	}
+	return names
+}
+
+// markdownMentionNames čita pomene iz teksta markdown-a; pomeni u kodu i
+// u tekstu linkova se ne računaju.
+func markdownMentionNames(source string) []string {
+	var names []string
+	ast.WalkFunc(markdown.Parse([]byte(source), nil), func(node ast.Node, entering bool) ast.WalkStatus {
+		if text, ok := node.(*ast.Text); ok && entering && !insideLink(text) {
+			names = mentionNames(names, string(text.Literal))
+		}
+		return ast.GoToNext
+	})
+	return names
+}
+
+func insideLink(node ast.Node) bool {
+	for parent := node.GetParent(); parent != nil; parent = parent.GetParent() {
+		if _, ok := parent.(*ast.Link); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveMentions proverava korisnike koje author pominje: blokirani,
+// nepostojeći i oni koji su u blokadi sa autorom ostaju običan tekst, pa se
+// ne vraćaju. Pomen ne sme da zaustavi upis, pa i korisnik koji ne može da
+// se proveri (servis nije dostupan) ostaje običan tekst.
+func (s *Service) resolveMentions(author string, names []string) []Mention {
+	if s.users == nil || len(names) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var authorID int64
+	if s.blocks != nil {
+		id, _, err := s.users.UserID(ctx, author)
+		if err != nil {
+			log.Printf("⚠️ Pomeni korisnika %s ostaju tekst: %s", author, err)
+			return nil
+		}
+		authorID = id
+	}
+
+	var mentions []Mention
+	for _, name := range names {
+		id, ok, err := s.users.UserID(ctx, name)
+		if err == nil && ok && authorID != 0 && id != authorID {
+			var blocked bool
+			blocked, err = s.blocks.Blocks(ctx, authorID, id)
+			ok = !blocked
+		}
+		if err != nil {
+			log.Printf("⚠️ Pomen @%s ostaje tekst: %s", name, err)
+			continue
+		}
+		if ok {
+			mentions = append(mentions, Mention{Username: name, UserID: id})
+		}
+	}
+	return mentions
+}
+
+// renderMarkdown renderuje opis bloga; pomeni iz mentions postaju linkovi
+// na profil, a ostali tekst ide kroz uobičajen renderer.
+func renderMarkdown(source string, mentions []Mention) string {
+	renderer := html.NewRenderer(html.RendererOptions{
+		Flags: html.CommonFlags,
+	})
+	renderer.Opts.RenderNodeHook = func(w io.Writer, node ast.Node, entering bool) (ast.WalkStatus, bool) {
+		text, ok := node.(*ast.Text)
+		if !ok || len(mentions) == 0 || insideLink(text) {
+			return ast.GoToNext, false
+		}
+		if entering {
+			renderMentions(w, renderer, text, mentions)
+		}
+		return ast.GoToNext, true
+	}
+	return string(markdown.Render(markdown.Parse([]byte(source), nil), renderer))
+}
+
+func renderMentions(w io.Writer, renderer *html.Renderer, text *ast.Text, mentions []Mention) {
+	literal := string(text.Literal)
+	plain := func(s string) {
+		if s != "" {
+			renderer.Text(w, &ast.Text{Leaf: ast.Leaf{Literal: []byte(s)}})
+		}
+	}
+
+	last := 0
+	for _, m := range mentionPattern.FindAllStringSubmatchIndex(literal, -1) {
+		name := literal[m[2]:m[3]]
+		i := slices.IndexFunc(mentions, func(mention Mention) bool { return mention.Username == name })
+		if i < 0 {
+			continue
+		}
+		at := m[2] - 1
+		plain(literal[last:at])
+		fmt.Fprintf(w, `<a href="/profile/%d" class="mention">@`, mentions[i].UserID)
+		html.EscapeHTML(w, []byte(name))
+		io.WriteString(w, "</a>")
+		last = m[3]
+	}
+	plain(literal[last:])
+}
+
+// mentionsAdded vraća pomene iz mentions kojih nema u before.
+func mentionsAdded(mentions, before []Mention) []Mention {
+	var added []Mention
+	for _, mention := range mentions {
+		if !slices.Contains(before, mention) {
+			added = append(added, mention)
+		}
+	}
+	return added
+}
+
+// mentionActivities pravi po jedan blog.mentioned za svakog korisnika
+// koga userID pominje u blogu ili komentaru commentID; autor pomena ne
+// obaveštava sam sebe.
+func mentionActivities(blog Blog, userID, commentID string, mentions []Mention, at time.Time) []BlogActivity {
+	var activities []BlogActivity
+	for _, mention := range mentions {
+		if mention.Username == userID {
+			continue
+		}
+		activities = append(activities, BlogActivity{
+			BlogID:     blog.ID,
+			BlogAuthor: blog.Author,
+			BlogTitle:  blog.Title,
+			UserID:     userID,
+			CommentID:  commentID,
+			Mentioned:  mention.Username,
+			At:         at,
+		})
+	}
+	return activities
+}
+
+// notifyMentions objavljuje pomene u blogu blogID; blog ili komentar je
+// već sačuvan, pa greška samo završava u logu.
+func (s *Service) notifyMentions(blogID, userID, commentID string, mentions []Mention) {
+	if s.events == nil || len(mentions) == 0 {
+		return
+	}
+	blog, err := s.repo.Get(blogID, userID)
+	if err != nil {
+		log.Printf("❌ Događaj %s za blog %s nije poslat: %s", RoutingKeyBlogMentioned, blogID, err)
+		return
+	}
+	for _, event := range mentionActivities(blog, userID, commentID, mentions, time.Now()) {
+		if err := s.events.Publish(RoutingKeyBlogMentioned, event); err != nil {
+			log.Printf("❌ Događaj %s za blog %s nije poslat: %s", RoutingKeyBlogMentioned, blogID, err)
+		}
+	}
+}
@@ -2,20 +2,93 @@ package blog
 
 import "time"
 
+// Blog je objava korisnika sa komentarima i reakcijama.
 type Blog struct {
 	ID          string    `json:"id" bson:"_id,omitempty"`
 	Author      string    `json:"author" bson:"author"`
 	Title       string    `json:"title" bson:"title"`
 	Description string    `json:"description" bson:"description"`
 	CreatedAt   time.Time `json:"createdAt" bson:"createdAt"`
-	Likes       []string  `json:"likes,omitempty" bson:"likes,omitempty"`
-	Comments    []Comment `json:"comments,omitempty" bson:"comments,omitempty"`
-	Images      []string  `json:"images,omitempty" bson:"images,omitempty"`
+	// Reactions se ne šalje klijentu, da bi lista blogova ostala mala;
+	// korisnici se čitaju stranicu po stranicu preko GET /blogs/{id}/reactions.
+	Reactions      []Reaction     `json:"-" bson:"reactions,omitempty"`
+	ReactionCounts map[string]int `json:"reactionCounts" bson:"reactionCounts,omitempty"`
+	MyReaction     string         `json:"myReaction,omitempty" bson:"myReaction,omitempty"`
+	// LikeCount i LikedByMe se računaju iz "like" reakcije zbog starih klijenata.
+	LikeCount    int       `json:"likeCount" bson:"-"`
+	LikedByMe    bool      `json:"likedByMe" bson:"-"`
+	Comments     []Comment `json:"comments,omitempty" bson:"comments,omitempty"`
+	CommentCount int       `json:"commentCount" bson:"commentCount"`
+	Images       []string  `json:"images,omitempty" bson:"images,omitempty"`
+	Tags         []string  `json:"tags,omitempty" bson:"tags,omitempty"`
+	TourID       int64     `json:"tourId,omitempty" bson:"tourId,omitempty"`
+	KeyPointIDs  []int64   `json:"keyPointIds,omitempty" bson:"keyPointIds,omitempty"`
+	// Mentions su korisnici pomenuti u opisu.
+	Mentions []Mention `json:"mentions,omitempty" bson:"mentions,omitempty"`
+	// Hidden: blog koji moderator sakrije ne vraća se u listama.
+	Hidden bool `json:"-" bson:"hidden,omitempty"`
+	// PublishAt posle objave ostaje kao vreme objave.
+	PublishAt *time.Time `json:"publishAt,omitempty" bson:"publishAt,omitempty"`
+	// Scheduled: blog vidi samo autor dok PublishScheduler ne dođe do PublishAt.
+	Scheduled bool `json:"scheduled,omitempty" bson:"scheduled,omitempty"`
+	// DeletedAt: obrisan blog čeka u korpi korisnika DeletedBy do trajnog
+	// brisanja i vraća se samo u korpi.
+	DeletedAt *time.Time `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+	DeletedBy string     `json:"deletedBy,omitempty" bson:"deletedBy,omitempty"`
+	// Version se povećava, a UpdatedAt pomera pri svakom upisu u blog,
+	// uključujući komentare i reakcije; iz njih se prave ETag i Last-Modified.
+	Version   int64     `json:"version" bson:"version"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// ReactionPage je jedna stranica korisnika koji su na blog reagovali datim tipom.
+type ReactionPage struct {
+	BlogID string   `json:"blogId"`
+	Type   string   `json:"type"`
+	Total  int      `json:"total"`
+	Limit  int      `json:"limit"`
+	Offset int      `json:"offset"`
+	Users  []string `json:"users"`
 }
 
 type Comment struct {
-	UserID     string    `json:"userId" bson:"userId"`
-	Text       string    `json:"text" bson:"text"`
-	CreatedAt  time.Time `json:"createdAt" bson:"createdAt"`
-	ModifiedAt time.Time `json:"modifiedAt" bson:"modifiedAt"`
+	ID     string `json:"id" bson:"id"`
+	UserID string `json:"userId" bson:"userId"`
+	Text   string `json:"text" bson:"text"`
+	// Mentions su korisnici pomenuti u tekstu komentara.
+	Mentions       []Mention      `json:"mentions,omitempty" bson:"mentions,omitempty"`
+	CreatedAt      time.Time      `json:"createdAt" bson:"createdAt"`
+	ModifiedAt     time.Time      `json:"modifiedAt" bson:"modifiedAt"`
+	Reactions      []Reaction     `json:"-" bson:"reactions,omitempty"`
+	ReactionCounts map[string]int `json:"reactionCounts,omitempty" bson:"reactionCounts,omitempty"`
+	MyReaction     string         `json:"myReaction,omitempty" bson:"myReaction,omitempty"`
+	// Hidden i DeletedAt imaju isto značenje kao kod bloga.
+	Hidden    bool       `json:"-" bson:"hidden,omitempty"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+	DeletedBy string     `json:"deletedBy,omitempty" bson:"deletedBy,omitempty"`
+}
+
+// Mention je korisnik pomenut kao @username; UserID je ID iz
+// service-stakeholders, na koji vodi link profila.
+type Mention struct {
+	Username string `json:"username" bson:"username"`
+	UserID   int64  `json:"userId" bson:"userId"`
+}
+
+// Reaction je reakcija jednog korisnika; korisnik ima najviše jednu po blogu
+// i po komentaru.
+type Reaction struct {
+	UserID string `json:"userId" bson:"userId"`
+	Type   string `json:"type" bson:"type"`
+}
+
+// Target je blog ili, ako je CommentID postavljen, komentar na blogu;
+// na njega se reaguje i njega korisnici prijavljuju.
+type Target struct {
+	BlogID    string
+	CommentID string
+}
+
+func (t Target) isComment() bool {
+	return t.CommentID != ""
 }
@@ -0,0 +1,200 @@
+package blog
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"blog-service/internal/contentfilter"
+)
+
+// Razlozi prijave; klijent šalje kod, a tekst prikazuje sam.
+const (
+	ReasonSpam           = "spam"
+	ReasonHarassment     = "harassment"
+	ReasonHateSpeech     = "hate_speech"
+	ReasonViolence       = "violence"
+	ReasonMisinformation = "misinformation"
+	ReasonInappropriate  = "inappropriate"
+	ReasonOther          = "other"
+
+	// ReasonAutomated imaju prijave koje otvara filter sadržaja; korisnik
+	// ne može da je izabere.
+	ReasonAutomated = "automated"
+	SystemReporter  = "content-filter"
+)
+
+const (
+	ReportOpen     = "open"
+	ReportResolved = "resolved"
+)
+
+// Akcije moderatora. Hide, Delete i Approve deluju na sadržaj i zatvaraju
+// sve otvorene prijave za njega; Dismiss i Warn zatvaraju samo jednu
+// prijavu. Approve objavljuje sadržaj koji je zadržao filter.
+const (
+	ActionHide    = "hide"
+	ActionDelete  = "delete"
+	ActionApprove = "approve"
+	ActionDismiss = "dismiss"
+	ActionWarn    = "warn"
+)
+
+// Report je prijava bloga ili komentara. ContentAuthor se pamti u trenutku
+// prijave, da bi upozorenje (warn) imalo kome da ode i kada je sadržaj
+// u međuvremenu obrisan.
+type Report struct {
+	ID            string      `json:"id" bson:"_id,omitempty"`
+	BlogID        string      `json:"blogId" bson:"blogId"`
+	CommentID     string      `json:"commentId,omitempty" bson:"commentId,omitempty"`
+	ReporterID    string      `json:"reporterId" bson:"reporterId"`
+	ContentAuthor string      `json:"contentAuthor" bson:"contentAuthor"`
+	Reason        string      `json:"reason" bson:"reason"`
+	Note          string      `json:"note,omitempty" bson:"note,omitempty"`
+	Status        string      `json:"status" bson:"status"`
+	CreatedAt     time.Time   `json:"createdAt" bson:"createdAt"`
+	Resolution    *Resolution `json:"resolution,omitempty" bson:"resolution,omitempty"`
+}
+
+func (r Report) target() Target {
+	return Target{BlogID: r.BlogID, CommentID: r.CommentID}
+}
+
+// Resolution beleži ko je, kada i kako zatvorio prijavu.
+type Resolution struct {
+	Action      string    `json:"action" bson:"action"`
+	ModeratorID string    `json:"moderatorId" bson:"moderatorId"`
+	Note        string    `json:"note,omitempty" bson:"note,omitempty"`
+	At          time.Time `json:"at" bson:"at"`
+}
+
+// ReportPage je jedna stranica reda za moderaciju, od najstarije prijave.
+type ReportPage struct {
+	Status  string   `json:"status"`
+	Total   int      `json:"total"`
+	Limit   int      `json:"limit"`
+	Offset  int      `json:"offset"`
+	Reports []Report `json:"reports"`
+}
+
+// ReportRepository čuva prijave. Korisnik ima najviše jednu otvorenu
+// prijavu po blogu ili komentaru.
+type ReportRepository interface {
+	CreateReport(report Report) (Report, error)
+	GetReport(id string) (Report, error)
+	ListReports(status string, offset, limit int) (ReportPage, error)
+	// ResolveReport zatvara prijavu samo ako je još otvorena.
+	ResolveReport(id string, resolution Resolution) error
+	// ResolveTarget zatvara sve otvorene prijave za sadržaj.
+	ResolveTarget(target Target, resolution Resolution) (int64, error)
+}
+
+const (
+	DefaultReportPageSize = 20
+	MaxReportPageSize     = 100
+)
+
+type ModerationService struct {
+	blogs   BlogRepository
+	reports ReportRepository
+}
+
+func NewModerationService(blogs BlogRepository, reports ReportRepository) *ModerationService {
+	return &ModerationService{blogs: blogs, reports: reports}
+}
+
+// Report prijavljuje vidljiv blog ili komentar; sakriven sadržaj se
+// ponaša kao da ne postoji.
+func (s *ModerationService) Report(target Target, reporterID, reason, note string) (Report, error) {
+	if reason == ReasonOther && strings.TrimSpace(note) == "" {
+		return Report{}, ErrReportNoteRequired
+	}
+
+	author, err := s.blogs.ContentAuthor(target)
+	if err != nil {
+		return Report{}, err
+	}
+
+	return s.reports.CreateReport(Report{
+		BlogID:        target.BlogID,
+		CommentID:     target.CommentID,
+		ReporterID:    reporterID,
+		ContentAuthor: author,
+		Reason:        reason,
+		Note:          note,
+		Status:        ReportOpen,
+		CreatedAt:     time.Now(),
+	})
+}
+
+// Hold otvara prijavu za sadržaj koji je zadržao filter.
+func (s *ModerationService) Hold(target Target, author string, decision contentfilter.Decision) error {
+	_, err := s.reports.CreateReport(Report{
+		BlogID:        target.BlogID,
+		CommentID:     target.CommentID,
+		ReporterID:    SystemReporter,
+		ContentAuthor: author,
+		Reason:        ReasonAutomated,
+		Note:          decision.Rule + ": " + decision.Reason,
+		Status:        ReportOpen,
+		CreatedAt:     time.Now(),
+	})
+	return err
+}
+
+func (s *ModerationService) Queue(status string, offset, limit int) (ReportPage, error) {
+	if status == "" {
+		status = ReportOpen
+	}
+	if status != ReportOpen && status != ReportResolved {
+		return ReportPage{}, ErrInvalidReportStatus
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.reports.ListReports(status, offset, clampLimit(limit, DefaultReportPageSize, MaxReportPageSize))
+}
+
+// Act primenjuje akciju moderatora i vraća zatvorenu prijavu. Sadržaj se
+// menja pre zatvaranja prijave, pa neuspela izmena ostavlja prijavu u redu.
+func (s *ModerationService) Act(reportID, moderatorID, action, note string) (Report, error) {
+	report, err := s.reports.GetReport(reportID)
+	if err != nil {
+		return Report{}, err
+	}
+	if report.Status != ReportOpen {
+		return Report{}, ErrReportResolved
+	}
+
+	target := report.target()
+	switch action {
+	case ActionHide:
+		err = s.blogs.Hide(target)
+	case ActionDelete:
+		err = s.blogs.Delete(target, moderatorID)
+	case ActionApprove:
+		err = s.blogs.Unhide(target)
+	case ActionDismiss, ActionWarn:
+	default:
+		return Report{}, ErrUnknownModerationAction
+	}
+	// Sadržaj koji je već sakriven, obrisan ili odobren po drugoj prijavi
+	// nije greška.
+	if err != nil && !errors.Is(err, ErrBlogNotFound) && !errors.Is(err, ErrCommentNotFound) {
+		return Report{}, err
+	}
+
+	resolution := Resolution{Action: action, ModeratorID: moderatorID, Note: note, At: time.Now()}
+	if err := s.reports.ResolveReport(reportID, resolution); err != nil {
+		return Report{}, err
+	}
+	if action == ActionHide || action == ActionDelete || action == ActionApprove {
+		if _, err := s.reports.ResolveTarget(target, resolution); err != nil {
+			return Report{}, err
+		}
+	}
+
+	report.Status = ReportResolved
+	report.Resolution = &resolution
+	return report, nil
+}
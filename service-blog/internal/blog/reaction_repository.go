@@ -0,0 +1,232 @@
+package blog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxReactionAttempts ograničava ponavljanja kada se reakcija istog
+// korisnika promeni između čitanja i uslovnog upisa.
+const maxReactionAttempts = 3
+
+// SetReaction dodaje ili menja reakciju korisnika. Svaki upis je uslovni
+// UpdateOne nad stanjem koje je upravo pročitano, pa brojači ostaju tačni
+// i kada isti korisnik istovremeno pošalje dve reakcije.
+func (r *Repository) SetReaction(target Target, userID, reactionType string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objID, err := parseBlogID(target.BlogID)
+	if err != nil {
+		return err
+	}
+
+	prefix := reactionPrefix(target)
+	for attempt := 0; attempt < maxReactionAttempts; attempt++ {
+		current, err := r.currentReaction(ctx, objID, target, userID)
+		if err != nil {
+			return err
+		}
+		if current == reactionType {
+			return nil
+		}
+
+		var filter, update bson.M
+		arrayFilters := commentArrayFilters(target)
+		if current == "" {
+			filter = reactionFilter(objID, target, bson.M{"$not": bson.M{"$elemMatch": bson.M{"userId": userID}}})
+			update = bson.M{
+				"$push": bson.M{prefix + "reactions": Reaction{UserID: userID, Type: reactionType}},
+				"$inc":  bson.M{prefix + "reactionCounts." + reactionType: 1},
+			}
+		} else {
+			filter = reactionFilter(objID, target, bson.M{"$elemMatch": bson.M{"userId": userID, "type": current}})
+			update = bson.M{
+				"$set": bson.M{prefix + "reactions.$[r].type": reactionType},
+				"$inc": bson.M{
+					prefix + "reactionCounts." + current:      -1,
+					prefix + "reactionCounts." + reactionType: 1,
+				},
+			}
+			arrayFilters = append(arrayFilters, bson.M{"r.userId": userID})
+		}
+
+		matched, err := r.updateReaction(ctx, filter, update, arrayFilters)
+		if err != nil || matched {
+			return err
+		}
+	}
+	return errReactionConflict
+}
+
+func (r *Repository) RemoveReaction(target Target, userID, onlyType string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objID, err := parseBlogID(target.BlogID)
+	if err != nil {
+		return err
+	}
+
+	prefix := reactionPrefix(target)
+	for attempt := 0; attempt < maxReactionAttempts; attempt++ {
+		current, err := r.currentReaction(ctx, objID, target, userID)
+		if err != nil {
+			return err
+		}
+		if current == "" || (onlyType != "" && current != onlyType) {
+			return nil
+		}
+
+		filter := reactionFilter(objID, target, bson.M{"$elemMatch": bson.M{"userId": userID, "type": current}})
+		update := bson.M{
+			"$pull": bson.M{prefix + "reactions": bson.M{"userId": userID}},
+			"$inc":  bson.M{prefix + "reactionCounts." + current: -1},
+		}
+		matched, err := r.updateReaction(ctx, filter, update, commentArrayFilters(target))
+		if err != nil || matched {
+			return err
+		}
+	}
+	return errReactionConflict
+}
+
+// GetReactions vraća jednu stranicu korisnika sa datom reakcijom na blog;
+// niz reakcija se filtrira i seče u bazi.
+func (r *Repository) GetReactions(blogID, reactionType string, offset, limit int) (ReactionPage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objID, err := parseBlogID(blogID)
+	if err != nil {
+		return ReactionPage{}, err
+	}
+
+	users := bson.M{"$map": bson.M{
+		"input": bson.M{"$filter": bson.M{
+			"input": bson.M{"$ifNull": bson.A{"$reactions", bson.A{}}},
+			"cond":  bson.M{"$eq": bson.A{"$$this.type", reactionType}},
+		}},
+		"in": "$$this.userId",
+	}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: live(bson.M{"_id": objID})}},
+		{{Key: "$project", Value: bson.M{
+			"total": bson.M{"$ifNull": bson.A{"$reactionCounts." + reactionType, 0}},
+			"users": bson.M{"$slice": bson.A{users, offset, limit}},
+		}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return ReactionPage{}, err
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		if err := cursor.Err(); err != nil {
+			return ReactionPage{}, err
+		}
+		return ReactionPage{}, ErrBlogNotFound
+	}
+	var row struct {
+		Total int      `bson:"total"`
+		Users []string `bson:"users"`
+	}
+	if err := cursor.Decode(&row); err != nil {
+		return ReactionPage{}, err
+	}
+
+	page := ReactionPage{
+		BlogID: blogID,
+		Type:   reactionType,
+		Total:  row.Total,
+		Limit:  limit,
+		Offset: offset,
+		Users:  row.Users,
+	}
+	if page.Users == nil {
+		page.Users = []string{}
+	}
+	return page, nil
+}
+
+// currentReaction čita samo reakciju datog korisnika, ne ceo niz.
+func (r *Repository) currentReaction(ctx context.Context, objID primitive.ObjectID, target Target, userID string) (string, error) {
+	var projection bson.M
+	if target.isComment() {
+		projection = bson.M{"comments": bson.M{"$elemMatch": live(bson.M{"id": target.CommentID})}}
+	} else {
+		projection = bson.M{"reactions": bson.M{"$elemMatch": bson.M{"userId": userID}}}
+	}
+
+	var blog Blog
+	err := r.collection.FindOne(ctx, live(bson.M{"_id": objID}), options.FindOne().SetProjection(projection)).Decode(&blog)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", ErrBlogNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	reactions := blog.Reactions
+	if target.isComment() {
+		if len(blog.Comments) == 0 {
+			return "", ErrCommentNotFound
+		}
+		reactions = blog.Comments[0].Reactions
+	}
+	for _, reaction := range reactions {
+		if reaction.UserID == userID {
+			return reaction.Type, nil
+		}
+	}
+	return "", nil
+}
+
+// updateReaction vraća false ako uslov iz filtera više ne važi.
+func (r *Repository) updateReaction(ctx context.Context, filter, update bson.M, arrayFilters []any) (bool, error) {
+	opts := options.Update()
+	if len(arrayFilters) > 0 {
+		opts.SetArrayFilters(options.ArrayFilters{Filters: arrayFilters})
+	}
+	result, err := r.collection.UpdateOne(ctx, filter, bump(update), opts)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// reactionFilter primenjuje uslov nad nizom reakcija bloga ili komentara.
+func reactionFilter(objID primitive.ObjectID, target Target, condition bson.M) bson.M {
+	if target.isComment() {
+		return live(bson.M{
+			"_id": objID,
+			"comments": bson.M{"$elemMatch": live(bson.M{
+				"id":        target.CommentID,
+				"reactions": condition,
+			})},
+		})
+	}
+	return live(bson.M{"_id": objID, "reactions": condition})
+}
+
+// reactionPrefix je putanja do polja reakcija; komentar se bira preko $[c].
+func reactionPrefix(target Target) string {
+	if target.isComment() {
+		return "comments.$[c]."
+	}
+	return ""
+}
+
+func commentArrayFilters(target Target) []any {
+	if target.isComment() {
+		return []any{bson.M{"c.id": target.CommentID}}
+	}
+	return nil
+}
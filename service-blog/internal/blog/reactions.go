@@ -0,0 +1,54 @@
+package blog
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ReactionLike je reakcija iza starih /blogs/like ruta i zato je uvek dozvoljena.
+const ReactionLike = "like"
+
+// reactionTypePattern čuva tipove bezbednim za upotrebu u putanji polja
+// ("reactionCounts.<tip>").
+var reactionTypePattern = regexp.MustCompile(`^[a-z][a-z_]{0,31}$`)
+
+var (
+	reactionTypesMu sync.RWMutex
+	reactionTypes   = []string{ReactionLike, "love", "insightful"}
+)
+
+// SetReactionTypes menja skup dozvoljenih reakcija za ovu instalaciju
+// (BLOG_REACTIONS). Neispravni nazivi se preskaču, a "like" se uvek dodaje.
+func SetReactionTypes(types []string) {
+	allowed := []string{ReactionLike}
+	seen := map[string]bool{ReactionLike: true}
+	for _, t := range types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if seen[t] || !reactionTypePattern.MatchString(t) {
+			continue
+		}
+		seen[t] = true
+		allowed = append(allowed, t)
+	}
+
+	reactionTypesMu.Lock()
+	reactionTypes = allowed
+	reactionTypesMu.Unlock()
+}
+
+// ReactionTypes vraća dozvoljene reakcije redom kojim su zadate.
+func ReactionTypes() []string {
+	reactionTypesMu.RLock()
+	defer reactionTypesMu.RUnlock()
+	return append([]string(nil), reactionTypes...)
+}
+
+func validReactionType(reactionType string) bool {
+	for _, t := range ReactionTypes() {
+		if t == reactionType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package blog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MongoReportRepository čuva prijave u kolekciji reports; jedinstvenost
+// otvorene prijave obezbeđuje parcijalni unique indeks iz migracija.
+type MongoReportRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoReportRepository(db *mongo.Database) *MongoReportRepository {
+	return &MongoReportRepository{collection: db.Collection("reports")}
+}
+
+func (r *MongoReportRepository) CreateReport(report Report) (Report, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.InsertOne(ctx, report)
+	if mongo.IsDuplicateKeyError(err) {
+		return Report{}, ErrDuplicateReport
+	}
+	if err != nil {
+		return Report{}, err
+	}
+	report.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return report, nil
+}
+
+func (r *MongoReportRepository) GetReport(id string) (Report, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objID, err := parseReportID(id)
+	if err != nil {
+		return Report{}, err
+	}
+
+	var report Report
+	err = r.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&report)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Report{}, ErrReportNotFound
+	}
+	return report, err
+}
+
+func (r *MongoReportRepository) ListReports(status string, offset, limit int) (ReportPage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"status": status}
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return ReportPage{}, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return ReportPage{}, err
+	}
+	defer cursor.Close(ctx)
+
+	page := ReportPage{Status: status, Total: int(total), Limit: limit, Offset: offset, Reports: []Report{}}
+	if err := cursor.All(ctx, &page.Reports); err != nil {
+		return ReportPage{}, err
+	}
+	return page, nil
+}
+
+func (r *MongoReportRepository) ResolveReport(id string, resolution Resolution) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objID, err := parseReportID(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objID, "status": ReportOpen},
+		bson.M{"$set": bson.M{"status": ReportResolved, "resolution": resolution}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrReportResolved
+	}
+	return nil
+}
+
+func (r *MongoReportRepository) ResolveTarget(target Target, resolution Resolution) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"blogId": target.BlogID, "status": ReportOpen}
+	if target.isComment() {
+		filter["commentId"] = target.CommentID
+	} else {
+		filter["commentId"] = bson.M{"$exists": false}
+	}
+	result, err := r.collection.UpdateMany(ctx, filter,
+		bson.M{"$set": bson.M{"status": ReportResolved, "resolution": resolution}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func parseReportID(id string) (primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, ErrInvalidReportID
+	}
+	return objID, nil
+}
@@ -1,39 +1,183 @@
 package blog
 
 import (
-	"log"
 	"context"
+	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	 "go.mongodb.org/mongo-driver/bson/primitive"
 
 )
 
+// BlogRepository je skladište koje Service koristi. Repository ga
+// implementira nad MongoDB-om, a MemoryRepository u memoriji za testove.
+type BlogRepository interface {
+	// Create vraća ID novog bloga.
+	Create(blog Blog) (string, error)
+	// GetAll ne vraća zakazane blogove, osim autoru userID.
+	GetAll(userID string, filter BlogFilter) ([]Blog, error)
+	// Get vraća vidljiv blog sa reakcijom korisnika userID, kao GetAll.
+	Get(blogID, userID string) (Blog, error)
+	// Update menja sadržaj bloga samo ako mu je verzija i dalje version;
+	// inače vraća ErrVersionMismatch. DeleteBlog pod istim uslovom
+	// premešta blog u korpu korisnika deletedBy.
+	Update(blog Blog, version int64) error
+	DeleteBlog(blogID, deletedBy string, version int64) error
+	AddComment(blogID string, comment Comment) error
+	// EditComment menja tekst, pomene i vreme izmene vidljivog komentara.
+	EditComment(target Target, comment Comment) error
+	SetReaction(target Target, userID, reactionType string) error
+	// RemoveReaction briše reakciju korisnika; ako onlyType nije prazan,
+	// briše je samo ako je tog tipa.
+	RemoveReaction(target Target, userID, onlyType string) error
+	GetReactions(blogID, reactionType string, offset, limit int) (ReactionPage, error)
+	// TagCounts i TrendingTags vraćaju tagove od najčešćeg; TrendingTags
+	// broji samo blogove kreirane posle since.
+	TagCounts(limit int) ([]TagCount, error)
+	TrendingTags(since time.Time, limit int) ([]TagCount, error)
+	// UnlinkTour uklanja turu i ključne tačke iz svih blogova koji na nju
+	// upućuju i vraća broj izmenjenih blogova.
+	UnlinkTour(tourID int64) (int64, error)
+	// ClaimPublication objavljuje jedan zakazan blog čije je vreme prošlo i
+	// zakupljuje ga na lease za slanje događaja; vraća false kada takvog
+	// nema. Blog čiji zakup istekne bez CompletePublication ponovo se
+	// vraća, pa se događaj šalje bar jednom.
+	ClaimPublication(now time.Time, lease time.Duration) (Blog, bool, error)
+	CompletePublication(blogID string) error
+	// ContentAuthor vraća autora vidljivog bloga ili komentara.
+	ContentAuthor(target Target) (string, error)
+	// Hide sakriva blog ili komentar; sakriven komentar se ne broji u commentCount.
+	Hide(target Target) error
+	// Delete premešta blog ili komentar u korpu korisnika deletedBy; sadržaj
+	// u korpi ne vraća nijedan drugi upit.
+	Delete(target Target, deletedBy string) error
+	// Restore vraća sadržaj iz korpe; prazan deletedBy vraća ga bez obzira
+	// na to ko ga je obrisao.
+	Restore(target Target, deletedBy string) error
+	// Trash vraća blogove i komentare koje je obrisao korisnik userID.
+	Trash(userID string) (Trash, error)
+	// PurgeDeleted trajno briše blogove i komentare obrisane do before i
+	// vraća ID-jeve trajno obrisanih blogova.
+	PurgeDeleted(before time.Time) ([]string, error)
+	// Unhide vraća sadržaj koji je filter zadržao ili moderator sakrio.
+	Unhide(target Target) error
+	// RecordActivity beleži objavu autora; Activity vraća nultu vrednost
+	// za autora koji još ništa nije objavio.
+	RecordActivity(username string) error
+	Activity(username string) (Activity, error)
+	// LastModified vraća najnoviji updatedAt u kolekciji, računajući i
+	// obrisane i sakrivene blogove.
+	LastModified() (time.Time, error)
+}
+
+// Activity opisuje autora u blog servisu; filter sadržaja po njoj
+// prepoznaje nove naloge.
+type Activity struct {
+	FirstSeenAt time.Time `bson:"firstSeenAt"`
+	Posts       int       `bson:"posts"`
+}
+
 type Repository struct {
 	collection *mongo.Collection
+	activity   *mongo.Collection
 }
 
 func NewRepository(db *mongo.Database) *Repository {
 	return &Repository{
 		collection: db.Collection("blogs"),
+		activity:   db.Collection("user_activity"),
 	}
 }
 
-func (r *Repository) Create(blog Blog) error {
+func (r *Repository) Create(blog Blog) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	_, err := r.collection.InsertOne(ctx, blog)
-	return err
+	result, err := r.collection.InsertOne(ctx, blog)
+	if err != nil {
+		return "", err
+	}
+	return result.InsertedID.(primitive.ObjectID).Hex(), nil
 }
 
-func (r *Repository) GetAll() ([]Blog, error) {
+// GetAll ne vraća nizove reakcija, samo brojače i reakciju korisnika
+// userID na blog i na svaki komentar (myReaction).
+func (r *Repository) GetAll(userID string, filter BlogFilter) ([]Blog, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	cursor, err := r.collection.Find(ctx, bson.M{})
+	match := live(visibleTo(bson.M{"hidden": bson.M{"$ne": true}}, userID))
+	if filter.Tag != "" {
+		match["tags"] = filter.Tag
+	}
+	if filter.TourID != 0 {
+		match["tourId"] = filter.TourID
+	}
+	return r.find(ctx, match, userID)
+}
+
+func (r *Repository) Get(blogID, userID string) (Blog, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objID, err := parseBlogID(blogID)
+	if err != nil {
+		return Blog{}, err
+	}
+	blogs, err := r.find(ctx, live(visibleTo(bson.M{"_id": objID, "hidden": bson.M{"$ne": true}}, userID)), userID)
+	if err != nil {
+		return Blog{}, err
+	}
+	if len(blogs) == 0 {
+		return Blog{}, ErrBlogNotFound
+	}
+	return blogs[0], nil
+}
+
+// visibleTo dodaje uslov da zakazan blog vidi samo autor.
+func visibleTo(match bson.M, userID string) bson.M {
+	if userID == "" {
+		match["scheduled"] = bson.M{"$ne": true}
+		return match
+	}
+	match["$or"] = bson.A{bson.M{"scheduled": bson.M{"$ne": true}}, bson.M{"author": userID}}
+	return match
+}
+
+// live dodaje uslov da blog ili komentar nije u korpi; prolaze kroz njega
+// svi upiti osim korpe, vraćanja iz nje i trajnog brisanja.
+func live(filter bson.M) bson.M {
+	filter["deletedAt"] = bson.M{"$exists": false}
+	return filter
+}
+
+// find vraća blogove bez sakrivenih i obrisanih komentara i bez nizova reakcija.
+func (r *Repository) find(ctx context.Context, match bson.M, userID string) ([]Blog, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$addFields", Value: bson.M{
+			"myReaction": reactionOf("$reactions", userID),
+			"comments": bson.M{"$map": bson.M{
+				"input": bson.M{"$filter": bson.M{
+					"input": bson.M{"$ifNull": bson.A{"$comments", bson.A{}}},
+					"cond": bson.M{"$and": bson.A{
+						bson.M{"$ne": bson.A{"$$this.hidden", true}},
+						bson.M{"$eq": bson.A{bson.M{"$type": "$$this.deletedAt"}, "missing"}},
+					}},
+				}},
+				"as": "c",
+				"in": bson.M{"$mergeObjects": bson.A{
+					"$$c",
+					bson.M{"myReaction": reactionOf("$$c.reactions", userID)},
+				}},
+			}},
+		}}},
+		{{Key: "$project", Value: bson.M{"reactions": 0, "comments.reactions": 0}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
 	if err != nil {
 		return nil, err
 	}
@@ -46,55 +190,443 @@ func (r *Repository) GetAll() ([]Blog, error) {
 	return blogs, nil
 }
 
-func (r *Repository) AddLike(blogID, userID string) error {
+// reactionOf je izraz koji vraća tip reakcije korisnika iz niza reakcija,
+// ili ništa ako korisnik nije reagovao.
+func reactionOf(reactions string, userID string) bson.M {
+	return bson.M{"$arrayElemAt": bson.A{
+		bson.M{"$map": bson.M{
+			"input": bson.M{"$filter": bson.M{
+				"input": bson.M{"$ifNull": bson.A{reactions, bson.A{}}},
+				"cond":  bson.M{"$eq": bson.A{"$$this.userId", userID}},
+			}},
+			"in": "$$this.type",
+		}},
+		0,
+	}}
+}
+
+func (r *Repository) AddComment(blogID string, comment Comment) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	objID, err := primitive.ObjectIDFromHex(blogID)
+	objID, err := parseBlogID(blogID)
 	if err != nil {
 		return err
 	}
 
-	update := bson.M{"$addToSet": bson.M{"likes": userID}}
-	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
-	return err
+	// Zadržan komentar se ne broji dok ga moderator ne odobri.
+	inc := 1
+	if comment.Hidden {
+		inc = 0
+	}
+	update := bson.M{
+		"$push": bson.M{"comments": comment},
+		"$inc":  bson.M{"commentCount": inc},
+	}
+	result, err := r.collection.UpdateOne(ctx, live(bson.M{"_id": objID, "hidden": bson.M{"$ne": true}}), bump(update))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrBlogNotFound
+	}
+	return nil
 }
 
-func (r *Repository) RemoveLike(blogID, userID string) error {
+func (r *Repository) EditComment(target Target, comment Comment) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	objID, err := primitive.ObjectIDFromHex(blogID)
+	objID, err := parseBlogID(target.BlogID)
+	if err != nil {
+		return err
+	}
+
+	set := bson.M{"comments.$[c].text": comment.Text, "comments.$[c].mentions": comment.Mentions, "comments.$[c].modifiedAt": comment.ModifiedAt}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: commentArrayFilters(target)})
+	result, err := r.collection.UpdateOne(ctx,
+		live(bson.M{"_id": objID, "hidden": bson.M{"$ne": true}, "comments": bson.M{"$elemMatch": live(bson.M{"id": target.CommentID, "hidden": bson.M{"$ne": true}})}}),
+		bump(bson.M{"$set": set}),
+		opts,
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+func (r *Repository) Update(blog Blog, version int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objID, err := parseBlogID(blog.ID)
+	if err != nil {
+		return err
+	}
+
+	set := bson.M{"title": blog.Title, "description": blog.Description}
+	unset := bson.M{}
+	setOrUnset := func(field string, value any, empty bool) {
+		if empty {
+			unset[field] = ""
+		} else {
+			set[field] = value
+		}
+	}
+	setOrUnset("images", blog.Images, len(blog.Images) == 0)
+	setOrUnset("tags", blog.Tags, len(blog.Tags) == 0)
+	setOrUnset("tourId", blog.TourID, blog.TourID == 0)
+	setOrUnset("keyPointIds", blog.KeyPointIDs, len(blog.KeyPointIDs) == 0)
+	setOrUnset("mentions", blog.Mentions, len(blog.Mentions) == 0)
+	setOrUnset("publishAt", blog.PublishAt, blog.PublishAt == nil)
+	setOrUnset("scheduled", true, !blog.Scheduled)
+	if blog.Hidden {
+		set["hidden"] = true
+	}
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	result, err := r.collection.UpdateOne(ctx, live(bson.M{"_id": objID, "version": version, "hidden": bson.M{"$ne": true}}), bump(update))
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return r.versionMismatch(ctx, objID)
+	}
+	return nil
+}
+
+func (r *Repository) DeleteBlog(blogID, deletedBy string, version int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objID, err := parseBlogID(blogID)
+	if err != nil {
+		return err
+	}
+	result, err := r.collection.UpdateOne(ctx,
+		live(bson.M{"_id": objID, "version": version, "hidden": bson.M{"$ne": true}}),
+		bump(bson.M{"$set": bson.M{"deletedAt": time.Now(), "deletedBy": deletedBy}}),
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return r.versionMismatch(ctx, objID)
+	}
+	return nil
+}
+
+// versionMismatch razlikuje, posle neuspelog uslovnog upisa, blog koji je
+// u međuvremenu izmenjen od bloga koji ne postoji.
+func (r *Repository) versionMismatch(ctx context.Context, objID primitive.ObjectID) error {
+	n, err := r.collection.CountDocuments(ctx, live(bson.M{"_id": objID, "hidden": bson.M{"$ne": true}}))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrBlogNotFound
+	}
+	return ErrVersionMismatch
+}
+
+// UnlinkTour menja i blogove u korpi, da vraćen blog ne bi upućivao na
+// obrisanu turu.
+func (r *Repository) UnlinkTour(tourID int64) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	update := bson.M{"$unset": bson.M{"tourId": "", "keyPointIds": ""}}
+	result, err := r.collection.UpdateMany(ctx, bson.M{"tourId": tourID}, bump(update))
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// ClaimPublication je jedan atomičan findOneAndUpdate, pa isti blog ne
+// objavljuju dve replike; zakup pokriva slanje događaja.
+func (r *Repository) ClaimPublication(now time.Time, lease time.Duration) (Blog, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := live(bson.M{
+		"hidden": bson.M{"$ne": true},
+		"$or": bson.A{
+			bson.M{"scheduled": true, "publishAt": bson.M{"$lte": now}},
+			bson.M{"publishPending": true, "publishLease": bson.M{"$lte": now}},
+		},
+	})
+	update := bson.M{
+		"$unset": bson.M{"scheduled": ""},
+		"$set":   bson.M{"publishPending": true, "publishLease": now.Add(lease)},
+	}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"reactions": 0, "comments": 0})
+
+	var blog Blog
+	err := r.collection.FindOneAndUpdate(ctx, filter, bump(update), opts).Decode(&blog)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Blog{}, false, nil
+	}
+	if err != nil {
+		return Blog{}, false, err
+	}
+	return blog, true, nil
+}
+
+// CompletePublication ne ide kroz bump: polja zakupa se ne vraćaju
+// klijentu, pa ETag ostaje važeći.
+func (r *Repository) CompletePublication(blogID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objID, err := parseBlogID(blogID)
 	if err != nil {
 		return err
 	}
+	_, err = r.collection.UpdateOne(ctx,
+		bson.M{"_id": objID},
+		bson.M{"$unset": bson.M{"publishPending": "", "publishLease": ""}},
+	)
+	return err
+}
+
+func (r *Repository) ContentAuthor(target Target) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objID, err := parseBlogID(target.BlogID)
+	if err != nil {
+		return "", err
+	}
+
+	visible := bson.M{"$ne": true}
+	projection := bson.M{"author": 1}
+	if target.isComment() {
+		projection = bson.M{"comments": bson.M{"$elemMatch": live(bson.M{"id": target.CommentID, "hidden": visible})}}
+	}
+	var blog Blog
+	err = r.collection.FindOne(ctx, live(bson.M{"_id": objID, "hidden": visible}), options.FindOne().SetProjection(projection)).Decode(&blog)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return "", ErrBlogNotFound
+	}
+	if err != nil {
+		return "", err
+	}
 
-	update := bson.M{"$pull": bson.M{"likes": userID}}
-	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	if !target.isComment() {
+		return blog.Author, nil
+	}
+	if len(blog.Comments) == 0 {
+		return "", ErrCommentNotFound
+	}
+	return blog.Comments[0].UserID, nil
+}
+
+func (r *Repository) Hide(target Target) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objID, err := parseBlogID(target.BlogID)
 	if err != nil {
 		return err
 	}
 
+	if !target.isComment() {
+		result, err := r.collection.UpdateOne(ctx,
+			live(bson.M{"_id": objID, "hidden": bson.M{"$ne": true}}),
+			bump(bson.M{"$set": bson.M{"hidden": true}}),
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return ErrBlogNotFound
+		}
+		return nil
+	}
+
+	filter := live(bson.M{"_id": objID, "comments": bson.M{"$elemMatch": live(bson.M{"id": target.CommentID, "hidden": bson.M{"$ne": true}})}})
+	update := bson.M{
+		"$set": bson.M{"comments.$[c].hidden": true},
+		"$inc": bson.M{"commentCount": -1},
+	}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: commentArrayFilters(target)})
+	result, err := r.collection.UpdateOne(ctx, filter, bump(update), opts)
+	if err != nil {
+		return err
+	}
 	if result.MatchedCount == 0 {
-		log.Println("⚠️  Nije pronađen blog sa tim ID-jem")
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+// Delete premešta blog ili komentar u korpu; commentCount se smanjuje samo
+// ako komentar nije bio sakriven.
+func (r *Repository) Delete(target Target, deletedBy string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objID, err := parseBlogID(target.BlogID)
+	if err != nil {
+		return err
 	}
-	if result.ModifiedCount == 0 {
-		log.Println("⚠️  Blog pronađen, ali lajk nije uklonjen (možda nije postojao?)")
+
+	now := time.Now()
+	if !target.isComment() {
+		result, err := r.collection.UpdateOne(ctx,
+			live(bson.M{"_id": objID}),
+			bump(bson.M{"$set": bson.M{"deletedAt": now, "deletedBy": deletedBy}}),
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return ErrBlogNotFound
+		}
+		return nil
 	}
 
+	set := bson.M{"comments.$[c].deletedAt": now, "comments.$[c].deletedBy": deletedBy}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: commentArrayFilters(target)})
+	result, err := r.collection.UpdateOne(ctx,
+		live(bson.M{"_id": objID, "comments": bson.M{"$elemMatch": live(bson.M{"id": target.CommentID, "hidden": bson.M{"$ne": true}})}}),
+		bump(bson.M{"$set": set, "$inc": bson.M{"commentCount": -1}}),
+		opts,
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+	result, err = r.collection.UpdateOne(ctx,
+		live(bson.M{"_id": objID, "comments": bson.M{"$elemMatch": live(bson.M{"id": target.CommentID})}}),
+		bump(bson.M{"$set": set}),
+		opts,
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrCommentNotFound
+	}
 	return nil
 }
-func (r *Repository) AddComment(blogID string, comment Comment) error {
+
+func (r *Repository) Unhide(target Target) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	objID, err := primitive.ObjectIDFromHex(blogID)
+	objID, err := parseBlogID(target.BlogID)
 	if err != nil {
 		return err
 	}
 
-	update := bson.M{"$push": bson.M{"comments": comment}}
-	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objID}, update)
+	if !target.isComment() {
+		result, err := r.collection.UpdateOne(ctx,
+			live(bson.M{"_id": objID, "hidden": true}),
+			bump(bson.M{"$unset": bson.M{"hidden": ""}}),
+		)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return ErrBlogNotFound
+		}
+		return nil
+	}
+
+	filter := live(bson.M{"_id": objID, "comments": bson.M{"$elemMatch": live(bson.M{"id": target.CommentID, "hidden": true})}})
+	update := bson.M{
+		"$unset": bson.M{"comments.$[c].hidden": ""},
+		"$inc":   bson.M{"commentCount": 1},
+	}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: commentArrayFilters(target)})
+	result, err := r.collection.UpdateOne(ctx, filter, bump(update), opts)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+func (r *Repository) RecordActivity(username string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$setOnInsert": bson.M{"firstSeenAt": time.Now()},
+		"$inc":         bson.M{"posts": 1},
+	}
+	_, err := r.activity.UpdateOne(ctx, bson.M{"_id": username}, update, options.Update().SetUpsert(true))
 	return err
 }
 
+func (r *Repository) Activity(username string) (Activity, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var activity Activity
+	err := r.activity.FindOne(ctx, bson.M{"_id": username}).Decode(&activity)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Activity{}, nil
+	}
+	return activity, err
+}
+
+// LastModified čita updatedAt najskorije izmenjenog dokumenta, preko
+// indeksa updatedAt; bump ga pomera i pri brisanju i sakrivanju.
+func (r *Repository) LastModified() (time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var latest struct {
+		UpdatedAt time.Time `bson:"updatedAt"`
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "updatedAt", Value: -1}}).SetProjection(bson.M{"updatedAt": 1})
+	err := r.collection.FindOne(ctx, bson.M{}, opts).Decode(&latest)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return time.Time{}, nil
+	}
+	return latest.UpdatedAt, err
+}
+
+// bump dodaje izmeni povećanje verzije i novo vreme izmene; svaki upis u
+// blog prolazi kroz njega, da bi ETag zastareo.
+func bump(update bson.M) bson.M {
+	inc, _ := update["$inc"].(bson.M)
+	if inc == nil {
+		inc = bson.M{}
+		update["$inc"] = inc
+	}
+	inc["version"] = 1
+
+	set, _ := update["$set"].(bson.M)
+	if set == nil {
+		set = bson.M{}
+		update["$set"] = set
+	}
+	set["updatedAt"] = time.Now()
+	return update
+}
+
+// parseBlogID pretvara neispravan hex ID u domensku grešku umesto 500.
+func parseBlogID(blogID string) (primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(blogID)
+	if err != nil {
+		return primitive.NilObjectID, ErrInvalidBlogID
+	}
+	return objID, nil
+}
+
@@ -0,0 +1,522 @@
+//go:build integration
+
+// Integracioni testovi rade nad pravim MongoDB-om, u privremenoj bazi koja
+// se briše posle testa:
+//
+//	MONGO_TEST_URI=mongodb://localhost:27017 go test -tags integration ./internal/blog/
+//
+// Bez MONGO_TEST_URI testovi se preskaču.
+package blog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"blog-service/internal/migrations"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func integrationRepository(t *testing.T) *Repository {
+	t.Helper()
+	return NewRepository(integrationDatabase(t))
+}
+
+func integrationDatabase(t *testing.T) *mongo.Database {
+	t.Helper()
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI nije postavljen")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("konekcija: %v", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(context.Background())
+		t.Skipf("MongoDB nije dostupan: %v", err)
+	}
+
+	database := client.Database(fmt.Sprintf("blogDB_test_%d", time.Now().UnixNano()))
+	t.Cleanup(func() {
+		database.Drop(context.Background())
+		client.Disconnect(context.Background())
+	})
+
+	if err := migrations.Run(ctx, database); err != nil {
+		t.Fatalf("migracije: %v", err)
+	}
+	return database
+}
+
+func TestMongoRepository(t *testing.T) {
+	repo := integrationRepository(t)
+
+	if _, err := repo.Create(Blog{Author: "ana", Title: "Naslov", Description: "<p>opis</p>", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	blogs, err := repo.GetAll("marko", BlogFilter{})
+	if err != nil || len(blogs) != 1 {
+		t.Fatalf("GetAll = %d blogova, %v", len(blogs), err)
+	}
+	id := blogs[0].ID
+
+	blog := Target{BlogID: id}
+	for i := 0; i < 2; i++ {
+		if err := repo.SetReaction(blog, "marko", ReactionLike); err != nil {
+			t.Fatalf("SetReaction: %v", err)
+		}
+	}
+	comment := Comment{ID: "c1", UserID: "marko", Text: "Odlično", CreatedAt: time.Now(), ModifiedAt: time.Now()}
+	if err := repo.AddComment(id, comment); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+
+	blogs, _ = repo.GetAll("marko", BlogFilter{})
+	if blogs[0].ReactionCounts[ReactionLike] != 1 || blogs[0].MyReaction != ReactionLike || blogs[0].CommentCount != 1 {
+		t.Errorf("reactionCounts = %v, myReaction = %q, commentCount = %d", blogs[0].ReactionCounts, blogs[0].MyReaction, blogs[0].CommentCount)
+	}
+	if blogs[0].Reactions != nil {
+		t.Errorf("GetAll ne sme da vrati niz reakcija: %v", blogs[0].Reactions)
+	}
+
+	if err := repo.SetReaction(blog, "marko", "love"); err != nil {
+		t.Fatalf("SetReaction love: %v", err)
+	}
+	page, err := repo.GetReactions(id, "love", 0, 10)
+	if err != nil || page.Total != 1 || len(page.Users) != 1 {
+		t.Errorf("GetReactions = %+v, %v", page, err)
+	}
+	if page, _ = repo.GetReactions(id, ReactionLike, 0, 10); page.Total != 0 {
+		t.Errorf("posle promene tipa like = %d", page.Total)
+	}
+
+	onComment := Target{BlogID: id, CommentID: comment.ID}
+	if err := repo.SetReaction(onComment, "ana", "insightful"); err != nil {
+		t.Fatalf("SetReaction na komentar: %v", err)
+	}
+	if blogs, _ = repo.GetAll("ana", BlogFilter{}); blogs[0].Comments[0].MyReaction != "insightful" || blogs[0].Comments[0].ReactionCounts["insightful"] != 1 {
+		t.Errorf("komentar = %+v", blogs[0].Comments[0])
+	}
+	if err := repo.SetReaction(Target{BlogID: id, CommentID: "nema"}, "ana", "love"); !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("reakcija na nepostojeći komentar = %v, očekivano ErrCommentNotFound", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := repo.RemoveReaction(blog, "marko", ""); err != nil {
+			t.Fatalf("RemoveReaction: %v", err)
+		}
+	}
+	if blogs, _ = repo.GetAll("marko", BlogFilter{}); blogs[0].ReactionCounts["love"] != 0 || blogs[0].MyReaction != "" {
+		t.Errorf("posle uklanjanja reactionCounts = %v", blogs[0].ReactionCounts)
+	}
+	if err := repo.SetReaction(Target{BlogID: missingBlogID}, "marko", ReactionLike); !errors.Is(err, ErrBlogNotFound) {
+		t.Errorf("reakcija na nepostojeći blog = %v, očekivano ErrBlogNotFound", err)
+	}
+	if err := repo.SetReaction(Target{BlogID: "abc"}, "marko", ReactionLike); !errors.Is(err, ErrInvalidBlogID) {
+		t.Errorf("reakcija sa neispravnim ID-jem = %v, očekivano ErrInvalidBlogID", err)
+	}
+}
+
+func TestMongoTags(t *testing.T) {
+	repo := integrationRepository(t)
+
+	now := time.Now()
+	blogs := []Blog{
+		{Author: "ana", Title: "A", Description: "a", CreatedAt: now, Tags: []string{"kopaonik", "skijanje"}},
+		{Author: "ana", Title: "B", Description: "b", CreatedAt: now, Tags: []string{"kopaonik"}},
+		{Author: "ana", Title: "C", Description: "c", CreatedAt: now.Add(-60 * 24 * time.Hour), Tags: []string{"skijanje"}},
+		{Author: "ana", Title: "D", Description: "d", CreatedAt: now},
+		{Author: "ana", Title: "E", Description: "e", CreatedAt: now.Add(-60 * 24 * time.Hour), PublishAt: &now, Tags: []string{"tara"}},
+	}
+	for _, blog := range blogs {
+		if _, err := repo.Create(blog); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	found, err := repo.GetAll("", BlogFilter{Tag: "skijanje"})
+	if err != nil || len(found) != 2 {
+		t.Errorf("GetAll(skijanje) = %d blogova, %v", len(found), err)
+	}
+
+	tags, err := repo.TagCounts(10)
+	want := []TagCount{{Tag: "kopaonik", Count: 2}, {Tag: "skijanje", Count: 2}, {Tag: "tara", Count: 1}}
+	if err != nil || !slices.Equal(tags, want) {
+		t.Errorf("TagCounts = %v, %v; očekivano %v", tags, err, want)
+	}
+
+	tags, err = repo.TrendingTags(now.Add(-7*24*time.Hour), 1)
+	want = []TagCount{{Tag: "kopaonik", Count: 2}}
+	if err != nil || !slices.Equal(tags, want) {
+		t.Errorf("TrendingTags = %v, %v; očekivano %v", tags, err, want)
+	}
+
+	// E je napravljen pre 60 dana, ali objavljen sada.
+	tags, err = repo.TrendingTags(now.Add(-7*24*time.Hour), 10)
+	want = []TagCount{{Tag: "kopaonik", Count: 2}, {Tag: "skijanje", Count: 1}, {Tag: "tara", Count: 1}}
+	if err != nil || !slices.Equal(tags, want) {
+		t.Errorf("TrendingTags(10) = %v, %v; očekivano %v", tags, err, want)
+	}
+}
+
+func TestMongoTourLinks(t *testing.T) {
+	repo := integrationRepository(t)
+
+	for _, tourID := range []int64{7, 7, 8, 0} {
+		blog := Blog{Author: "ana", Title: "T", Description: "t", CreatedAt: time.Now(), TourID: tourID}
+		if tourID != 0 {
+			blog.KeyPointIDs = []int64{1}
+		}
+		if _, err := repo.Create(blog); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	if blogs, err := repo.GetAll("", BlogFilter{TourID: 7}); err != nil || len(blogs) != 2 {
+		t.Errorf("GetAll(tura 7) = %d blogova, %v", len(blogs), err)
+	}
+	if n, err := repo.UnlinkTour(7); err != nil || n != 2 {
+		t.Errorf("UnlinkTour = %d, %v", n, err)
+	}
+	if blogs, _ := repo.GetAll("", BlogFilter{TourID: 7}); len(blogs) != 0 {
+		t.Errorf("posle UnlinkTour tura 7 ima %d blogova", len(blogs))
+	}
+	if blogs, _ := repo.GetAll("", BlogFilter{TourID: 8}); len(blogs) != 1 || len(blogs[0].KeyPointIDs) != 1 {
+		t.Errorf("tura 8 = %+v", blogs)
+	}
+}
+
+func TestMongoModeration(t *testing.T) {
+	database := integrationDatabase(t)
+	repo := NewRepository(database)
+	reports := NewMongoReportRepository(database)
+
+	if _, err := repo.Create(Blog{Author: "ana", Title: "A", Description: "a", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	blogs, _ := repo.GetAll("", BlogFilter{})
+	id := blogs[0].ID
+	for _, c := range []string{"c1", "c2"} {
+		if err := repo.AddComment(id, Comment{ID: c, UserID: "marko", Text: c, CreatedAt: time.Now(), ModifiedAt: time.Now()}); err != nil {
+			t.Fatalf("AddComment: %v", err)
+		}
+	}
+
+	c1 := Target{BlogID: id, CommentID: "c1"}
+	if author, err := repo.ContentAuthor(c1); err != nil || author != "marko" {
+		t.Errorf("ContentAuthor = %q, %v", author, err)
+	}
+
+	report := Report{BlogID: id, CommentID: "c1", ReporterID: "ana", Reason: ReasonSpam, Status: ReportOpen, CreatedAt: time.Now()}
+	created, err := reports.CreateReport(report)
+	if err != nil {
+		t.Fatalf("CreateReport: %v", err)
+	}
+	if _, err := reports.CreateReport(report); !errors.Is(err, ErrDuplicateReport) {
+		t.Errorf("ponovljena prijava = %v, očekivano ErrDuplicateReport", err)
+	}
+
+	if err := repo.Hide(c1); err != nil {
+		t.Fatalf("Hide: %v", err)
+	}
+	if err := repo.Hide(c1); !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("ponovljen Hide = %v, očekivano ErrCommentNotFound", err)
+	}
+	blogs, _ = repo.GetAll("", BlogFilter{})
+	if len(blogs[0].Comments) != 1 || blogs[0].CommentCount != 1 {
+		t.Errorf("posle Hide komentari = %+v, commentCount = %d", blogs[0].Comments, blogs[0].CommentCount)
+	}
+
+	resolution := Resolution{Action: ActionHide, ModeratorID: "admin", At: time.Now()}
+	if err := reports.ResolveReport(created.ID, resolution); err != nil {
+		t.Fatalf("ResolveReport: %v", err)
+	}
+	if err := reports.ResolveReport(created.ID, resolution); !errors.Is(err, ErrReportResolved) {
+		t.Errorf("ponovljen ResolveReport = %v, očekivano ErrReportResolved", err)
+	}
+	if _, err := reports.CreateReport(report); err != nil {
+		t.Errorf("nova prijava posle zatvaranja stare: %v", err)
+	}
+	if page, err := reports.ListReports(ReportResolved, 0, 10); err != nil || page.Total != 1 || page.Reports[0].Resolution.ModeratorID != "admin" {
+		t.Errorf("ListReports(resolved) = %+v, %v", page, err)
+	}
+
+	if err := repo.Delete(c1, "admin"); err != nil {
+		t.Fatalf("Delete sakrivenog komentara: %v", err)
+	}
+	if err := repo.Delete(Target{BlogID: id, CommentID: "c2"}, "admin"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	blogs, _ = repo.GetAll("", BlogFilter{})
+	if len(blogs[0].Comments) != 0 || blogs[0].CommentCount != 0 {
+		t.Errorf("posle Delete komentari = %+v, commentCount = %d", blogs[0].Comments, blogs[0].CommentCount)
+	}
+
+	if err := repo.Hide(Target{BlogID: id}); err != nil {
+		t.Fatalf("Hide bloga: %v", err)
+	}
+	if blogs, _ = repo.GetAll("", BlogFilter{}); len(blogs) != 0 {
+		t.Errorf("sakriven blog je u listi")
+	}
+	if err := repo.AddComment(id, Comment{ID: "c3", UserID: "marko", Text: "x"}); !errors.Is(err, ErrBlogNotFound) {
+		t.Errorf("komentar na sakriven blog = %v, očekivano ErrBlogNotFound", err)
+	}
+
+	if err := repo.Unhide(Target{BlogID: id}); err != nil {
+		t.Fatalf("Unhide bloga: %v", err)
+	}
+	if err := repo.Unhide(Target{BlogID: id}); !errors.Is(err, ErrBlogNotFound) {
+		t.Errorf("ponovljen Unhide = %v, očekivano ErrBlogNotFound", err)
+	}
+	if blogs, _ = repo.GetAll("", BlogFilter{}); len(blogs) != 1 {
+		t.Errorf("odobren blog nije u listi")
+	}
+
+	for range 2 {
+		if err := repo.RecordActivity("marko"); err != nil {
+			t.Fatalf("RecordActivity: %v", err)
+		}
+	}
+	if activity, err := repo.Activity("marko"); err != nil || activity.Posts != 2 || activity.FirstSeenAt.IsZero() {
+		t.Errorf("Activity = %+v, %v", activity, err)
+	}
+	if activity, err := repo.Activity("niko"); err != nil || activity.Posts != 0 {
+		t.Errorf("Activity nepoznatog autora = %+v, %v", activity, err)
+	}
+}
+
+func TestMongoVersioning(t *testing.T) {
+	repo := NewRepository(integrationDatabase(t))
+
+	id, err := repo.Create(Blog{Author: "ana", Title: "A", Description: "a", Tags: []string{"tara"}, CreatedAt: time.Now(), Version: 1})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := repo.AddComment(id, Comment{ID: "c1", UserID: "marko", Text: "x"}); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+	blog, err := repo.Get(id, "")
+	if err != nil || blog.Version != 2 || blog.UpdatedAt.IsZero() {
+		t.Fatalf("posle komentara Get = %+v, %v", blog, err)
+	}
+
+	if err := repo.Update(Blog{ID: id, Title: "B", Description: "b"}, 1); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("Update sa starom verzijom = %v, očekivano ErrVersionMismatch", err)
+	}
+	if err := repo.Update(Blog{ID: id, Title: "B", Description: "b"}, 2); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	blog, _ = repo.Get(id, "")
+	if blog.Title != "B" || blog.Version != 3 || blog.Tags != nil || len(blog.Comments) != 1 {
+		t.Errorf("posle Update = %+v", blog)
+	}
+
+	if err := repo.DeleteBlog(id, "ana", 2); !errors.Is(err, ErrVersionMismatch) {
+		t.Errorf("DeleteBlog sa starom verzijom = %v", err)
+	}
+	if err := repo.DeleteBlog(id, "ana", 3); err != nil {
+		t.Fatalf("DeleteBlog: %v", err)
+	}
+	if err := repo.Update(Blog{ID: id, Title: "C", Description: "c"}, 3); !errors.Is(err, ErrBlogNotFound) {
+		t.Errorf("Update obrisanog bloga = %v, očekivano ErrBlogNotFound", err)
+	}
+}
+
+func TestMongoRevisions(t *testing.T) {
+	revisions := NewMongoRevisionRepository(integrationDatabase(t))
+
+	for _, title := range []string{"A", "B", "C"} {
+		if _, err := revisions.AddRevision(Revision{BlogID: "b1", Editor: "ana", Title: title, Markdown: title, CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("AddRevision: %v", err)
+		}
+	}
+	if latest, err := revisions.LatestRevision("b1"); err != nil || latest.Number != 3 || latest.Title != "C" {
+		t.Errorf("LatestRevision = %+v, %v", latest, err)
+	}
+	page, err := revisions.ListRevisions("b1", 1, 10)
+	if err != nil || page.Total != 3 || len(page.Revisions) != 2 || page.Revisions[0].Number != 2 || page.Revisions[0].Markdown != "" {
+		t.Errorf("ListRevisions = %+v, %v", page, err)
+	}
+
+	if err := revisions.DeleteRevision("b1", 3); err != nil {
+		t.Fatalf("DeleteRevision: %v", err)
+	}
+	if rev, err := revisions.AddRevision(Revision{BlogID: "b1", Title: "D"}); err != nil || rev.Number != 3 {
+		t.Errorf("AddRevision posle brisanja = %+v, %v", rev, err)
+	}
+	if err := revisions.DeleteRevisions("b1"); err != nil {
+		t.Fatalf("DeleteRevisions: %v", err)
+	}
+	if _, err := revisions.GetRevision("b1", 1); !errors.Is(err, ErrRevisionNotFound) {
+		t.Errorf("GetRevision posle DeleteRevisions = %v", err)
+	}
+}
+
+func TestMongoScheduledPublishing(t *testing.T) {
+	repo := integrationRepository(t)
+
+	publishAt := time.Now().Add(time.Hour).UTC().Truncate(time.Millisecond)
+	id, err := repo.Create(Blog{Author: "ana", Title: "Sutra", Description: "d", Tags: []string{"tara"}, CreatedAt: time.Now(), Version: 1, PublishAt: &publishAt, Scheduled: true})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := repo.Get(id, "marko"); !errors.Is(err, ErrBlogNotFound) {
+		t.Errorf("zakazan blog za drugog korisnika: %v", err)
+	}
+	if blog, err := repo.Get(id, "ana"); err != nil || !blog.Scheduled {
+		t.Errorf("zakazan blog za autora = %+v, %v", blog, err)
+	}
+	if blogs, _ := repo.GetAll("", BlogFilter{Tag: "tara"}); len(blogs) != 0 {
+		t.Errorf("GetAll vraća zakazan blog: %+v", blogs)
+	}
+	if _, ok, err := repo.ClaimPublication(time.Now(), time.Minute); ok || err != nil {
+		t.Fatalf("ClaimPublication pre vremena: %v, %v", ok, err)
+	}
+
+	// Replike se takmiče za isti blog; preuzima ga tačno jedna.
+	due := publishAt.Add(time.Second)
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		claimed int
+	)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, ok, err := repo.ClaimPublication(due, time.Minute)
+			if err != nil {
+				t.Errorf("ClaimPublication: %v", err)
+			}
+			if ok {
+				mu.Lock()
+				claimed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if claimed != 1 {
+		t.Fatalf("blog preuzet %d puta", claimed)
+	}
+	if blog, err := repo.Get(id, "marko"); err != nil || blog.Scheduled || blog.Version != 2 {
+		t.Errorf("posle objave Get = %+v, %v", blog, err)
+	}
+
+	// Nezavršen zakup se ponovo preuzima tek kad istekne.
+	if _, ok, _ := repo.ClaimPublication(due.Add(30*time.Second), time.Minute); ok {
+		t.Error("preuzet blog pod važećim zakupom")
+	}
+	if blog, ok, _ := repo.ClaimPublication(due.Add(2*time.Minute), time.Minute); !ok || blog.ID != id {
+		t.Fatalf("istekao zakup nije preuzet: %+v, %v", blog, ok)
+	}
+	if err := repo.CompletePublication(id); err != nil {
+		t.Fatalf("CompletePublication: %v", err)
+	}
+	if _, ok, _ := repo.ClaimPublication(due.Add(time.Hour), time.Minute); ok {
+		t.Error("blog preuzet posle CompletePublication")
+	}
+}
+
+func TestMongoTrash(t *testing.T) {
+	database := integrationDatabase(t)
+	repo := NewRepository(database)
+
+	id, err := repo.Create(Blog{Author: "ana", Title: "A", Description: "a", Tags: []string{"tara"}, CreatedAt: time.Now(), Version: 1})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	for _, c := range []Comment{{ID: "c1", UserID: "marko", Text: "x"}, {ID: "c2", UserID: "jova", Text: "y"}} {
+		if err := repo.AddComment(id, c); err != nil {
+			t.Fatalf("AddComment: %v", err)
+		}
+	}
+	c1 := Target{BlogID: id, CommentID: "c1"}
+
+	if err := repo.Delete(c1, "marko"); err != nil {
+		t.Fatalf("Delete komentara: %v", err)
+	}
+	if err := repo.SetReaction(c1, "ana", ReactionLike); !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("reakcija na obrisan komentar = %v", err)
+	}
+	blog, _ := repo.Get(id, "")
+	if len(blog.Comments) != 1 || blog.CommentCount != 1 {
+		t.Errorf("posle brisanja komentara = %+v", blog)
+	}
+	trash, err := repo.Trash("marko")
+	if err != nil || len(trash.Comments) != 1 || trash.Comments[0].BlogTitle != "A" || trash.Comments[0].Comment.DeletedBy != "marko" {
+		t.Fatalf("Trash(marko) = %+v, %v", trash, err)
+	}
+	if err := repo.Restore(c1, "ana"); !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("vraćanje tuđeg brisanja = %v", err)
+	}
+	if err := repo.Restore(c1, "marko"); err != nil {
+		t.Fatalf("Restore komentara: %v", err)
+	}
+	if blog, _ = repo.Get(id, ""); len(blog.Comments) != 2 || blog.CommentCount != 2 {
+		t.Errorf("posle vraćanja komentara = %+v", blog)
+	}
+
+	if err := repo.DeleteBlog(id, "ana", blog.Version); err != nil {
+		t.Fatalf("DeleteBlog: %v", err)
+	}
+	if blogs, _ := repo.GetAll("ana", BlogFilter{}); len(blogs) != 0 {
+		t.Errorf("GetAll vraća obrisan blog")
+	}
+	if tags, _ := repo.TagCounts(10); len(tags) != 0 {
+		t.Errorf("tagovi obrisanog bloga se broje: %+v", tags)
+	}
+	if err := repo.AddComment(id, Comment{ID: "c3", UserID: "marko", Text: "z"}); !errors.Is(err, ErrBlogNotFound) {
+		t.Errorf("komentar na obrisan blog = %v", err)
+	}
+	if trash, _ = repo.Trash("ana"); len(trash.Blogs) != 1 || trash.Blogs[0].DeletedAt == nil || trash.Blogs[0].Comments != nil {
+		t.Errorf("Trash(ana) = %+v", trash)
+	}
+	if err := repo.Restore(Target{BlogID: id}, ""); err != nil {
+		t.Fatalf("Restore bloga: %v", err)
+	}
+	if _, err := repo.Get(id, ""); err != nil {
+		t.Errorf("vraćen blog: %v", err)
+	}
+
+	// Trajno brisanje: blog i komentar obrisani pre granice nestaju.
+	blog, _ = repo.Get(id, "")
+	if err := repo.Delete(Target{BlogID: id, CommentID: "c2"}, "jova"); err != nil {
+		t.Fatal(err)
+	}
+	if purged, err := repo.PurgeDeleted(time.Now()); err != nil || len(purged) != 0 {
+		t.Fatalf("PurgeDeleted bez blogova = %v, %v", purged, err)
+	}
+	var raw struct {
+		Comments []Comment `bson:"comments"`
+	}
+	objID, _ := parseBlogID(id)
+	database.Collection("blogs").FindOne(context.Background(), map[string]any{"_id": objID}).Decode(&raw)
+	if len(raw.Comments) != 1 || raw.Comments[0].ID != "c1" {
+		t.Errorf("posle PurgeDeleted komentari = %+v", raw.Comments)
+	}
+
+	if err := repo.Delete(Target{BlogID: id}, "ana"); err != nil {
+		t.Fatal(err)
+	}
+	if purged, _ := repo.PurgeDeleted(time.Now().Add(-time.Hour)); len(purged) != 0 {
+		t.Errorf("obrisan pre isteka: %v", purged)
+	}
+	if purged, err := repo.PurgeDeleted(time.Now()); err != nil || !slices.Equal(purged, []string{id}) {
+		t.Errorf("PurgeDeleted = %v, %v", purged, err)
+	}
+	if err := repo.Restore(Target{BlogID: id}, ""); !errors.Is(err, ErrBlogNotFound) {
+		t.Errorf("vraćanje trajno obrisanog = %v", err)
+	}
+}
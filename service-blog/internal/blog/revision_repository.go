@@ -0,0 +1,119 @@
+package blog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// maxRevisionAttempts ograničava ponavljanja kada dve izmene istovremeno
+// uzmu isti broj revizije; unique indeks (blogId, rev) pušta samo jednu.
+const maxRevisionAttempts = 3
+
+// MongoRevisionRepository čuva revizije u kolekciji blog_revisions.
+type MongoRevisionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewMongoRevisionRepository(db *mongo.Database) *MongoRevisionRepository {
+	return &MongoRevisionRepository{collection: db.Collection("blog_revisions")}
+}
+
+func (r *MongoRevisionRepository) AddRevision(rev Revision) (Revision, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for attempt := 0; attempt < maxRevisionAttempts; attempt++ {
+		latest, err := r.latest(ctx, rev.BlogID)
+		if err != nil && !errors.Is(err, ErrRevisionNotFound) {
+			return Revision{}, err
+		}
+		rev.Number = latest.Number + 1
+
+		_, err = r.collection.InsertOne(ctx, rev)
+		if mongo.IsDuplicateKeyError(err) {
+			continue
+		}
+		if err != nil {
+			return Revision{}, err
+		}
+		return rev, nil
+	}
+	return Revision{}, errRevisionConflict
+}
+
+func (r *MongoRevisionRepository) GetRevision(blogID string, number int) (Revision, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var rev Revision
+	err := r.collection.FindOne(ctx, bson.M{"blogId": blogID, "rev": number}).Decode(&rev)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Revision{}, ErrRevisionNotFound
+	}
+	return rev, err
+}
+
+func (r *MongoRevisionRepository) LatestRevision(blogID string) (Revision, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return r.latest(ctx, blogID)
+}
+
+func (r *MongoRevisionRepository) latest(ctx context.Context, blogID string) (Revision, error) {
+	var rev Revision
+	opts := options.FindOne().SetSort(bson.D{{Key: "rev", Value: -1}})
+	err := r.collection.FindOne(ctx, bson.M{"blogId": blogID}, opts).Decode(&rev)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return Revision{}, ErrRevisionNotFound
+	}
+	return rev, err
+}
+
+func (r *MongoRevisionRepository) ListRevisions(blogID string, offset, limit int) (RevisionPage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"blogId": blogID}
+	total, err := r.collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return RevisionPage{}, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "rev", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"markdown": 0})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return RevisionPage{}, err
+	}
+	defer cursor.Close(ctx)
+
+	page := RevisionPage{BlogID: blogID, Total: int(total), Limit: limit, Offset: offset, Revisions: []Revision{}}
+	if err := cursor.All(ctx, &page.Revisions); err != nil {
+		return RevisionPage{}, err
+	}
+	return page, nil
+}
+
+func (r *MongoRevisionRepository) DeleteRevision(blogID string, number int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := r.collection.DeleteOne(ctx, bson.M{"blogId": blogID, "rev": number})
+	return err
+}
+
+func (r *MongoRevisionRepository) DeleteRevisions(blogID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := r.collection.DeleteMany(ctx, bson.M{"blogId": blogID})
+	return err
+}
@@ -0,0 +1,227 @@
+package blog
+
+import (
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultRevisionPageSize = 20
+	MaxRevisionPageSize     = 100
+)
+
+// Revision je sadržaj bloga posle jedne izmene. Markdown je izvorni tekst
+// koji je autor poslao (posle maskiranja), a ne renderovan HTML; revizije
+// blogova starijih od istorije imaju HTML, koji markdown renderer propušta
+// nepromenjen. RestoredFrom je broj revizije koja je vraćena, ako jeste.
+type Revision struct {
+	BlogID       string    `json:"blogId" bson:"blogId"`
+	Number       int       `json:"rev" bson:"rev"`
+	Editor       string    `json:"editor" bson:"editor"`
+	CreatedAt    time.Time `json:"createdAt" bson:"createdAt"`
+	Title        string    `json:"title" bson:"title"`
+	Markdown     string    `json:"markdown,omitempty" bson:"markdown"`
+	Images       []string  `json:"images,omitempty" bson:"images,omitempty"`
+	Tags         []string  `json:"tags,omitempty" bson:"tags,omitempty"`
+	TourID       int64     `json:"tourId,omitempty" bson:"tourId,omitempty"`
+	KeyPointIDs  []int64   `json:"keyPointIds,omitempty" bson:"keyPointIds,omitempty"`
+	RestoredFrom int       `json:"restoredFrom,omitempty" bson:"restoredFrom,omitempty"`
+}
+
+// RevisionPage je stranica istorije, od najnovije revizije; revizije u
+// listi nemaju Markdown.
+type RevisionPage struct {
+	BlogID    string     `json:"blogId"`
+	Total     int        `json:"total"`
+	Limit     int        `json:"limit"`
+	Offset    int        `json:"offset"`
+	Revisions []Revision `json:"revisions"`
+}
+
+// RevisionDiff poredi dve revizije red po red.
+type RevisionDiff struct {
+	BlogID    string     `json:"blogId"`
+	From      int        `json:"from"`
+	To        int        `json:"to"`
+	FromTitle string     `json:"fromTitle"`
+	ToTitle   string     `json:"toTitle"`
+	Lines     []DiffLine `json:"lines"`
+}
+
+// RevisionRepository čuva istoriju izmena u kolekciji blog_revisions.
+type RevisionRepository interface {
+	// AddRevision dodeljuje reviziji sledeći broj za njen blog.
+	AddRevision(rev Revision) (Revision, error)
+	GetRevision(blogID string, number int) (Revision, error)
+	// LatestRevision vraća ErrRevisionNotFound ako blog nema istoriju.
+	LatestRevision(blogID string) (Revision, error)
+	ListRevisions(blogID string, offset, limit int) (RevisionPage, error)
+	DeleteRevision(blogID string, number int) error
+	DeleteRevisions(blogID string) error
+}
+
+func newRevision(blog Blog, editor, markdown string) Revision {
+	return Revision{
+		BlogID:      blog.ID,
+		Editor:      editor,
+		CreatedAt:   time.Now(),
+		Title:       blog.Title,
+		Markdown:    markdown,
+		Images:      blog.Images,
+		Tags:        blog.Tags,
+		TourID:      blog.TourID,
+		KeyPointIDs: blog.KeyPointIDs,
+	}
+}
+
+// recordCreated čuva prvu reviziju novog bloga. Greška se samo loguje:
+// blog je već sačuvan, a istorija počinje od sledeće izmene.
+func (s *Service) recordCreated(blog Blog, markdown string) {
+	if _, err := s.revisions.AddRevision(newRevision(blog, blog.Author, markdown)); err != nil {
+		log.Printf("❌ Prva revizija bloga %s nije sačuvana: %s", blog.ID, err)
+	}
+}
+
+// Revisions i Diff vide samo autor i administratori.
+func (s *Service) Revisions(blogID, userID string, admin bool, offset, limit int) (RevisionPage, error) {
+	if err := s.canSeeHistory(blogID, userID, admin); err != nil {
+		return RevisionPage{}, err
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.revisions.ListRevisions(blogID, offset, clampLimit(limit, DefaultRevisionPageSize, MaxRevisionPageSize))
+}
+
+func (s *Service) Revision(blogID, userID string, admin bool, number int) (Revision, error) {
+	if err := s.canSeeHistory(blogID, userID, admin); err != nil {
+		return Revision{}, err
+	}
+	return s.revisions.GetRevision(blogID, number)
+}
+
+// Diff poredi revizije from i to; nula za to znači poslednju reviziju, a
+// za from reviziju pre to.
+func (s *Service) Diff(blogID, userID string, admin bool, from, to int) (RevisionDiff, error) {
+	if err := s.canSeeHistory(blogID, userID, admin); err != nil {
+		return RevisionDiff{}, err
+	}
+
+	var newer Revision
+	var err error
+	if to == 0 {
+		newer, err = s.revisions.LatestRevision(blogID)
+	} else {
+		newer, err = s.revisions.GetRevision(blogID, to)
+	}
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+	if from == 0 {
+		from = max(newer.Number-1, 1)
+	}
+	older, err := s.revisions.GetRevision(blogID, from)
+	if err != nil {
+		return RevisionDiff{}, err
+	}
+
+	return RevisionDiff{
+		BlogID:    blogID,
+		From:      older.Number,
+		To:        newer.Number,
+		FromTitle: older.Title,
+		ToTitle:   newer.Title,
+		Lines:     lineDiff(older.Markdown, newer.Markdown),
+	}, nil
+}
+
+// Restore vraća sadržaj revizije number kao novu izmenu bloga, sa istim
+// proverama i If-Match verzijom kao Update.
+func (s *Service) Restore(blogID string, number int, userID string, version int64) (Blog, error) {
+	rev, err := s.revisions.GetRevision(blogID, number)
+	if err != nil {
+		return Blog{}, err
+	}
+	changes := Blog{
+		ID:          blogID,
+		Title:       rev.Title,
+		Description: rev.Markdown,
+		Images:      rev.Images,
+		Tags:        rev.Tags,
+		TourID:      rev.TourID,
+		KeyPointIDs: rev.KeyPointIDs,
+	}
+	return s.update(changes, userID, version, number)
+}
+
+func (s *Service) canSeeHistory(blogID, userID string, admin bool) error {
+	blog, err := s.repo.Get(blogID, userID)
+	if err != nil {
+		return err
+	}
+	if blog.Author != userID && !admin {
+		return ErrNotBlogAuthor
+	}
+	return nil
+}
+
+// DiffLine je jedan red razlike: Op je "=" za nepromenjen, "-" za
+// uklonjen i "+" za dodat red.
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// lineDiff pravi najkraću razliku preko najduže zajedničke podsekvence
+// redova. Opis ima najviše 20000 znakova, pa je tabela O(n·m) mala.
+func lineDiff(a, b string) []DiffLine {
+	x, y := splitLines(a), splitLines(b)
+	n, m := len(x), len(y)
+
+	// lcs[i][j] je dužina zajedničke podsekvence x[i:] i y[j:].
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	lines := []DiffLine{}
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case x[i] == y[j]:
+			lines = append(lines, DiffLine{Op: "=", Text: x[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Op: "-", Text: x[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: "+", Text: y[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		lines = append(lines, DiffLine{Op: "-", Text: x[i]})
+	}
+	for ; j < m; j++ {
+		lines = append(lines, DiffLine{Op: "+", Text: y[j]})
+	}
+	return lines
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
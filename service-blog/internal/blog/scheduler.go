@@ -0,0 +1,90 @@
+package blog
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// DefaultPublishLease je vreme za koje replika mora da pošalje događaj
+// objave; posle toga ga preuzima druga.
+const DefaultPublishLease = time.Minute
+
+// PublishScheduler objavljuje zakazane blogove kada dođe njihov PublishAt.
+// Može da radi na više replika: blog preuzima atomični ClaimPublication.
+type PublishScheduler struct {
+	repo   BlogRepository
+	events EventPublisher
+	lease  time.Duration
+}
+
+// NewPublishScheduler: events može biti nil, tada se blogovi objavljuju
+// bez događaja.
+func NewPublishScheduler(repo BlogRepository, events EventPublisher) *PublishScheduler {
+	return &PublishScheduler{repo: repo, events: events, lease: DefaultPublishLease}
+}
+
+// Run proverava zakazane blogove na svakih interval dok se ctx ne otkaže.
+func (s *PublishScheduler) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		published, err := s.PublishDue(time.Now())
+		if published > 0 {
+			log.Printf("✅ Objavljeno zakazanih blogova: %d", published)
+		}
+		if err != nil {
+			log.Printf("⚠️ Objava zakazanih blogova: %s", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishDue objavljuje sve blogove kojima je vreme objave prošlo do now i
+// vraća njihov broj. Uz blog.published se šalju i pomeni iz bloga, jer
+// Create o zakazanom blogu ne obaveštava. Ako događaj ne može da se
+// pošalje, blog ostaje objavljen, a događaji se ponovo šalju kada zakup
+// istekne.
+func (s *PublishScheduler) PublishDue(now time.Time) (int, error) {
+	published := 0
+	for {
+		blog, ok, err := s.repo.ClaimPublication(now, s.lease)
+		if err != nil || !ok {
+			return published, err
+		}
+		if s.events != nil {
+			event := BlogPublished{
+				BlogID:      blog.ID,
+				Author:      blog.Author,
+				Title:       blog.Title,
+				Tags:        blog.Tags,
+				TourID:      blog.TourID,
+				PublishedAt: publishedAt(blog),
+			}
+			if err := s.events.Publish(RoutingKeyBlogPublished, event); err != nil {
+				return published, err
+			}
+			for _, mention := range mentionActivities(blog, blog.Author, "", blog.Mentions, event.PublishedAt) {
+				if err := s.events.Publish(RoutingKeyBlogMentioned, mention); err != nil {
+					return published, err
+				}
+			}
+		}
+		if err := s.repo.CompletePublication(blog.ID); err != nil {
+			return published, err
+		}
+		published++
+	}
+}
+
+func publishedAt(blog Blog) time.Time {
+	if blog.PublishAt != nil {
+		return *blog.PublishAt
+	}
+	return blog.CreatedAt
+}
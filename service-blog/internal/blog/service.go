@@ -1,46 +1,427 @@
 package blog
 
 import (
+	"log"
 	"time"
 
-	"github.com/gomarkdown/markdown"
-	"github.com/gomarkdown/markdown/html"
+	"blog-service/internal/contentfilter"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	DefaultReactionPageSize = 20
+	MaxReactionPageSize     = 100
 )
 
 type Service struct {
-	repo *Repository
+	repo       BlogRepository
+	revisions  RevisionRepository
+	tours      TourCatalog
+	users      UserDirectory
+	blocks     BlockList
+	filter     ContentFilter
+	moderation *ModerationService
+	events     EventPublisher
+	live       LiveFeed
 }
 
-func NewService(repo *Repository) *Service {
-	return &Service{repo: repo}
+// ServiceOptions su zavisnosti servisa bez kojih blogovi i dalje rade;
+// svako polje može ostati nil.
+type ServiceOptions struct {
+	// Tours proverava ture i ključne tačke; bez njega se blog vezan za
+	// turu odbija kao da service-tours nije dostupan.
+	Tours TourCatalog
+	// Users razrešava pomene; bez njega pomeni ostaju običan tekst.
+	Users UserDirectory
+	// Blocks čita blokade među korisnicima; bez njega se ne proveravaju.
+	Blocks BlockList
+	// Filter proverava sadržaj pre čuvanja; bez njega se sve propušta.
+	Filter ContentFilter
+	// Moderation prima sadržaj koji filter zadrži; bez njega se takav
+	// sadržaj odbija, jer nema ko da ga odobri.
+	Moderation *ModerationService
+	// Events objavljuje lajkove, komentare i pomene; bez njega se ne šalju.
+	Events EventPublisher
+	// Live šalje izmene blogova uživo; bez njega praćenje uživo nije dostupno.
+	Live LiveFeed
 }
 
-func (s *Service) Create(blog Blog) error {
+func NewService(repo BlogRepository, revisions RevisionRepository, opts ServiceOptions) *Service {
+	return &Service{
+		repo:       repo,
+		revisions:  revisions,
+		tours:      opts.Tours,
+		users:      opts.Users,
+		blocks:     opts.Blocks,
+		filter:     opts.Filter,
+		moderation: opts.Moderation,
+		events:     opts.Events,
+		live:       opts.Live,
+	}
+}
+
+// Create vraća blog onakav kakav je sačuvan: sa renderovanim opisom i
+// normalizovanim tagovima. Blog koji filter zadrži čuva se sakriven
+// (Hidden) dok ga moderator ne odobri. Blog sa PublishAt ostaje zakazan
+// dok ga PublishScheduler ne objavi. Pomenuti korisnici se obaveštavaju
+// kada blog postane vidljiv; o zadržanom blogu se ne obaveštavaju.
+func (s *Service) Create(blog Blog) (Blog, error) {
+	if err := schedule(&blog, time.Now()); err != nil {
+		return Blog{}, err
+	}
+	tags, err := normalizeTags(blog.Tags)
+	if err != nil {
+		return Blog{}, err
+	}
+	blog.Tags = tags
+	if err := s.validateTourLink(&blog); err != nil {
+		return Blog{}, err
+	}
+	decision, err := s.screen(contentfilter.KindBlog, blog.Author, blog.Title, blog.Description)
+	if err != nil {
+		return Blog{}, err
+	}
+	blog.Title, blog.Description = decision.Title, decision.Body
+	blog.Hidden = decision.Action == contentfilter.Hold
 	blog.CreatedAt = time.Now()
+	blog.UpdatedAt = blog.CreatedAt
+	blog.Version = 1
+	blog.Mentions = s.resolveMentions(blog.Author, markdownMentionNames(decision.Body))
+	blog.Description = renderMarkdown(decision.Body, blog.Mentions)
+
+	id, err := s.repo.Create(blog)
+	if err != nil {
+		return Blog{}, err
+	}
+	blog.ID = id
+	s.recordCreated(blog, decision.Body)
+	s.settle(Target{BlogID: id}, blog.Author, decision)
+	if !blog.Hidden && !blog.Scheduled {
+		s.notifyMentions(id, blog.Author, "", blog.Mentions)
+	}
+	return blog, nil
+}
+
+// Update menja naslov, opis, slike, tagove i turu bloga; ostala polja
+// zadržava. Uspeva samo ako je blog i dalje u verziji version, koju je
+// klijent dobio u ETag-u. Izmena prolazi iste provere kao Create, a
+// izmenu koju filter zadrži blog čeka sakriven. Svaka izmena je nova
+// revizija u istoriji. Obaveštavaju se samo novi pomenuti korisnici.
+func (s *Service) Update(changes Blog, userID string, version int64) (Blog, error) {
+	return s.update(changes, userID, version, 0)
+}
+
+// update: restoredFrom je broj vraćene revizije (Restore), inače nula.
+func (s *Service) update(changes Blog, userID string, version int64, restoredFrom int) (Blog, error) {
+	blog, err := s.repo.Get(changes.ID, userID)
+	if err != nil {
+		return Blog{}, err
+	}
+	if blog.Author != userID {
+		return Blog{}, ErrNotBlogAuthor
+	}
+	if version == anyVersion {
+		version = blog.Version
+	}
+	if blog.Version != version {
+		return Blog{}, ErrVersionMismatch
+	}
+	switch {
+	case changes.PublishAt == nil:
+		changes.PublishAt, changes.Scheduled = blog.PublishAt, blog.Scheduled
+	case !blog.Scheduled:
+		return Blog{}, ErrAlreadyPublished
+	default:
+		if err := schedule(&changes, time.Now()); err != nil {
+			return Blog{}, err
+		}
+	}
+
+	tags, err := normalizeTags(changes.Tags)
+	if err != nil {
+		return Blog{}, err
+	}
+	changes.Tags = tags
+	changes.Author = blog.Author
+	if err := s.validateTourLink(&changes); err != nil {
+		return Blog{}, err
+	}
+	decision, err := s.screen(contentfilter.KindBlog, blog.Author, changes.Title, changes.Description)
+	if err != nil {
+		return Blog{}, err
+	}
+	changes.Title = decision.Title
+	changes.Mentions = s.resolveMentions(blog.Author, markdownMentionNames(decision.Body))
+	changes.Description = renderMarkdown(decision.Body, changes.Mentions)
+	changes.Hidden = decision.Action == contentfilter.Hold
 
-	renderer := html.NewRenderer(html.RendererOptions{
-		Flags: html.CommonFlags,
-	})
-	mdHTML := markdown.ToHTML([]byte(blog.Description), nil, renderer)
-	blog.Description = string(mdHTML)
+	// Revizija se upisuje pre bloga, da primenjena izmena ne bi ostala van
+	// istorije; ako uslovni upis bloga ne uspe, revizija se briše.
+	rev := newRevision(changes, userID, decision.Body)
+	rev.RestoredFrom = restoredFrom
+	rev, err = s.revisions.AddRevision(rev)
+	if err != nil {
+		return Blog{}, err
+	}
+	if err := s.repo.Update(changes, version); err != nil {
+		if delErr := s.revisions.DeleteRevision(rev.BlogID, rev.Number); delErr != nil {
+			log.Printf("❌ Revizija %d bloga %s nije obrisana posle neuspele izmene: %s", rev.Number, rev.BlogID, delErr)
+		}
+		return Blog{}, err
+	}
+	if changes.Hidden {
+		if err := s.moderation.Hold(Target{BlogID: blog.ID}, blog.Author, decision); err != nil {
+			log.Printf("❌ Zadržana izmena bloga %s nije dodata u red za moderaciju: %s", blog.ID, err)
+		}
+	}
 
-	return s.repo.Create(blog)
+	added := mentionsAdded(changes.Mentions, blog.Mentions)
+	blog.Title, blog.Description, blog.Images, blog.Tags = changes.Title, changes.Description, changes.Images, changes.Tags
+	blog.Mentions = changes.Mentions
+	blog.TourID, blog.KeyPointIDs, blog.Hidden = changes.TourID, changes.KeyPointIDs, changes.Hidden
+	blog.PublishAt, blog.Scheduled = changes.PublishAt, changes.Scheduled
+	blog.Version = version + 1
+	blog.UpdatedAt = time.Now()
+	fillLikeFields(&blog)
+	if !blog.Hidden && !blog.Scheduled {
+		s.notifyMentions(blog.ID, userID, "", added)
+	}
+	return blog, nil
 }
 
-func (s *Service) GetAll() ([]Blog, error) {
-	return s.repo.GetAll()
+// schedule zakazuje blog za PublishAt; blog bez PublishAt se objavljuje odmah.
+func schedule(blog *Blog, now time.Time) error {
+	if blog.PublishAt == nil {
+		blog.Scheduled = false
+		return nil
+	}
+	if !blog.PublishAt.After(now) {
+		return ErrPublishAtPast
+	}
+	// Mongo čuva milisekunde; skraćivanje drži odgovor istim kao sačuvan blog.
+	publishAt := blog.PublishAt.UTC().Truncate(time.Millisecond)
+	blog.PublishAt = &publishAt
+	blog.Scheduled = true
+	return nil
 }
 
+// Delete premešta blog u verziji version u korpu korisnika userID; osim
+// autora to može i administrator. Istorija ostaje do trajnog brisanja.
+func (s *Service) Delete(blogID, userID string, admin bool, version int64) error {
+	blog, err := s.repo.Get(blogID, userID)
+	if err != nil {
+		return err
+	}
+	if blog.Author != userID && !admin {
+		return ErrNotBlogAuthor
+	}
+	if version == anyVersion {
+		version = blog.Version
+	}
+	return s.repo.DeleteBlog(blogID, userID, version)
+}
+
+// LastModified je vreme poslednje izmene bilo kog bloga, uključujući
+// brisanje i sakrivanje; služi kao Last-Modified liste blogova.
+func (s *Service) LastModified() (time.Time, error) {
+	return s.repo.LastModified()
+}
+
+func (s *Service) Get(blogID, userID string) (Blog, error) {
+	blog, err := s.repo.Get(blogID, userID)
+	if err != nil {
+		return Blog{}, err
+	}
+	fillLikeFields(&blog)
+	return blog, nil
+}
+
+// GetAll za svaki blog i komentar vraća myReaction korisnika userID;
+// prazan userID znači anonimnog korisnika. Tag iz filtera se normalizuje
+// isto kao pri kreiranju, pa ?tag=Kopaonik nalazi "kopaonik".
+func (s *Service) GetAll(userID string, filter BlogFilter) ([]Blog, error) {
+	if filter.Tag != "" {
+		tag, ok := normalizeTag(filter.Tag)
+		if !ok {
+			return nil, ErrInvalidTag
+		}
+		filter.Tag = tag
+	}
+
+	blogs, err := s.repo.GetAll(userID, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range blogs {
+		fillLikeFields(&blogs[i])
+	}
+	return blogs, nil
+}
+
+// AddLike i RemoveLike su stare rute; lajk je samo "like" reakcija.
 func (s *Service) AddLike(blogID, userID string) error {
-	return s.repo.AddLike(blogID, userID)
+	return s.React(Target{BlogID: blogID}, userID, ReactionLike)
 }
 
+// RemoveLike ne dira reakciju drugog tipa.
 func (s *Service) RemoveLike(blogID, userID string) error {
-	return s.repo.RemoveLike(blogID, userID)
+	target := Target{BlogID: blogID}
+	if err := s.repo.RemoveReaction(target, userID, ReactionLike); err != nil {
+		return err
+	}
+	s.broadcastLikeCount(target)
+	return nil
+}
+
+func (s *Service) GetLikes(blogID string, offset, limit int) (ReactionPage, error) {
+	return s.GetReactions(blogID, ReactionLike, offset, limit)
 }
 
-func (s *Service) AddComment(blogID string, comment Comment) error {
+// React postavlja reakciju korisnika; postojeća reakcija drugog tipa se menja.
+// Lajk bloga se objavljuje kao blog.liked, a novi brojevi kao like-count.
+func (s *Service) React(target Target, userID, reactionType string) error {
+	if !validReactionType(reactionType) {
+		return ErrUnknownReaction
+	}
+	if err := s.repo.SetReaction(target, userID, reactionType); err != nil {
+		return err
+	}
+	s.broadcastLikeCount(target)
+	if reactionType == ReactionLike && !target.isComment() {
+		s.notify(RoutingKeyBlogLiked, target.BlogID, userID, "")
+	}
+	return nil
+}
+
+func (s *Service) Unreact(target Target, userID string) error {
+	if err := s.repo.RemoveReaction(target, userID, ""); err != nil {
+		return err
+	}
+	s.broadcastLikeCount(target)
+	return nil
+}
+
+// GetReactions ograničava limit na [1, MaxReactionPageSize].
+func (s *Service) GetReactions(blogID, reactionType string, offset, limit int) (ReactionPage, error) {
+	if !validReactionType(reactionType) {
+		return ReactionPage{}, ErrUnknownReaction
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return s.repo.GetReactions(blogID, reactionType, offset, clampLimit(limit, DefaultReactionPageSize, MaxReactionPageSize))
+}
+
+// AddComment: komentar koji filter zadrži čuva se sakriven, kao u Create,
+// i ne objavljuje se ni kao blog.commented ni kao comment-added, a
+// pomenuti korisnici se ne obaveštavaju.
+func (s *Service) AddComment(blogID string, comment Comment) (Comment, error) {
+	decision, err := s.screen(contentfilter.KindComment, comment.UserID, "", comment.Text)
+	if err != nil {
+		return Comment{}, err
+	}
+	comment.Text = decision.Body
+	comment.Hidden = decision.Action == contentfilter.Hold
+	comment.Mentions = s.resolveMentions(comment.UserID, mentionNames(nil, decision.Body))
+
+	comment.ID = primitive.NewObjectID().Hex()
 	comment.CreatedAt = time.Now()
 	comment.ModifiedAt = time.Now()
-	return s.repo.AddComment(blogID, comment)
+	if err := s.repo.AddComment(blogID, comment); err != nil {
+		return Comment{}, err
+	}
+	s.settle(Target{BlogID: blogID, CommentID: comment.ID}, comment.UserID, decision)
+	if !comment.Hidden {
+		s.notify(RoutingKeyBlogCommented, blogID, comment.UserID, comment.ID)
+		s.notifyMentions(blogID, comment.UserID, comment.ID, comment.Mentions)
+		s.broadcast(blogID, LiveCommentAdded, comment)
+	}
+	return comment, nil
+}
+
+// EditComment menja tekst komentara; menja ga samo autor. Izmena prolazi
+// kroz filter kao nov komentar, a zadržana izmena sakriva komentar do
+// odluke moderatora.
+func (s *Service) EditComment(target Target, userID, text string) (Comment, error) {
+	blog, err := s.repo.Get(target.BlogID, userID)
+	if err != nil {
+		return Comment{}, err
+	}
+	i := commentIndex(blog.Comments, target.CommentID)
+	if i < 0 {
+		return Comment{}, ErrCommentNotFound
+	}
+	comment := blog.Comments[i]
+	if comment.UserID != userID {
+		return Comment{}, ErrNotCommentEditor
+	}
+
+	decision, err := s.screen(contentfilter.KindComment, userID, "", text)
+	if err != nil {
+		return Comment{}, err
+	}
+	mentions := s.resolveMentions(userID, mentionNames(nil, decision.Body))
+	added := mentionsAdded(mentions, comment.Mentions)
+	comment.Text = decision.Body
+	comment.Mentions = mentions
+	comment.ModifiedAt = time.Now()
+	if err := s.repo.EditComment(target, comment); err != nil {
+		return Comment{}, err
+	}
+
+	if decision.Action == contentfilter.Hold {
+		comment.Hidden = true
+		if err := s.repo.Hide(target); err != nil {
+			log.Printf("❌ Zadržana izmena komentara %+v nije sakrivena: %s", target, err)
+		}
+		if err := s.moderation.Hold(target, userID, decision); err != nil {
+			log.Printf("❌ Zadržana izmena komentara %+v nije dodata u red za moderaciju: %s", target, err)
+		}
+		return comment, nil
+	}
+	s.notifyMentions(target.BlogID, userID, target.CommentID, added)
+	s.broadcast(target.BlogID, LiveCommentEdited, comment)
+	return comment, nil
+}
+
+// UnlinkTour se poziva kada service-tours obriše turu.
+func (s *Service) UnlinkTour(tourID int64) (int64, error) {
+	return s.repo.UnlinkTour(tourID)
+}
+
+// Tags vraća najviše limit tagova sa brojem blogova, od najčešćeg.
+func (s *Service) Tags(limit int) ([]TagCount, error) {
+	return s.repo.TagCounts(clampLimit(limit, DefaultTagsLimit, MaxTagsLimit))
+}
+
+// TrendingTags broji tagove blogova objavljenih u poslednjih window;
+// prozor se ograničava na MaxTrendingWindow.
+func (s *Service) TrendingTags(window time.Duration, limit int) ([]TagCount, error) {
+	if window <= 0 {
+		window = DefaultTrendingWindow
+	}
+	if window > MaxTrendingWindow {
+		window = MaxTrendingWindow
+	}
+	return s.repo.TrendingTags(time.Now().Add(-window), clampLimit(limit, DefaultTrendingLimit, MaxTrendingLimit))
+}
+
+func clampLimit(limit, def, max int) int {
+	if limit <= 0 {
+		return def
+	}
+	if limit > max {
+		return max
+	}
+	return limit
+}
+
+// fillLikeFields popunjava polja koja stari klijenti čitaju umesto reakcija.
+func fillLikeFields(blog *Blog) {
+	if blog.ReactionCounts == nil {
+		blog.ReactionCounts = map[string]int{}
+	}
+	blog.LikeCount = blog.ReactionCounts[ReactionLike]
+	blog.LikedByMe = blog.MyReaction == ReactionLike
 }
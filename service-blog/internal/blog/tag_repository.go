@@ -0,0 +1,55 @@
+package blog
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func (r *Repository) TagCounts(limit int) ([]TagCount, error) {
+	return r.countTags(nil, limit)
+}
+
+// TrendingTags broji tagove blogova objavljenih u prozoru. Vreme objave
+// je publishAt ako postoji, inače createdAt (kao publishedAt); indeksi nad
+// oba polja sužavaju $match pre $unwind-a.
+func (r *Repository) TrendingTags(since time.Time, limit int) ([]TagCount, error) {
+	return r.countTags(bson.M{"$or": bson.A{
+		bson.M{"publishAt": bson.M{"$gte": since}},
+		bson.M{"publishAt": nil, "createdAt": bson.M{"$gte": since}},
+	}}, limit)
+}
+
+func (r *Repository) countTags(match bson.M, limit int) ([]TagCount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if match == nil {
+		match = bson.M{}
+	}
+	match["tags.0"] = bson.M{"$exists": true}
+	match["scheduled"] = bson.M{"$ne": true}
+	match["hidden"] = bson.M{"$ne": true}
+	live(match)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+		{{Key: "$limit", Value: limit}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tags := []TagCount{}
+	if err := cursor.All(ctx, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
@@ -0,0 +1,75 @@
+package blog
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	MaxTagsPerBlog = 10
+	maxTagLength   = 32
+
+	DefaultTagsLimit = 100
+	MaxTagsLimit     = 500
+
+	DefaultTrendingWindow = 7 * 24 * time.Hour
+	MaxTrendingWindow     = 90 * 24 * time.Hour
+	DefaultTrendingLimit  = 10
+	MaxTrendingLimit      = 50
+)
+
+// TagCount je broj blogova sa datim tagom.
+type TagCount struct {
+	Tag   string `json:"tag" bson:"_id"`
+	Count int    `json:"count" bson:"count"`
+}
+
+// BlogFilter sužava listu blogova; prazno polje ne filtrira.
+type BlogFilter struct {
+	Tag    string
+	TourID int64
+}
+
+// normalizeTag svodi tag na oblik u kome se čuva: mala slova, bez '#' na
+// početku, razmaci i '_' postaju '-'. "#Stara Planina" i "stara-planina"
+// su isti tag. Vraća false ako posle normalizacije ništa ne ostane ili ako
+// tag sadrži znakove koji nisu slova, cifre ili '-'.
+func normalizeTag(raw string) (string, bool) {
+	tag := strings.ToLower(strings.TrimSpace(raw))
+	tag = strings.TrimLeft(tag, "#")
+	tag = strings.Join(strings.FieldsFunc(tag, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '_' || r == '-'
+	}), "-")
+
+	if tag == "" || len([]rune(tag)) > maxTagLength {
+		return "", false
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' {
+			return "", false
+		}
+	}
+	return tag, true
+}
+
+// normalizeTags normalizuje i uklanja duplikate, čuvajući redosled autora.
+func normalizeTags(raw []string) ([]string, error) {
+	tags := make([]string, 0, len(raw))
+	seen := map[string]bool{}
+	for _, r := range raw {
+		tag, ok := normalizeTag(r)
+		if !ok {
+			return nil, ErrInvalidTag
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+	if len(tags) > MaxTagsPerBlog {
+		return nil, ErrTooManyTags
+	}
+	return tags, nil
+}
@@ -0,0 +1,80 @@
+package blog
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// TourCatalog proverava ture na koje blog upućuje; u produkciji je to
+// tours.Client nad service-tours.
+type TourCatalog interface {
+	// KeyPointIDs vraća ključne tačke ture; found je false ako tura ne postoji.
+	KeyPointIDs(ctx context.Context, tourID int64, username string) (ids []int64, found bool, err error)
+}
+
+// MemoryTourCatalog je TourCatalog za testove i lokalni rad bez service-tours.
+type MemoryTourCatalog struct {
+	mu    sync.RWMutex
+	tours map[int64][]int64
+}
+
+func NewMemoryTourCatalog() *MemoryTourCatalog {
+	return &MemoryTourCatalog{tours: map[int64][]int64{}}
+}
+
+func (c *MemoryTourCatalog) AddTour(tourID int64, keyPointIDs ...int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tours[tourID] = append([]int64(nil), keyPointIDs...)
+}
+
+func (c *MemoryTourCatalog) KeyPointIDs(ctx context.Context, tourID int64, username string) ([]int64, bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ids, ok := c.tours[tourID]
+	return append([]int64(nil), ids...), ok, nil
+}
+
+// errNoTourCatalog: servis je napravljen bez ServiceOptions.Tours.
+var errNoTourCatalog = errors.New("katalog tura nije podešen")
+
+// validateTourLink proverava da tura postoji i da su sve ključne tačke
+// sa nje; duplikati ključnih tačaka se uklanjaju.
+func (s *Service) validateTourLink(blog *Blog) error {
+	if blog.TourID == 0 {
+		if len(blog.KeyPointIDs) > 0 {
+			return ErrKeyPointsWithoutTour
+		}
+		return nil
+	}
+
+	if s.tours == nil {
+		return errToursUnavailable(errNoTourCatalog)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids, found, err := s.tours.KeyPointIDs(ctx, blog.TourID, blog.Author)
+	if err != nil {
+		return errToursUnavailable(err)
+	}
+	if !found {
+		return ErrTourNotFound
+	}
+
+	keyPoints := make([]int64, 0, len(blog.KeyPointIDs))
+	for _, id := range blog.KeyPointIDs {
+		if !slices.Contains(ids, id) {
+			return ErrKeyPointNotOnTour
+		}
+		if !slices.Contains(keyPoints, id) {
+			keyPoints = append(keyPoints, id)
+		}
+	}
+	blog.KeyPointIDs = keyPoints
+	return nil
+}
@@ -0,0 +1,110 @@
+package blog
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// DefaultTrashRetention je koliko obrisan sadržaj čeka u korpi pre
+// trajnog brisanja.
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// Trash je korpa korisnika: blogovi i komentari koje je on obrisao.
+type Trash struct {
+	Blogs    []Blog           `json:"blogs"`
+	Comments []TrashedComment `json:"comments"`
+}
+
+// TrashedComment je obrisan komentar sa blogom na kome je bio.
+type TrashedComment struct {
+	BlogID    string  `json:"blogId" bson:"blogId"`
+	BlogTitle string  `json:"blogTitle" bson:"blogTitle"`
+	Comment   Comment `json:"comment" bson:"comment"`
+}
+
+// DeleteComment premešta komentar u korpu korisnika userID. Komentar
+// briše njegov autor, autor bloga ili administrator.
+func (s *Service) DeleteComment(target Target, userID string, admin bool) error {
+	blog, err := s.repo.Get(target.BlogID, userID)
+	if err != nil {
+		return err
+	}
+	i := commentIndex(blog.Comments, target.CommentID)
+	if i < 0 {
+		return ErrCommentNotFound
+	}
+	if blog.Comments[i].UserID != userID && blog.Author != userID && !admin {
+		return ErrNotCommentAuthor
+	}
+	return s.repo.Delete(target, userID)
+}
+
+// Trash vraća korpu korisnika; lajkovi se računaju kao u GetAll.
+func (s *Service) Trash(userID string) (Trash, error) {
+	trash, err := s.repo.Trash(userID)
+	if err != nil {
+		return Trash{}, err
+	}
+	for i := range trash.Blogs {
+		fillLikeFields(&trash.Blogs[i])
+	}
+	return trash, nil
+}
+
+// RestoreDeleted vraća blog ili komentar iz korpe. Korisnik vraća samo ono
+// što je sam obrisao; administrator vraća i sadržaj koji su obrisali drugi,
+// npr. moderatori.
+func (s *Service) RestoreDeleted(target Target, userID string, admin bool) error {
+	deletedBy := userID
+	if admin {
+		deletedBy = ""
+	}
+	return s.repo.Restore(target, deletedBy)
+}
+
+// RetentionWorker trajno briše sadržaj koji je u korpi duže od retention,
+// zajedno sa istorijom izmena. Reakcije i slike su polja bloga, pa nestaju
+// sa njim. Brisanje je idempotentno, pa radnik može da radi na više replika.
+type RetentionWorker struct {
+	repo      BlogRepository
+	revisions RevisionRepository
+	retention time.Duration
+}
+
+func NewRetentionWorker(repo BlogRepository, revisions RevisionRepository, retention time.Duration) *RetentionWorker {
+	return &RetentionWorker{repo: repo, revisions: revisions, retention: retention}
+}
+
+// Run prazni korpe na svakih interval dok se ctx ne otkaže.
+func (w *RetentionWorker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := w.Purge(time.Now())
+		if purged > 0 {
+			log.Printf("🗑️ Trajno obrisano blogova iz korpe: %d", purged)
+		}
+		if err != nil {
+			log.Printf("⚠️ Pražnjenje korpe: %s", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge trajno briše sadržaj obrisan pre now-retention i vraća broj
+// trajno obrisanih blogova.
+func (w *RetentionWorker) Purge(now time.Time) (int, error) {
+	purged, err := w.repo.PurgeDeleted(now.Add(-w.retention))
+	for _, blogID := range purged {
+		if err := w.revisions.DeleteRevisions(blogID); err != nil {
+			log.Printf("❌ Istorija trajno obrisanog bloga %s nije obrisana: %s", blogID, err)
+		}
+	}
+	return len(purged), err
+}
@@ -0,0 +1,153 @@
+package blog
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// purgeBatchSize ograničava broj blogova koje jedan PurgeDeleted trajno briše.
+const purgeBatchSize = 500
+
+func (r *Repository) Restore(target Target, deletedBy string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objID, err := parseBlogID(target.BlogID)
+	if err != nil {
+		return err
+	}
+
+	deleted := bson.M{"deletedAt": bson.M{"$exists": true}}
+	if deletedBy != "" {
+		deleted["deletedBy"] = deletedBy
+	}
+
+	if !target.isComment() {
+		deleted["_id"] = objID
+		result, err := r.collection.UpdateOne(ctx, deleted, bump(bson.M{"$unset": bson.M{"deletedAt": "", "deletedBy": ""}}))
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return ErrBlogNotFound
+		}
+		return nil
+	}
+
+	// Komentar se vraća samo na blog koji nije u korpi; kao u Unhide, broji
+	// se samo ako nije sakriven.
+	deleted["id"] = target.CommentID
+	unset := bson.M{"comments.$[c].deletedAt": "", "comments.$[c].deletedBy": ""}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: commentArrayFilters(target)})
+	visible := maps.Clone(deleted)
+	visible["hidden"] = bson.M{"$ne": true}
+	result, err := r.collection.UpdateOne(ctx,
+		live(bson.M{"_id": objID, "comments": bson.M{"$elemMatch": visible}}),
+		bump(bson.M{"$unset": unset, "$inc": bson.M{"commentCount": 1}}),
+		opts,
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount > 0 {
+		return nil
+	}
+	result, err = r.collection.UpdateOne(ctx,
+		live(bson.M{"_id": objID, "comments": bson.M{"$elemMatch": deleted}}),
+		bump(bson.M{"$unset": unset}),
+		opts,
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrCommentNotFound
+	}
+	return nil
+}
+
+// Trash vraća blogove bez komentara i reakcija, a komentare sa naslovom
+// bloga; i jedne i druge od poslednjeg obrisanog.
+func (r *Repository) Trash(userID string) (Trash, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	trash := Trash{Blogs: []Blog{}, Comments: []TrashedComment{}}
+
+	opts := options.Find().
+		SetProjection(bson.M{"reactions": 0, "comments": 0}).
+		SetSort(bson.D{{Key: "deletedAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"deletedBy": userID}, opts)
+	if err != nil {
+		return Trash{}, err
+	}
+	if err := cursor.All(ctx, &trash.Blogs); err != nil {
+		return Trash{}, err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: live(bson.M{"comments.deletedBy": userID})}},
+		{{Key: "$unwind", Value: "$comments"}},
+		{{Key: "$match", Value: bson.M{"comments.deletedBy": userID}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":       0,
+			"blogId":    bson.M{"$toString": "$_id"},
+			"blogTitle": "$title",
+			"comment":   "$comments",
+		}}},
+		{{Key: "$project", Value: bson.M{"comment.reactions": 0}}},
+		{{Key: "$sort", Value: bson.M{"comment.deletedAt": -1}}},
+	}
+	cursor, err = r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return Trash{}, err
+	}
+	if err := cursor.All(ctx, &trash.Comments); err != nil {
+		return Trash{}, err
+	}
+	return trash, nil
+}
+
+// PurgeDeleted briše blogove jedan po jedan pod uslovom deletedAt <= before,
+// pa blog vraćen u međuvremenu ostaje i ne ulazi u rezultat. Komentari se
+// uklanjaju bez bump-a: već se ne vraćaju klijentu, pa ETag ostaje važeći.
+func (r *Repository) PurgeDeleted(before time.Time) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	expired := bson.M{"deletedAt": bson.M{"$lte": before}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1}).SetLimit(purgeBatchSize)
+	cursor, err := r.collection.Find(ctx, expired, opts)
+	if err != nil {
+		return nil, err
+	}
+	var rows []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, err
+	}
+
+	purged := []string{}
+	for _, row := range rows {
+		result, err := r.collection.DeleteOne(ctx, bson.M{"_id": row.ID, "deletedAt": bson.M{"$lte": before}})
+		if err != nil {
+			return purged, err
+		}
+		if result.DeletedCount > 0 {
+			purged = append(purged, row.ID.Hex())
+		}
+	}
+
+	_, err = r.collection.UpdateMany(ctx,
+		bson.M{"comments.deletedAt": bson.M{"$lte": before}},
+		bson.M{"$pull": bson.M{"comments": bson.M{"deletedAt": bson.M{"$lte": before}}}},
+	)
+	return purged, err
+}
@@ -0,0 +1,134 @@
+package contentfilter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// RuleConfig je jedno pravilo u JSON fajlu, npr.
+//
+//	{"name": "linkovi", "type": "links", "action": "hold", "max": 3}
+//
+// Type je words, links, repeated_chars ili new_account; polja posle
+// AppliesTo važe samo za odgovarajući tip.
+type RuleConfig struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Action    Action   `json:"action"`
+	AppliesTo []string `json:"appliesTo"`
+
+	Words    []string `json:"words"`
+	Max      int      `json:"max"`
+	MaxRun   int      `json:"maxRun"`
+	MinAge   string   `json:"minAge"`
+	MinPosts int      `json:"minPosts"`
+	MaxLinks *int     `json:"maxLinks"`
+}
+
+type Config struct {
+	Rules []RuleConfig `json:"rules"`
+}
+
+// ParseRules pravi pravila iz JSON-a; jedno neispravno pravilo odbacuje
+// ceo fajl, da se ne bi pustila polovina konfiguracije.
+func ParseRules(data []byte) ([]Rule, error) {
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+
+	rules := make([]Rule, 0, len(cfg.Rules))
+	for i, rc := range cfg.Rules {
+		rule, err := rc.build()
+		if err != nil {
+			return nil, fmt.Errorf("pravilo %d (%s): %w", i, rc.Name, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (rc RuleConfig) build() (Rule, error) {
+	if rc.Name == "" {
+		return nil, errors.New("nedostaje name")
+	}
+	if rc.Action == Allow {
+		return nil, errors.New("nedostaje action")
+	}
+	for _, kind := range rc.AppliesTo {
+		if kind != KindBlog && kind != KindComment {
+			return nil, fmt.Errorf("appliesTo: nepoznata vrsta %q", kind)
+		}
+	}
+	if rc.Action == Mask && rc.Type != "words" {
+		return nil, errors.New("mask je dozvoljen samo za words")
+	}
+
+	switch rc.Type {
+	case "words":
+		if len(rc.Words) == 0 {
+			return nil, errors.New("words je prazan")
+		}
+		return NewWordListRule(rc.Name, rc.Action, rc.AppliesTo, rc.Words), nil
+	case "links":
+		if rc.Max < 0 {
+			return nil, errors.New("max ne sme biti negativan")
+		}
+		return NewLinkLimitRule(rc.Name, rc.Action, rc.AppliesTo, rc.Max), nil
+	case "repeated_chars":
+		if rc.MaxRun < 2 {
+			return nil, errors.New("maxRun mora biti najmanje 2")
+		}
+		return NewRepeatedCharsRule(rc.Name, rc.Action, rc.AppliesTo, rc.MaxRun), nil
+	case "new_account":
+		minAge, err := time.ParseDuration(rc.MinAge)
+		if rc.MinAge != "" && err != nil {
+			return nil, fmt.Errorf("minAge: %w", err)
+		}
+		return NewNewAccountRule(rc.Name, rc.Action, rc.AppliesTo, minAge, rc.MinPosts, rc.MaxLinks), nil
+	default:
+		return nil, fmt.Errorf("nepoznat tip %q", rc.Type)
+	}
+}
+
+func LoadFile(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseRules(data)
+}
+
+// WatchFile učitava pravila iz path i ponovo ih učitava kad god se fajlu
+// promeni vreme izmene. Neispravan fajl se loguje, a važeća pravila ostaju.
+// Vraća se kada se ctx otkaže.
+func (p *Pipeline) WatchFile(ctx context.Context, path string, interval time.Duration) {
+	var loaded time.Time
+	for {
+		if info, err := os.Stat(path); err != nil {
+			if loaded.IsZero() {
+				log.Printf("⚠️ Pravila filtera %s nisu dostupna: %s", path, err)
+				loaded = time.Unix(0, 0)
+			}
+		} else if !info.ModTime().Equal(loaded) {
+			loaded = info.ModTime()
+			if rules, err := LoadFile(path); err != nil {
+				log.Printf("❌ Pravila filtera %s nisu učitana, ostaju prethodna: %s", path, err)
+			} else {
+				p.SetRules(rules)
+				log.Printf("🛡️ Učitano %d pravila filtera iz %s", len(rules), path)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Package contentfilter proverava tekst novih blogova i komentara nizom
+// pravila pre nego što se sačuva. Pravilo može da odbije sadržaj, zadrži
+// ga za moderaciju ili da zameni zabranjene reči zvezdicama.
+package contentfilter
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// Action je ishod pravila; veća vrednost je stroža i pobeđuje.
+type Action int
+
+const (
+	Allow Action = iota
+	Mask
+	Hold
+	Reject
+)
+
+var actionNames = map[Action]string{Allow: "allow", Mask: "mask", Hold: "hold", Reject: "reject"}
+
+func (a Action) String() string {
+	if name, ok := actionNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("Action(%d)", int(a))
+}
+
+func (a *Action) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+	for action, n := range actionNames {
+		if n == name && action != Allow {
+			*a = action
+			return nil
+		}
+	}
+	return fmt.Errorf("nepoznata akcija %q", name)
+}
+
+const (
+	KindBlog    = "blog"
+	KindComment = "comment"
+)
+
+// Content je tekst koji se proverava. AccountAge i Posts opisuju autora u
+// blog servisu: koliko je prošlo od njegovog prvog objavljenog sadržaja i
+// koliko ga je objavio.
+type Content struct {
+	Kind       string
+	Author     string
+	Title      string
+	Body       string
+	AccountAge time.Duration
+	Posts      int
+}
+
+// Rule je jedno pravilo filtera. Apply vraća razlog ako je pravilo
+// okinuto; pravila sa akcijom Mask menjaju Title i Body u c.
+type Rule interface {
+	Name() string
+	Action() Action
+	Applies(kind string) bool
+	Apply(c *Content) (reason string, hit bool)
+}
+
+// Hit je jedno okinuto pravilo.
+type Hit struct {
+	Rule   string
+	Action Action
+	Reason string
+}
+
+// Decision je ishod celog filtera. Rule i Reason potiču od pravila koje je
+// odredilo Action; Title i Body su tekst posle maskiranja.
+type Decision struct {
+	Action Action
+	Rule   string
+	Reason string
+	Title  string
+	Body   string
+	Hits   []Hit
+}
+
+// Pipeline izvršava pravila redom kojim su zadata. Reject prekida proveru,
+// Hold se pamti i provera se nastavlja da bi kasniji Reject mogao da ga
+// nadjača, a Mask menja tekst koji vide sledeća pravila. Pravila se mogu
+// zameniti u letu (SetRules, WatchFile) bez zaključavanja provera u toku.
+type Pipeline struct {
+	rules atomic.Pointer[[]Rule]
+}
+
+func NewPipeline(rules ...Rule) *Pipeline {
+	p := &Pipeline{}
+	p.SetRules(rules)
+	return p
+}
+
+func (p *Pipeline) SetRules(rules []Rule) {
+	rules = slices.Clone(rules)
+	p.rules.Store(&rules)
+}
+
+func (p *Pipeline) Rules() []Rule {
+	return *p.rules.Load()
+}
+
+func (p *Pipeline) Check(c Content) Decision {
+	decision := Decision{Action: Allow}
+	for _, rule := range p.Rules() {
+		if !rule.Applies(c.Kind) {
+			continue
+		}
+		reason, hit := rule.Apply(&c)
+		if !hit {
+			continue
+		}
+
+		action := rule.Action()
+		log.Printf("🛡️ Filter: %s autora %s, pravilo %q → %s (%s)", c.Kind, c.Author, rule.Name(), action, reason)
+		decision.Hits = append(decision.Hits, Hit{Rule: rule.Name(), Action: action, Reason: reason})
+		if action > decision.Action {
+			decision.Action = action
+			decision.Rule = rule.Name()
+			decision.Reason = reason
+		}
+		if action == Reject {
+			break
+		}
+	}
+	decision.Title = c.Title
+	decision.Body = c.Body
+	return decision
+}
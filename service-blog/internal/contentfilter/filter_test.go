@@ -0,0 +1,112 @@
+package contentfilter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPipelineCheck(t *testing.T) {
+	zero := 0
+	p := NewPipeline(
+		NewWordListRule("psovke", Mask, nil, []string{"budala"}),
+		NewLinkLimitRule("linkovi", Hold, []string{KindComment}, 1),
+		NewRepeatedCharsRule("ponavljanje", Hold, nil, 5),
+		NewNewAccountRule("novi", Hold, nil, 24*time.Hour, 3, &zero),
+		NewWordListRule("kocka", Reject, nil, []string{"kazino"}),
+	)
+	veteran := Content{AccountAge: 30 * 24 * time.Hour, Posts: 10}
+
+	tests := []struct {
+		name       string
+		content    Content
+		wantAction Action
+		wantRule   string
+		wantBody   string
+	}{
+		{name: "čist tekst", content: Content{Kind: KindBlog, Body: "Lep dan na Tari."}, wantAction: Allow, wantBody: "Lep dan na Tari."},
+		{name: "maskiranje cele reči", content: Content{Kind: KindBlog, Body: "Budala! budalaština"}, wantAction: Mask, wantRule: "psovke", wantBody: "******! budalaština"},
+		{name: "linkovi u komentaru", content: Content{Kind: KindComment, Body: "https://a.rs www.b.rs"}, wantAction: Hold, wantRule: "linkovi", wantBody: "https://a.rs www.b.rs"},
+		{name: "linkovi u blogu", content: Content{Kind: KindBlog, Body: "https://a.rs www.b.rs"}, wantAction: Allow, wantBody: "https://a.rs www.b.rs"},
+		{name: "ponovljeni znakovi", content: Content{Kind: KindBlog, Body: "super!!!!!!"}, wantAction: Hold, wantRule: "ponavljanje", wantBody: "super!!!!!!"},
+		{name: "reject nadjačava hold", content: Content{Kind: KindComment, Body: "kazino https://a.rs https://b.rs budala"}, wantAction: Reject, wantRule: "kocka", wantBody: "kazino https://a.rs https://b.rs ******"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.content
+			c.AccountAge, c.Posts = veteran.AccountAge, veteran.Posts
+			d := p.Check(c)
+			if d.Action != tt.wantAction || d.Rule != tt.wantRule || d.Body != tt.wantBody {
+				t.Errorf("Check = %s %q %q, očekivano %s %q %q", d.Action, d.Rule, d.Body, tt.wantAction, tt.wantRule, tt.wantBody)
+			}
+		})
+	}
+
+	if d := p.Check(Content{Kind: KindBlog, Body: "bez linkova"}); d.Action != Allow {
+		t.Errorf("novi autor bez linkova = %s", d.Action)
+	}
+	if d := p.Check(Content{Kind: KindBlog, Body: "www.a.rs"}); d.Action != Hold || d.Rule != "novi" {
+		t.Errorf("novi autor sa linkom = %s %q", d.Action, d.Rule)
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules([]byte(`{"rules": [
+		{"name": "kocka", "type": "words", "action": "reject", "words": ["kazino"]},
+		{"name": "novi", "type": "new_account", "action": "hold", "minAge": "24h", "appliesTo": ["comment"]}
+	]}`))
+	if err != nil || len(rules) != 2 || rules[1].Applies(KindBlog) {
+		t.Fatalf("ParseRules = %v, %v", rules, err)
+	}
+
+	for _, bad := range []string{
+		`{"rules": [{"name": "x", "type": "links", "action": "mask"}]}`,
+		`{"rules": [{"name": "x", "type": "words", "action": "block", "words": ["a"]}]}`,
+		`{"rules": [{"name": "x", "type": "smeh", "action": "hold"}]}`,
+		`{"rules": [{"type": "links", "action": "hold"}]}`,
+	} {
+		if _, err := ParseRules([]byte(bad)); err == nil {
+			t.Errorf("ParseRules(%s) bez greške", bad)
+		}
+	}
+}
+
+func TestWatchFileReloads(t *testing.T) {
+	p := NewPipeline()
+	path := filepath.Join(t.TempDir(), "rules.json")
+	write := func(data string, mtime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		os.Chtimes(path, mtime, mtime)
+	}
+	waitFor := func(n int) {
+		t.Helper()
+		for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			if len(p.Rules()) == n {
+				return
+			}
+		}
+		t.Fatalf("broj pravila = %d, očekivano %d", len(p.Rules()), n)
+	}
+
+	now := time.Now()
+	write(`{"rules": [{"name": "a", "type": "links", "action": "hold", "max": 1}]}`, now)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.WatchFile(ctx, path, 10*time.Millisecond)
+	waitFor(1)
+
+	write(`{"rules": [`, now.Add(time.Second))
+	time.Sleep(50 * time.Millisecond)
+	waitFor(1)
+
+	write(`{"rules": [
+		{"name": "a", "type": "links", "action": "hold", "max": 1},
+		{"name": "b", "type": "repeated_chars", "action": "hold", "maxRun": 4}
+	]}`, now.Add(2*time.Second))
+	waitFor(2)
+}
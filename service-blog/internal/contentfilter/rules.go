@@ -0,0 +1,169 @@
+package contentfilter
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// base sadrži polja zajednička svim pravilima.
+type base struct {
+	name      string
+	action    Action
+	appliesTo []string
+}
+
+func (b base) Name() string   { return b.name }
+func (b base) Action() Action { return b.action }
+
+// Applies: prazan appliesTo znači i blogove i komentare.
+func (b base) Applies(kind string) bool {
+	return len(b.appliesTo) == 0 || slices.Contains(b.appliesTo, kind)
+}
+
+// WordListRule traži cele reči bez obzira na velika i mala slova. Sa
+// akcijom Mask svaka pronađena reč se zamenjuje zvezdicama iste dužine.
+type WordListRule struct {
+	base
+	words map[string]bool
+}
+
+func NewWordListRule(name string, action Action, appliesTo []string, words []string) *WordListRule {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		if w = strings.ToLower(strings.TrimSpace(w)); w != "" {
+			set[w] = true
+		}
+	}
+	return &WordListRule{base: base{name, action, appliesTo}, words: set}
+}
+
+func (r *WordListRule) Apply(c *Content) (string, bool) {
+	var found []string
+	c.Title, found = r.scan(c.Title, found)
+	c.Body, found = r.scan(c.Body, found)
+	if len(found) == 0 {
+		return "", false
+	}
+	return "zabranjene reči: " + strings.Join(found, ", "), true
+}
+
+// scan prolazi kroz nizove slova i cifara; tekst se menja samo za Mask.
+func (r *WordListRule) scan(text string, found []string) (string, []string) {
+	runes := []rune(text)
+	for start := 0; start < len(runes); {
+		if !isWordRune(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && isWordRune(runes[end]) {
+			end++
+		}
+		word := strings.ToLower(string(runes[start:end]))
+		if r.words[word] {
+			if !slices.Contains(found, word) {
+				found = append(found, word)
+			}
+			if r.action == Mask {
+				for i := start; i < end; i++ {
+					runes[i] = maskRune
+				}
+			}
+		}
+		start = end
+	}
+	return string(runes), found
+}
+
+// maskRune zamenjuje slova maskirane reči.
+const maskRune = '*'
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s)\]>"']+`)
+
+// LinkLimitRule se okida kada naslov i tekst zajedno imaju više od max linkova.
+type LinkLimitRule struct {
+	base
+	max int
+}
+
+func NewLinkLimitRule(name string, action Action, appliesTo []string, max int) *LinkLimitRule {
+	return &LinkLimitRule{base: base{name, action, appliesTo}, max: max}
+}
+
+func (r *LinkLimitRule) Apply(c *Content) (string, bool) {
+	n := countLinks(c)
+	if n <= r.max {
+		return "", false
+	}
+	return fmt.Sprintf("%d linkova, dozvoljeno %d", n, r.max), true
+}
+
+func countLinks(c *Content) int {
+	return len(linkPattern.FindAllStringIndex(c.Title, -1)) + len(linkPattern.FindAllStringIndex(c.Body, -1))
+}
+
+// RepeatedCharsRule hvata "spaaaaaam" i "!!!!!!!!": isti znak koji nije
+// razmak ponovljen više od maxRun puta zaredom. Zvezdice iz maskiranja
+// (maskRune) se ne broje.
+type RepeatedCharsRule struct {
+	base
+	maxRun int
+}
+
+func NewRepeatedCharsRule(name string, action Action, appliesTo []string, maxRun int) *RepeatedCharsRule {
+	return &RepeatedCharsRule{base: base{name, action, appliesTo}, maxRun: maxRun}
+}
+
+func (r *RepeatedCharsRule) Apply(c *Content) (string, bool) {
+	for _, text := range []string{c.Title, c.Body} {
+		var prev rune
+		run := 0
+		for _, ch := range text {
+			if ch == prev && !unicode.IsSpace(ch) && ch != maskRune {
+				run++
+			} else {
+				prev, run = ch, 1
+			}
+			if run > r.maxRun {
+				return fmt.Sprintf("znak %q ponovljen više od %d puta", ch, r.maxRun), true
+			}
+		}
+	}
+	return "", false
+}
+
+// NewAccountRule ograničava autore koji su tek počeli da objavljuju: mlađe
+// od minAge ili sa manje od minPosts objava. Ako je maxLinks zadat, pravilo
+// se okida samo kada sadržaj ima više linkova od toga; inače se okida za
+// svaki sadržaj novog autora.
+type NewAccountRule struct {
+	base
+	minAge   time.Duration
+	minPosts int
+	maxLinks *int
+}
+
+func NewNewAccountRule(name string, action Action, appliesTo []string, minAge time.Duration, minPosts int, maxLinks *int) *NewAccountRule {
+	return &NewAccountRule{base: base{name, action, appliesTo}, minAge: minAge, minPosts: minPosts, maxLinks: maxLinks}
+}
+
+func (r *NewAccountRule) Apply(c *Content) (string, bool) {
+	if c.AccountAge >= r.minAge && c.Posts >= r.minPosts {
+		return "", false
+	}
+	if r.maxLinks == nil {
+		return fmt.Sprintf("novi autor (%d objava)", c.Posts), true
+	}
+	if n := countLinks(c); n > *r.maxLinks {
+		return fmt.Sprintf("novi autor sa %d linkova, dozvoljeno %d", n, *r.maxLinks), true
+	}
+	return "", false
+}
@@ -0,0 +1,74 @@
+// Package followers čita blokade između korisnika iz service-follower, da
+// blog ne bi povezao pomen korisnika koji je blokirao autora ili koga je
+// autor blokirao.
+package followers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client čita blokade preko REST API-ja service-follower.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 3 * time.Second},
+	}
+}
+
+// Blocks javlja da li userID blokira otherID ili otherID blokira userID.
+func (c *Client) Blocks(ctx context.Context, userID, otherID int64) (bool, error) {
+	blocked, err := c.blocked(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if slices.Contains(blocked, otherID) {
+		return true, nil
+	}
+	blocked, err = c.blocked(ctx, otherID)
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(blocked, userID), nil
+}
+
+// blocked vraća ID-jeve korisnika koje userID blokira. Korisnik koga nema
+// u grafu nikoga ne blokira.
+func (c *Client) blocked(ctx context.Context, userID int64) ([]int64, error) {
+	url := c.baseURL + "/users/" + strconv.FormatInt(userID, 10) + "/blocks"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("service-follower je vratio %d za blokade korisnika %d", resp.StatusCode, userID)
+	}
+
+	var ids []int64
+	if err := json.NewDecoder(resp.Body).Decode(&ids); err != nil {
+		return nil, fmt.Errorf("neispravan odgovor service-follower: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,465 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// all je lista svih migracija blog baze. Nova migracija dobija sledeći broj;
+// postojeće se nikad ne menjaju kada su jednom puštene.
+var all = []Migration{
+	{
+		Version:     1,
+		Description: "indeksi nad blogs kolekcijom",
+		Up:          createBlogIndexes,
+	},
+	{
+		Version:     2,
+		Description: "JSON schema validator za blogs",
+		Up:          installBlogValidator,
+	},
+	{
+		Version:     3,
+		Description: "createdAt za stare blogove iz vremena u ObjectID-ju",
+		Up:          backfillBlogCreatedAt,
+	},
+	{
+		Version:     4,
+		Description: "likeCount i commentCount iz postojećih nizova",
+		Up:          backfillBlogCounters,
+	},
+	{
+		Version:     5,
+		Description: "lajkovi prelaze u reakcije tipa like",
+		Up:          migrateLikesToReactions,
+	},
+	{
+		Version:     6,
+		Description: "ID za postojeće komentare",
+		Up:          backfillCommentIDs,
+	},
+	{
+		Version:     7,
+		Description: "indeks nad tagovima",
+		Up:          createTagIndex,
+	},
+	{
+		Version:     8,
+		Description: "indeks nad tourId",
+		Up:          createTourIndex,
+	},
+	{
+		Version:     9,
+		Description: "indeksi nad reports kolekcijom",
+		Up:          createReportIndexes,
+	},
+	{
+		Version:     10,
+		Description: "aktivnost autora iz postojećih blogova i komentara",
+		Up:          backfillUserActivity,
+	},
+	{
+		Version:     11,
+		Description: "version i updatedAt za ETag",
+		Up:          backfillVersions,
+	},
+	{
+		Version:     12,
+		Description: "blog_revisions: indeks i prva revizija postojećih blogova",
+		Up:          createRevisions,
+	},
+	{
+		Version:     13,
+		Description: "indeksi za zakazanu objavu",
+		Up:          createPublishIndexes,
+	},
+	{
+		Version:     14,
+		Description: "indeksi za korpu i trajno brisanje",
+		Up:          createTrashIndexes,
+	},
+	{
+		Version:     15,
+		Description: "indeks nad updatedAt za Last-Modified liste",
+		Up:          createUpdatedAtIndex,
+	},
+	{
+		Version:     16,
+		Description: "indeks nad publishAt za trending tagove",
+		Up:          createPublishAtIndex,
+	},
+}
+
+func createBlogIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("blogs").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "author", Value: 1}, {Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("author_createdAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "createdAt", Value: -1}},
+			Options: options.Index().SetName("createdAt"),
+		},
+		{
+			Keys:    bson.D{{Key: "likes", Value: 1}},
+			Options: options.Index().SetName("likes"),
+		},
+	})
+	return err
+}
+
+// installBlogValidator proverava samo polja koja servis uvek upisuje;
+// nova polja su dozvoljena da bi kasnije migracije mogle da ih dodaju.
+func installBlogValidator(ctx context.Context, db *mongo.Database) error {
+	comment := bson.M{
+		"bsonType": "object",
+		"required": bson.A{"userId", "text"},
+		"properties": bson.M{
+			"userId":     bson.M{"bsonType": "string"},
+			"text":       bson.M{"bsonType": "string", "maxLength": 2000},
+			"createdAt":  bson.M{"bsonType": "date"},
+			"modifiedAt": bson.M{"bsonType": "date"},
+		},
+	}
+
+	schema := bson.M{
+		"bsonType": "object",
+		"required": bson.A{"author", "title", "description", "createdAt"},
+		"properties": bson.M{
+			"author":      bson.M{"bsonType": "string", "maxLength": 64},
+			"title":       bson.M{"bsonType": "string", "maxLength": 200},
+			"description": bson.M{"bsonType": "string"},
+			"createdAt":   bson.M{"bsonType": "date"},
+			"likes":       bson.M{"bsonType": "array", "items": bson.M{"bsonType": "string"}},
+			"comments":    bson.M{"bsonType": "array", "items": comment},
+			"images":      bson.M{"bsonType": "array", "items": bson.M{"bsonType": "string"}},
+		},
+	}
+	return setValidator(ctx, db, "blogs", schema)
+}
+
+func backfillBlogCreatedAt(ctx context.Context, db *mongo.Database) error {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"createdAt": bson.M{"$exists": false}},
+		bson.M{"createdAt": nil},
+	}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{"createdAt": bson.M{"$toDate": "$_id"}}}},
+	}
+	_, err := db.Collection("blogs").UpdateMany(ctx, filter, update)
+	return err
+}
+
+// backfillBlogCounters računa brojače samo za blogove koji ih još nemaju;
+// commentCount od tada održava AddComment, a likeCount zamenjuje migracija 5.
+func backfillBlogCounters(ctx context.Context, db *mongo.Database) error {
+	filter := bson.M{"$or": bson.A{
+		bson.M{"likeCount": bson.M{"$exists": false}},
+		bson.M{"commentCount": bson.M{"$exists": false}},
+	}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"likeCount":    bson.M{"$size": bson.M{"$ifNull": bson.A{"$likes", bson.A{}}}},
+			"commentCount": bson.M{"$size": bson.M{"$ifNull": bson.A{"$comments", bson.A{}}}},
+		}}},
+	}
+	_, err := db.Collection("blogs").UpdateMany(ctx, filter, update)
+	return err
+}
+
+// migrateLikesToReactions prepisuje niz likes u reactions; reactionCounts.like
+// zamenjuje likeCount. Indeks nad likes više ne služi ničemu.
+func migrateLikesToReactions(ctx context.Context, db *mongo.Database) error {
+	blogs := db.Collection("blogs")
+
+	likes := bson.M{"$ifNull": bson.A{"$likes", bson.A{}}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"reactions": bson.M{"$map": bson.M{
+				"input": likes,
+				"in":    bson.M{"userId": "$$this", "type": "like"},
+			}},
+			"reactionCounts": bson.M{"like": bson.M{"$size": likes}},
+		}}},
+		{{Key: "$unset", Value: bson.A{"likes", "likeCount"}}},
+	}
+	filter := bson.M{"reactions": bson.M{"$exists": false}}
+	if _, err := blogs.UpdateMany(ctx, filter, update); err != nil {
+		return err
+	}
+
+	_, err := blogs.Indexes().DropOne(ctx, "likes")
+	var cmdErr mongo.CommandError
+	if err != nil && !(errors.As(err, &cmdErr) && cmdErr.Name == "IndexNotFound") {
+		return err
+	}
+	_, err = blogs.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "reactions.userId", Value: 1}},
+		Options: options.Index().SetName("reactions_userId"),
+	})
+	if err != nil {
+		return err
+	}
+
+	return installReactionValidator(ctx, db)
+}
+
+// installReactionValidator zamenjuje šemu iz migracije 2: umesto likes
+// dozvoljava reactions na blogu i na komentarima.
+func installReactionValidator(ctx context.Context, db *mongo.Database) error {
+	reaction := bson.M{
+		"bsonType": "object",
+		"required": bson.A{"userId", "type"},
+		"properties": bson.M{
+			"userId": bson.M{"bsonType": "string"},
+			"type":   bson.M{"bsonType": "string", "maxLength": 32},
+		},
+	}
+	reactions := bson.M{"bsonType": "array", "items": reaction}
+
+	comment := bson.M{
+		"bsonType": "object",
+		"required": bson.A{"userId", "text"},
+		"properties": bson.M{
+			"id":         bson.M{"bsonType": "string"},
+			"userId":     bson.M{"bsonType": "string"},
+			"text":       bson.M{"bsonType": "string", "maxLength": 2000},
+			"createdAt":  bson.M{"bsonType": "date"},
+			"modifiedAt": bson.M{"bsonType": "date"},
+			"reactions":  reactions,
+		},
+	}
+
+	schema := bson.M{
+		"bsonType": "object",
+		"required": bson.A{"author", "title", "description", "createdAt"},
+		"properties": bson.M{
+			"author":      bson.M{"bsonType": "string", "maxLength": 64},
+			"title":       bson.M{"bsonType": "string", "maxLength": 200},
+			"description": bson.M{"bsonType": "string"},
+			"createdAt":   bson.M{"bsonType": "date"},
+			"reactions":   reactions,
+			"comments":    bson.M{"bsonType": "array", "items": comment},
+			"images":      bson.M{"bsonType": "array", "items": bson.M{"bsonType": "string"}},
+		},
+	}
+	return setValidator(ctx, db, "blogs", schema)
+}
+
+// backfillCommentIDs dodeljuje ID komentarima koji ga nemaju, da bi na njih
+// moglo da se reaguje. ObjectID se ne može napraviti u pipeline-u, pa se
+// blogovi prepisuju jedan po jedan.
+func backfillCommentIDs(ctx context.Context, db *mongo.Database) error {
+	blogs := db.Collection("blogs")
+
+	filter := bson.M{"comments": bson.M{"$elemMatch": bson.M{"id": bson.M{"$exists": false}}}}
+	cursor, err := blogs.Find(ctx, filter, options.Find().SetProjection(bson.M{"comments": 1}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID       primitive.ObjectID `bson:"_id"`
+			Comments []bson.M           `bson:"comments"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		for _, comment := range doc.Comments {
+			if _, ok := comment["id"]; !ok {
+				comment["id"] = primitive.NewObjectID().Hex()
+			}
+		}
+		if _, err := blogs.UpdateByID(ctx, doc.ID, bson.M{"$set": bson.M{"comments": doc.Comments}}); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// createTagIndex služi filteru ?tag= na listi blogova.
+func createTagIndex(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("blogs").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "tags", Value: 1}, {Key: "createdAt", Value: -1}},
+		Options: options.Index().SetName("tags_createdAt"),
+	})
+	return err
+}
+
+// createTourIndex služi ruti /tours/{id}/blogs i brisanju veze sa turom;
+// blogovi bez ture ne ulaze u indeks.
+func createTourIndex(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("blogs").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "tourId", Value: 1}, {Key: "createdAt", Value: -1}},
+		Options: options.Index().SetName("tourId_createdAt").
+			SetPartialFilterExpression(bson.M{"tourId": bson.M{"$exists": true}}),
+	})
+	return err
+}
+
+// createReportIndexes: jedna otvorena prijava po korisniku i sadržaju, i
+// red za moderaciju po statusu od najstarije prijave.
+func createReportIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("reports").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "blogId", Value: 1}, {Key: "commentId", Value: 1}, {Key: "reporterId", Value: 1}},
+			Options: options.Index().SetName("open_report_per_reporter").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"status": "open"}),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "createdAt", Value: 1}},
+			Options: options.Index().SetName("status_createdAt"),
+		},
+	})
+	return err
+}
+
+// backfillUserActivity popunjava user_activity iz kojeg filter sadržaja
+// čita starost naloga, da postojeći autori ne bi bili tretirani kao novi.
+func backfillUserActivity(ctx context.Context, db *mongo.Database) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$project", Value: bson.M{
+			"posts": bson.M{"$concatArrays": bson.A{
+				bson.A{bson.M{"user": "$author", "at": "$createdAt"}},
+				bson.M{"$map": bson.M{
+					"input": bson.M{"$ifNull": bson.A{"$comments", bson.A{}}},
+					"in":    bson.M{"user": "$$this.userId", "at": "$$this.createdAt"},
+				}},
+			}},
+		}}},
+		{{Key: "$unwind", Value: "$posts"}},
+		{{Key: "$match", Value: bson.M{"posts.user": bson.M{"$nin": bson.A{nil, ""}}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":         "$posts.user",
+			"firstSeenAt": bson.M{"$min": "$posts.at"},
+			"posts":       bson.M{"$sum": 1},
+		}}},
+		{{Key: "$merge", Value: bson.M{"into": "user_activity", "whenMatched": "keepExisting"}}},
+	}
+	cursor, err := db.Collection("blogs").Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	return cursor.Close(ctx)
+}
+
+// backfillVersions daje postojećim blogovima verziju 1; updatedAt je
+// vreme kreiranja, jer ranije izmene nisu beležene.
+func backfillVersions(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("blogs").UpdateMany(ctx,
+		bson.M{"version": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{
+			"version":   1,
+			"updatedAt": bson.M{"$ifNull": bson.A{"$updatedAt", "$createdAt"}},
+		}}}},
+	)
+	return err
+}
+
+// createRevisions pravi jedinstven indeks (blogId, rev) i prvu reviziju za
+// blogove kreirane pre istorije. Njihov markdown nije sačuvan, pa revizija
+// dobija renderovan HTML, koji renderer pri vraćanju propušta nepromenjen.
+func createRevisions(ctx context.Context, db *mongo.Database) error {
+	revisions := db.Collection("blog_revisions")
+	_, err := revisions.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "blogId", Value: 1}, {Key: "rev", Value: 1}},
+		Options: options.Index().SetName("blogId_rev").SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$project", Value: bson.M{
+			"_id":         0,
+			"blogId":      bson.M{"$toString": "$_id"},
+			"rev":         bson.M{"$literal": 1},
+			"editor":      "$author",
+			"createdAt":   bson.M{"$ifNull": bson.A{"$updatedAt", "$createdAt"}},
+			"title":       "$title",
+			"markdown":    "$description",
+			"images":      "$images",
+			"tags":        "$tags",
+			"tourId":      "$tourId",
+			"keyPointIds": "$keyPointIds",
+		}}},
+		{{Key: "$merge", Value: bson.M{
+			"into":           "blog_revisions",
+			"on":             bson.A{"blogId", "rev"},
+			"whenMatched":    "keepExisting",
+			"whenNotMatched": "insert",
+		}}},
+	}
+	cursor, err := db.Collection("blogs").Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	return cursor.Close(ctx)
+}
+
+// createPublishIndexes služi PublishScheduler-u; u indekse ulaze samo
+// zakazani blogovi i blogovi čiji događaj objave još nije poslat.
+func createPublishIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("blogs").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "publishAt", Value: 1}},
+			Options: options.Index().SetName("scheduled_publishAt").
+				SetPartialFilterExpression(bson.M{"scheduled": true}),
+		},
+		{
+			Keys: bson.D{{Key: "publishLease", Value: 1}},
+			Options: options.Index().SetName("publishPending_publishLease").
+				SetPartialFilterExpression(bson.M{"publishPending": true}),
+		},
+	})
+	return err
+}
+
+// createTrashIndexes: korpa korisnika i radnik koji je prazni čitaju samo
+// obrisan sadržaj, pa indeksi sadrže samo njega.
+func createTrashIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("blogs").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "deletedBy", Value: 1}, {Key: "deletedAt", Value: -1}},
+			Options: options.Index().SetName("deletedBy_deletedAt").
+				SetPartialFilterExpression(bson.M{"deletedAt": bson.M{"$exists": true}}),
+		},
+		{
+			Keys:    bson.D{{Key: "comments.deletedBy", Value: 1}},
+			Options: options.Index().SetName("comments_deletedBy").SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "comments.deletedAt", Value: 1}},
+			Options: options.Index().SetName("comments_deletedAt").SetSparse(true),
+		},
+	})
+	return err
+}
+
+// createUpdatedAtIndex služi Last-Modified listi blogova, koja čita
+// najskoriju izmenu u celoj kolekciji.
+func createUpdatedAtIndex(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("blogs").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "updatedAt", Value: -1}},
+		Options: options.Index().SetName("updatedAt"),
+	})
+	return err
+}
+
+// createPublishAtIndex: trending tagovi blogova zakazanih za objavu
+// gledaju publishAt, pa postojeći parcijalni indeks nad zakazanim
+// blogovima tu ne pomaže.
+func createPublishAtIndex(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("blogs").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "publishAt", Value: -1}},
+		Options: options.Index().SetName("publishAt").SetSparse(true),
+	})
+	return err
+}
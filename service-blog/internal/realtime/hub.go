@@ -0,0 +1,169 @@
+// Package realtime prenosi promene blogova otvorenim SSE vezama. Svaka instanca
+// drži kratku istoriju događaja po blogu za nastavak posle prekida veze
+// (Last-Event-ID); Relay preko RabbitMQ-a deli događaje između instanci.
+package realtime
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// DefaultHistory je broj poslednjih događaja po blogu od kojih klijent
+	// može da nastavi.
+	DefaultHistory = 100
+	// idleRetention je koliko se čuva istorija bloga koji niko ne prati.
+	idleRetention = 10 * time.Minute
+	// subscriberBuffer je broj događaja koji čekaju sporog klijenta; kada
+	// se popuni, veza se zatvara, a klijent nastavlja preko Last-Event-ID.
+	subscriberBuffer = 64
+)
+
+// Event je jedna promena bloga; ID je jedinstven na svim instancama, pa
+// klijent može da nastavi na bilo kojoj.
+type Event struct {
+	ID     string          `json:"id"`
+	BlogID string          `json:"blogId"`
+	Type   string          `json:"type"`
+	Data   json.RawMessage `json:"data"`
+}
+
+func NewEvent(blogID, eventType string, data any) (Event, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{ID: primitive.NewObjectID().Hex(), BlogID: blogID, Type: eventType, Data: body}, nil
+}
+
+// Hub raspoređuje događaje pretplatnicima na ovoj instanci. Bez Relay-a
+// radi samo lokalno, što je dovoljno za jednu instancu i testove.
+type Hub struct {
+	mu       sync.Mutex
+	history  int
+	streams  map[string]*stream
+	lastTrim time.Time
+}
+
+type stream struct {
+	events      []Event
+	updatedAt   time.Time
+	subscribers map[*Subscription]struct{}
+}
+
+func NewHub(history int) *Hub {
+	return &Hub{history: history, streams: map[string]*stream{}}
+}
+
+// Subscription prima događaje jednog bloga dok se ne zatvori. Kanal
+// Events se zatvara i kada klijent ne stigne da čita.
+type Subscription struct {
+	Events <-chan Event
+
+	hub    *Hub
+	blogID string
+	ch     chan Event
+}
+
+// Publish je isto što i Deliver; Hub tako zamenjuje Relay kada postoji
+// samo jedna instanca.
+func (h *Hub) Publish(event Event) error {
+	h.Deliver(event)
+	return nil
+}
+
+// Subscribe vraća pretplatu i događaje posle lastEventID. Ako lastEventID
+// nije prazan, a više nije u istoriji, resumed je false i klijent treba da
+// ponovo učita blog.
+func (h *Hub) Subscribe(blogID, lastEventID string) (sub *Subscription, replay []Event, resumed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s := h.stream(blogID)
+	resumed = true
+	if lastEventID != "" {
+		resumed = false
+		for i, event := range s.events {
+			if event.ID == lastEventID {
+				replay = append(replay, s.events[i+1:]...)
+				resumed = true
+				break
+			}
+		}
+	}
+
+	ch := make(chan Event, subscriberBuffer)
+	sub = &Subscription{Events: ch, hub: h, blogID: blogID, ch: ch}
+	s.subscribers[sub] = struct{}{}
+	return sub, replay, resumed
+}
+
+// Close je bezbedan i kada je hub već zatvorio pretplatu.
+func (sub *Subscription) Close() {
+	h := sub.hub
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if s, ok := h.streams[sub.blogID]; ok {
+		if _, ok := s.subscribers[sub]; ok {
+			delete(s.subscribers, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// Deliver upisuje događaj u istoriju bloga i šalje ga pretplatnicima.
+// Događaj koji je već isporučen se preskače.
+func (h *Hub) Deliver(event Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	h.trim(now)
+
+	s := h.stream(event.BlogID)
+	for _, seen := range s.events {
+		if seen.ID == event.ID {
+			return
+		}
+	}
+	s.events = append(s.events, event)
+	if len(s.events) > h.history {
+		s.events = s.events[len(s.events)-h.history:]
+	}
+	s.updatedAt = now
+
+	for sub := range s.subscribers {
+		select {
+		case sub.ch <- event:
+		default:
+			delete(s.subscribers, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+func (h *Hub) stream(blogID string) *stream {
+	s, ok := h.streams[blogID]
+	if !ok {
+		s = &stream{subscribers: map[*Subscription]struct{}{}, updatedAt: time.Now()}
+		h.streams[blogID] = s
+	}
+	return s
+}
+
+// trim najviše jednom u minutu briše istoriju blogova koje niko ne prati.
+func (h *Hub) trim(now time.Time) {
+	if now.Sub(h.lastTrim) < time.Minute {
+		return
+	}
+	h.lastTrim = now
+	for blogID, s := range h.streams {
+		if len(s.subscribers) == 0 && now.Sub(s.updatedAt) > idleRetention {
+			delete(h.streams, blogID)
+		}
+	}
+}
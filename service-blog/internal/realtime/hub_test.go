@@ -0,0 +1,65 @@
+package realtime
+
+import (
+	"testing"
+)
+
+func mustEvent(t *testing.T, blogID, eventType string) Event {
+	t.Helper()
+	event, err := NewEvent(blogID, eventType, map[string]int{"likeCount": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func TestHubReplayAndResume(t *testing.T) {
+	hub := NewHub(2)
+	first, second, third := mustEvent(t, "b1", "a"), mustEvent(t, "b1", "b"), mustEvent(t, "b1", "c")
+	hub.Deliver(first)
+	hub.Deliver(mustEvent(t, "b2", "drugi blog"))
+	hub.Deliver(second)
+
+	sub, replay, resumed := hub.Subscribe("b1", first.ID)
+	defer sub.Close()
+	if !resumed || len(replay) != 1 || replay[0].ID != second.ID {
+		t.Fatalf("nastavak od prvog: %v, %+v", resumed, replay)
+	}
+
+	// Ponovljen događaj (npr. sopstveni preko Relay-a) se ne šalje dvaput.
+	hub.Deliver(third)
+	hub.Deliver(third)
+	if got := <-sub.Events; got.ID != third.ID {
+		t.Errorf("uživo = %+v", got)
+	}
+	select {
+	case got := <-sub.Events:
+		t.Errorf("duplikat = %+v", got)
+	default:
+	}
+
+	// Istorija čuva poslednja dva događaja; prvi je ispao.
+	if _, replay, resumed := hub.Subscribe("b1", first.ID); resumed || len(replay) != 0 {
+		t.Errorf("nastavak od izbačenog: %v, %+v", resumed, replay)
+	}
+	if _, replay, resumed := hub.Subscribe("b1", ""); !resumed || len(replay) != 0 {
+		t.Errorf("nova veza: %v, %+v", resumed, replay)
+	}
+}
+
+func TestHubDropsSlowSubscriber(t *testing.T) {
+	hub := NewHub(DefaultHistory)
+	sub, _, _ := hub.Subscribe("b1", "")
+	for range subscriberBuffer + 1 {
+		hub.Deliver(mustEvent(t, "b1", "a"))
+	}
+
+	received := 0
+	for range sub.Events {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("primljeno %d događaja pre zatvaranja, očekivano %d", received, subscriberBuffer)
+	}
+	sub.Close()
+}
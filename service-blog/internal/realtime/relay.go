@@ -0,0 +1,138 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Svaka instanca šalje svoje događaje na fanout exchange i prima sve, pa i
+// svoje, preko privremenog reda koji nestaje sa vezom. Poruke se ne čuvaju:
+// ko propusti događaj, nastavlja preko reset-a.
+const (
+	exchangeName = "blog-live-exchange"
+	exchangeType = "fanout"
+
+	queueSize      = 1024
+	reconnectDelay = 10 * time.Second
+)
+
+var errQueueFull = errors.New("red događaja je pun")
+
+// Relay deli događaje između instanci. Publish ne čeka broker; ako slanje
+// ne uspe, događaj ipak stiže do klijenata na ovoj instanci.
+type Relay struct {
+	url   string
+	hub   *Hub
+	queue chan Event
+}
+
+func NewRelay(url string, hub *Hub) *Relay {
+	return &Relay{url: url, hub: hub, queue: make(chan Event, queueSize)}
+}
+
+func (r *Relay) Publish(event Event) error {
+	select {
+	case r.queue <- event:
+		return nil
+	default:
+		r.hub.Deliver(event)
+		return errQueueFull
+	}
+}
+
+func (r *Relay) Subscribe(blogID, lastEventID string) (*Subscription, []Event, bool) {
+	return r.hub.Subscribe(blogID, lastEventID)
+}
+
+// Run šalje i prima događaje dok se ctx ne otkaže; veza se obnavlja na
+// svakih reconnectDelay, a dok je nema, događaji ostaju lokalni.
+func (r *Relay) Run(ctx context.Context) {
+	for {
+		if err := r.relay(ctx); err != nil {
+			log.Printf("⚠️ Deljenje događaja uživo: %s; novi pokušaj za %s", err, reconnectDelay)
+		}
+		retry := time.After(reconnectDelay)
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-r.queue:
+				r.hub.Deliver(event)
+			case <-retry:
+				break wait
+			}
+		}
+	}
+}
+
+func (r *Relay) relay(ctx context.Context) error {
+	conn, err := amqp.Dial(r.url)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	if err := ch.ExchangeDeclare(exchangeName, exchangeType, true, false, false, false, nil); err != nil {
+		return err
+	}
+	q, err := ch.QueueDeclare("", false, true, true, false, nil)
+	if err != nil {
+		return err
+	}
+	if err := ch.QueueBind(q.Name, "", exchangeName, false, nil); err != nil {
+		return err
+	}
+	msgs, err := ch.Consume(q.Name, "", true, true, false, false, nil)
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Deljenje događaja uživo pokrenuto")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-msgs:
+			if !ok {
+				return amqp.ErrClosed
+			}
+			var event Event
+			if err := json.Unmarshal(d.Body, &event); err != nil {
+				log.Printf("Neispravan događaj uživo: %s", err)
+				continue
+			}
+			r.hub.Deliver(event)
+		case event := <-r.queue:
+			if err := r.send(ctx, ch, event); err != nil {
+				r.hub.Deliver(event)
+				return err
+			}
+		}
+	}
+}
+
+func (r *Relay) send(ctx context.Context, ch *amqp.Channel, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return ch.PublishWithContext(ctx, exchangeName, "", false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Transient,
+		Body:         body,
+	})
+}
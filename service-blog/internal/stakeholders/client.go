@@ -0,0 +1,33 @@
+// Package stakeholders proverava korisnike koje blogovi i komentari
+// pominju preko REST API-ja service-stakeholders.
+package stakeholders
+
+import (
+	"context"
+	"time"
+
+	"shared/stakeholders"
+)
+
+// serviceName je identitet koji blog servis šalje service-stakeholders.
+const serviceName = "blog-service"
+
+// Client čita korisnike preko REST API-ja service-stakeholders.
+type Client struct {
+	client *stakeholders.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{client: stakeholders.NewClient(baseURL, serviceName, 3*time.Second)}
+}
+
+// UserID vraća ID korisnika sa korisničkim imenom username; ok je false
+// ako korisnik ne postoji ili je blokiran. ID blokiranog korisnika se
+// ipak vraća.
+func (c *Client) UserID(ctx context.Context, username string) (int64, bool, error) {
+	u, found, err := c.client.UserByUsername(ctx, username)
+	if err != nil || !found {
+		return 0, false, err
+	}
+	return u.ID, !u.Blocked, nil
+}
@@ -0,0 +1,66 @@
+// Package tours povezuje blog servis sa service-tours: proverava ture i
+// ključne tačke na koje blog upućuje i prati brisanje tura.
+package tours
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client čita ture preko REST API-ja service-tours.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 3 * time.Second},
+	}
+}
+
+type keyPoint struct {
+	ID int64 `json:"id"`
+}
+
+// KeyPointIDs vraća ID-jeve ključnih tačaka ture; found je false ako tura
+// ne postoji. Ruta /keypoints/tour/{id} ne proverava autora ture, pa blog
+// može da upućuje i na tuđu turu.
+func (c *Client) KeyPointIDs(ctx context.Context, tourID int64, username string) ([]int64, bool, error) {
+	url := c.baseURL + "/api/tours/keypoints/tour/" + strconv.FormatInt(tourID, 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("X-Username", username)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, false, nil
+	default:
+		return nil, false, fmt.Errorf("service-tours je vratio %d za turu %d", resp.StatusCode, tourID)
+	}
+
+	var keyPoints []keyPoint
+	if err := json.NewDecoder(resp.Body).Decode(&keyPoints); err != nil {
+		return nil, false, fmt.Errorf("neispravan odgovor service-tours: %w", err)
+	}
+	ids := make([]int64, len(keyPoints))
+	for i, kp := range keyPoints {
+		ids[i] = kp.ID
+	}
+	return ids, true, nil
+}
@@ -0,0 +1,46 @@
+package tours
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestKeyPointIDs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Username") != "ana" {
+			t.Errorf("X-Username = %q", r.Header.Get("X-Username"))
+		}
+		switch r.URL.Path {
+		case "/api/tours/keypoints/tour/7":
+			w.Write([]byte(`[{"id":1,"naziv":"Start"},{"id":2,"naziv":"Vrh"}]`))
+		case "/api/tours/keypoints/tour/8":
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Tura nije pronađena ili ne pripada autoru"}`))
+		case "/api/tours/keypoints/tour/9":
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL + "/")
+	ctx := context.Background()
+
+	ids, found, err := client.KeyPointIDs(ctx, 7, "ana")
+	if err != nil || !found || !slices.Equal(ids, []int64{1, 2}) {
+		t.Errorf("tura 7 = %v, %t, %v", ids, found, err)
+	}
+	if _, found, err := client.KeyPointIDs(ctx, 8, "ana"); err != nil || found {
+		t.Errorf("tura 8 = %t, %v; očekivano nije pronađena", found, err)
+	}
+	if _, _, err := client.KeyPointIDs(ctx, 9, "ana"); err == nil {
+		t.Error("400 iz service-tours mora da vrati grešku, a ne nepostojeću turu")
+	}
+	if _, _, err := client.KeyPointIDs(ctx, 10, "ana"); err == nil {
+		t.Error("500 iz service-tours mora da vrati grešku")
+	}
+}
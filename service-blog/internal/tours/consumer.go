@@ -0,0 +1,113 @@
+package tours
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// service-tours (TourEventPublisher) objavljuje tour.deleted na topic
+// exchange posle brisanja ture; telo poruke je ID ture kao tekst.
+const (
+	exchangeName = "tour-events-exchange"
+	exchangeType = "topic"
+	queueName    = "blog-tour-events-queue"
+
+	routingKeyTourDeleted = "tour.deleted"
+
+	reconnectDelay = 10 * time.Second
+)
+
+// Unlinker uklanja vezu blogova sa obrisanom turom.
+type Unlinker interface {
+	UnlinkTour(tourID int64) (int64, error)
+}
+
+// StartConsumer prati događaje o turama dok se ctx ne otkaže. Za razliku
+// od follower servisa, nedostupan RabbitMQ ne ruši blog servis: veza se
+// obnavlja na svakih reconnectDelay.
+func StartConsumer(ctx context.Context, url string, unlinker Unlinker) {
+	for {
+		if err := consume(ctx, url, unlinker); err != nil {
+			log.Printf("⚠️ Potrošač događaja o turama: %s; novi pokušaj za %s", err, reconnectDelay)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func consume(ctx context.Context, url string, unlinker Unlinker) error {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	if err := ch.ExchangeDeclare(exchangeName, exchangeType, true, false, false, false, nil); err != nil {
+		return err
+	}
+	q, err := ch.QueueDeclare(queueName, true, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+	if err := ch.QueueBind(q.Name, routingKeyTourDeleted, exchangeName, false, nil); err != nil {
+		return err
+	}
+	msgs, err := ch.Consume(q.Name, "", false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Potrošač događaja o turama pokrenut")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-msgs:
+			if !ok {
+				return amqp.ErrClosed
+			}
+			handle(d, unlinker)
+		}
+	}
+}
+
+func handle(d amqp.Delivery, unlinker Unlinker) {
+	if d.RoutingKey != routingKeyTourDeleted {
+		log.Printf("Nepoznat routing key: %s", d.RoutingKey)
+		d.Ack(false)
+		return
+	}
+
+	tourID, err := strconv.ParseInt(strings.TrimSpace(string(d.Body)), 10, 64)
+	if err != nil {
+		log.Printf("Greška pri konverziji ID-a ture '%s': %s", d.Body, err)
+		d.Nack(false, false)
+		return
+	}
+
+	unlinked, err := unlinker.UnlinkTour(tourID)
+	if err != nil {
+		// Poruka se vraća u red; UnlinkTour je idempotentan. Pauza sprečava
+		// vrtenje iste poruke dok je baza nedostupna.
+		log.Printf("Greška pri uklanjanju veze sa turom %d: %s", tourID, err)
+		time.Sleep(time.Second)
+		d.Nack(false, true)
+		return
+	}
+	log.Printf("Tura %d obrisana, uklonjena veza sa %d blogova", tourID, unlinked)
+	d.Ack(false)
+}
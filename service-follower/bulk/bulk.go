@@ -0,0 +1,295 @@
+// Package bulk uvozi i izvozi :User čvorove i FOLLOWS veze u NDJSON ili CSV
+// formatu. Koriste ga i HTTP admin rute i CLI podkomande.
+//
+// NDJSON: jedan objekat po liniji, npr.
+//
+//	{"type":"user","id":1,"private":false}
+//	{"type":"follow","followerId":1,"followedId":2}
+//
+// CSV: prva kolona je tip, npr. "user,1,false" i "follow,1,2".
+// Linija koja počinje sa "type" tretira se kao zaglavlje.
+package bulk
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"follower-service/db"
+)
+
+const (
+	FormatNDJSON = "ndjson"
+	FormatCSV    = "csv"
+
+	TypeUser   = "user"
+	TypeFollow = "follow"
+
+	// BatchSize je broj zapisa po jednom UNWIND upitu.
+	BatchSize = 500
+	// maxReportedErrors ograničava veličinu izveštaja kod loših fajlova.
+	maxReportedErrors = 100
+)
+
+type Record struct {
+	Type       string `json:"type"`
+	ID         int64  `json:"id,omitempty"`
+	Private    *bool  `json:"private,omitempty"`
+	FollowerID int64  `json:"followerId,omitempty"`
+	FollowedID int64  `json:"followedId,omitempty"`
+}
+
+type LineError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// Report opisuje ishod uvoza. FollowsSkipped su veze čiji korisnici
+// ne postoje ili su blokirani.
+type Report struct {
+	Users          int         `json:"users"`
+	Follows        int         `json:"follows"`
+	FollowsSkipped int         `json:"followsSkipped"`
+	Errors         []LineError `json:"errors,omitempty"`
+}
+
+// Store je deo grafa koji uvoz i izvoz koriste; implementira ga db.Neo4jRepository.
+type Store interface {
+	MergeUsers(ctx context.Context, users []db.UserRecord) error
+	MergeFollows(ctx context.Context, follows []db.FollowRecord) (int64, error)
+	ExportUsers(ctx context.Context, fn func(db.UserRecord) error) error
+	ExportFollows(ctx context.Context, fn func(db.FollowRecord) error) error
+}
+
+func ValidFormat(format string) bool {
+	return format == FormatNDJSON || format == FormatCSV
+}
+
+// Import čita zapise iz r i upisuje ih u grupama. Neispravne linije se
+// preskaču i navode u izveštaju; greška baze prekida uvoz.
+func Import(ctx context.Context, store Store, r io.Reader, format string) (Report, error) {
+	imp := &importer{ctx: ctx, store: store}
+
+	var err error
+	switch format {
+	case FormatNDJSON:
+		err = imp.readNDJSON(r)
+	case FormatCSV:
+		err = imp.readCSV(r)
+	default:
+		return Report{}, fmt.Errorf("nepoznat format: %s", format)
+	}
+	if err != nil {
+		return imp.report, err
+	}
+
+	if err := imp.flush(); err != nil {
+		return imp.report, err
+	}
+	return imp.report, nil
+}
+
+type importer struct {
+	ctx     context.Context
+	store   Store
+	users   []db.UserRecord
+	follows []db.FollowRecord
+	report  Report
+}
+
+func (imp *importer) readNDJSON(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var rec Record
+		if err := json.Unmarshal([]byte(text), &rec); err != nil {
+			imp.lineError(line, "neispravan JSON")
+			continue
+		}
+		if err := imp.add(line, rec); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func (imp *importer) readCSV(r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				imp.lineError(parseErr.Line, "neispravan CSV red")
+				continue
+			}
+			return err
+		}
+		if len(fields) == 0 || strings.EqualFold(fields[0], "type") {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+
+		rec, err := parseCSVRecord(fields)
+		if err != nil {
+			imp.lineError(line, err.Error())
+			continue
+		}
+		if err := imp.add(line, rec); err != nil {
+			return err
+		}
+	}
+}
+
+func parseCSVRecord(fields []string) (Record, error) {
+	rec := Record{Type: strings.ToLower(fields[0])}
+	switch rec.Type {
+	case TypeUser:
+		if len(fields) < 2 {
+			return rec, fmt.Errorf("user red mora imati id")
+		}
+		id, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return rec, fmt.Errorf("id mora biti broj")
+		}
+		rec.ID = id
+		if len(fields) > 2 && fields[2] != "" {
+			private, err := strconv.ParseBool(fields[2])
+			if err != nil {
+				return rec, fmt.Errorf("private mora biti true ili false")
+			}
+			rec.Private = &private
+		}
+	case TypeFollow:
+		if len(fields) < 3 {
+			return rec, fmt.Errorf("follow red mora imati followerId i followedId")
+		}
+		followerID, err1 := strconv.ParseInt(fields[1], 10, 64)
+		followedID, err2 := strconv.ParseInt(fields[2], 10, 64)
+		if err1 != nil || err2 != nil {
+			return rec, fmt.Errorf("ID-jevi moraju biti brojevi")
+		}
+		rec.FollowerID = followerID
+		rec.FollowedID = followedID
+	}
+	return rec, nil
+}
+
+func (imp *importer) add(line int, rec Record) error {
+	switch rec.Type {
+	case TypeUser:
+		if rec.ID <= 0 {
+			imp.lineError(line, "id mora biti pozitivan")
+			return nil
+		}
+		imp.users = append(imp.users, db.UserRecord{ID: rec.ID, Private: rec.Private})
+		if len(imp.users) >= BatchSize {
+			return imp.flushUsers()
+		}
+	case TypeFollow:
+		if rec.FollowerID <= 0 || rec.FollowedID <= 0 {
+			imp.lineError(line, "followerId i followedId moraju biti pozitivni")
+			return nil
+		}
+		imp.follows = append(imp.follows, db.FollowRecord{FollowerID: rec.FollowerID, FollowedID: rec.FollowedID})
+		if len(imp.follows) >= BatchSize {
+			return imp.flush()
+		}
+	default:
+		imp.lineError(line, "nepoznat tip zapisa: "+rec.Type)
+	}
+	return nil
+}
+
+// flush uvek prvo upisuje korisnike, da bi veze iz iste grupe mogle
+// da ih pronađu.
+func (imp *importer) flush() error {
+	if err := imp.flushUsers(); err != nil {
+		return err
+	}
+	if len(imp.follows) == 0 {
+		return nil
+	}
+
+	applied, err := imp.store.MergeFollows(imp.ctx, imp.follows)
+	if err != nil {
+		return err
+	}
+	imp.report.Follows += int(applied)
+	imp.report.FollowsSkipped += len(imp.follows) - int(applied)
+	imp.follows = imp.follows[:0]
+	return nil
+}
+
+func (imp *importer) flushUsers() error {
+	if len(imp.users) == 0 {
+		return nil
+	}
+	if err := imp.store.MergeUsers(imp.ctx, imp.users); err != nil {
+		return err
+	}
+	imp.report.Users += len(imp.users)
+	imp.users = imp.users[:0]
+	return nil
+}
+
+func (imp *importer) lineError(line int, message string) {
+	if len(imp.report.Errors) < maxReportedErrors {
+		imp.report.Errors = append(imp.report.Errors, LineError{Line: line, Message: message})
+	}
+}
+
+// Export upisuje sve korisnike, pa sve FOLLOWS veze u w.
+func Export(ctx context.Context, store Store, w io.Writer, format string) error {
+	switch format {
+	case FormatNDJSON:
+		enc := json.NewEncoder(w)
+		if err := store.ExportUsers(ctx, func(u db.UserRecord) error {
+			return enc.Encode(Record{Type: TypeUser, ID: u.ID, Private: u.Private})
+		}); err != nil {
+			return err
+		}
+		return store.ExportFollows(ctx, func(f db.FollowRecord) error {
+			return enc.Encode(Record{Type: TypeFollow, FollowerID: f.FollowerID, FollowedID: f.FollowedID})
+		})
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"type", "id_or_follower", "private_or_followed"}); err != nil {
+			return err
+		}
+		if err := store.ExportUsers(ctx, func(u db.UserRecord) error {
+			return cw.Write([]string{TypeUser, strconv.FormatInt(u.ID, 10), strconv.FormatBool(*u.Private)})
+		}); err != nil {
+			return err
+		}
+		if err := store.ExportFollows(ctx, func(f db.FollowRecord) error {
+			return cw.Write([]string{TypeFollow, strconv.FormatInt(f.FollowerID, 10), strconv.FormatInt(f.FollowedID, 10)})
+		}); err != nil {
+			return err
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("nepoznat format: %s", format)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"follower-service/bulk"
+	"follower-service/db"
+	"follower-service/migrations"
+	"follower-service/reconcile"
+)
+
+// runCommand izvršava CLI podkomandu ("import", "export" ili "reconcile")
+// umesto HTTP servera.
+// Vraća false ako prvi argument nije poznata podkomanda.
+func runCommand(args []string) (bool, error) {
+	if len(args) == 0 {
+		return false, nil
+	}
+
+	switch args[0] {
+	case "import":
+		return true, runImport(args[1:])
+	case "export":
+		return true, runExport(args[1:])
+	case "reconcile":
+		return true, runReconcile(args[1:])
+	default:
+		return false, nil
+	}
+}
+
+func runImport(args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	format := fs.String("format", bulk.FormatNDJSON, "ndjson ili csv")
+	in := fs.String("in", "-", "ulazni fajl, - za stdin")
+	fs.Parse(args)
+
+	if !bulk.ValidFormat(*format) {
+		return fmt.Errorf("nepoznat format: %s", *format)
+	}
+
+	var r io.Reader = os.Stdin
+	if *in != "-" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	db.InitDB()
+	defer db.CloseDB()
+
+	if err := migrations.Run(context.Background()); err != nil {
+		return err
+	}
+
+	report, err := bulk.Import(context.Background(), db.NewNeo4jRepository(db.Driver), r, *format)
+	enc := json.NewEncoder(os.Stderr)
+	enc.SetIndent("", "  ")
+	enc.Encode(report)
+	return err
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", bulk.FormatNDJSON, "ndjson ili csv")
+	out := fs.String("out", "-", "izlazni fajl, - za stdout")
+	fs.Parse(args)
+
+	if !bulk.ValidFormat(*format) {
+		return fmt.Errorf("nepoznat format: %s", *format)
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	db.InitDB()
+	defer db.CloseDB()
+
+	return bulk.Export(context.Background(), db.NewNeo4jRepository(db.Driver), w, *format)
+}
+
+func runReconcile(args []string) error {
+	fs := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	orphans := fs.String("orphans", reconcile.OrphansFlag, "flag ili delete")
+	dryRun := fs.Bool("dry-run", false, "samo prikaži razlike")
+	fs.Parse(args)
+
+	db.InitDB()
+	defer db.CloseDB()
+
+	rc := reconcile.NewReconciler(reconcile.NewStakeholdersClient(stakeholdersURL()), db.NewNeo4jRepository(db.Driver))
+	report, err := rc.Run(context.Background(), reconcile.Options{Orphans: *orphans, DryRun: *dryRun})
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(report)
+	return err
+}
@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Relationship opisuje odnos korisnika A prema korisniku B. Mutual su
+// korisnici koje A prati, a koji prate B ("prati ga X i još 3 koje znaš").
+type Relationship struct {
+	AFollowsB    bool
+	BFollowsA    bool
+	MutualCount  int64
+	MutualSample []int64
+	// Distance je dužina najkraćeg FOLLOWS puta od A do B, nil ako puta nema
+	// u okviru zadatog ograničenja.
+	Distance *int64
+}
+
+type FollowCounts struct {
+	Followers int64
+	Following int64
+}
+
+func (repo *Neo4jRepository) GetRelationship(ctx context.Context, userID, otherID int64, sampleSize, maxDistance int) (Relationship, error) {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		query := `
+            MATCH (a:User {id: $userId})
+            MATCH (b:User {id: $otherId})
+            OPTIONAL MATCH (a)-[:FOLLOWS]->(m:User)-[:FOLLOWS]->(b)
+            WITH a, b, m ORDER BY m.id
+            WITH a, b, collect(m.id) AS mutualIds
+            RETURN EXISTS { (a)-[:FOLLOWS]->(b) } AS aFollowsB,
+                   EXISTS { (b)-[:FOLLOWS]->(a) } AS bFollowsA,
+                   size(mutualIds) AS mutualCount,
+                   mutualIds[0..$sampleSize] AS mutualSample
+        `
+		params := map[string]any{
+			"userId":     userID,
+			"otherId":    otherID,
+			"sampleSize": sampleSize,
+		}
+
+		res, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		record, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		aFollowsB, _ := record.Get("aFollowsB")
+		bFollowsA, _ := record.Get("bFollowsA")
+		mutualCount, _ := record.Get("mutualCount")
+		mutualSample, _ := record.Get("mutualSample")
+
+		rel := Relationship{
+			AFollowsB:   aFollowsB.(bool),
+			BFollowsA:   bFollowsA.(bool),
+			MutualCount: mutualCount.(int64),
+		}
+		for _, id := range mutualSample.([]any) {
+			rel.MutualSample = append(rel.MutualSample, id.(int64))
+		}
+
+		// Dužina puta ne može biti parametar, pa je maxDistance uvek int iz servisa.
+		query = `
+            MATCH (a:User {id: $userId})
+            MATCH (b:User {id: $otherId})
+            OPTIONAL MATCH p = shortestPath((a)-[:FOLLOWS*..` + strconv.Itoa(maxDistance) + `]->(b))
+            RETURN length(p) AS distance
+        `
+		res, err = tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		record, err = res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if distance, _ := record.Get("distance"); distance != nil {
+			d := distance.(int64)
+			rel.Distance = &d
+		}
+		return rel, nil
+	})
+
+	if err != nil {
+		log.Printf("Greška pri računanju odnosa %d -> %d: %v", userID, otherID, err)
+		return Relationship{}, err
+	}
+	return result.(Relationship), nil
+}
+
+func (repo *Neo4jRepository) GetFollowCounts(ctx context.Context, userID int64) (FollowCounts, error) {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		// COUNT {} nad vezama jednog čvora Neo4j čita iz degree podataka,
+		// bez obilaska susednih čvorova.
+		query := `
+            MATCH (u:User {id: $userId})
+            RETURN COUNT { (u)<-[:FOLLOWS]-() } AS followers,
+                   COUNT { (u)-[:FOLLOWS]->() } AS following
+        `
+		res, err := tx.Run(ctx, query, map[string]any{"userId": userID})
+		if err != nil {
+			return nil, err
+		}
+		record, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		followers, _ := record.Get("followers")
+		following, _ := record.Get("following")
+		return FollowCounts{
+			Followers: followers.(int64),
+			Following: following.(int64),
+		}, nil
+	})
+
+	if err != nil {
+		log.Printf("Greška pri brojanju veza za korisnika %d: %v", userID, err)
+		return FollowCounts{}, err
+	}
+	return result.(FollowCounts), nil
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"context"
+	"log"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// CreateBlock u jednoj transakciji upisuje BLOCKS vezu i briše
+// FOLLOWS i REQUESTED veze u oba smera između dva korisnika.
+func (repo *Neo4jRepository) CreateBlock(ctx context.Context, blockerID, blockedID int64) error {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		query := `
+            MATCH (blocker:User {id: $blockerId})
+            MATCH (blocked:User {id: $blockedId})
+            MERGE (blocker)-[:BLOCKS]->(blocked)
+            WITH blocker, blocked
+            OPTIONAL MATCH (blocker)-[rel:FOLLOWS|REQUESTED]-(blocked)
+            DELETE rel
+        `
+		params := map[string]any{
+			"blockerId": blockerID,
+			"blockedId": blockedID,
+		}
+
+		result, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		return result.Consume(ctx)
+	})
+
+	if err != nil {
+		log.Printf("Greška pri blokiranju %d -> %d: %v", blockerID, blockedID, err)
+	}
+	return err
+}
+
+func (repo *Neo4jRepository) DeleteBlock(ctx context.Context, blockerID, blockedID int64) (bool, error) {
+	return repo.deleteRelationship(ctx, "BLOCKS", blockerID, blockedID)
+}
+
+func (repo *Neo4jRepository) GetBlocked(ctx context.Context, userID int64) ([]int64, error) {
+	query := `
+        MATCH (:User {id: $userId})-[:BLOCKS]->(blocked:User)
+        RETURN blocked.id AS id
+        ORDER BY id
+    `
+	return repo.readUserIDs(ctx, query, map[string]any{"userId": userID})
+}
+
+// IsBlockedEitherWay proverava da li je bilo koji od dva korisnika blokirao drugog.
+func (repo *Neo4jRepository) IsBlockedEitherWay(ctx context.Context, firstID, secondID int64) (bool, error) {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		query := `
+            OPTIONAL MATCH (:User {id: $firstId})-[b:BLOCKS]-(:User {id: $secondId})
+            RETURN count(b) > 0 AS blocked
+        `
+		params := map[string]any{
+			"firstId":  firstID,
+			"secondId": secondID,
+		}
+
+		res, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		record, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		blocked, _ := record.Get("blocked")
+		return blocked.(bool), nil
+	})
+
+	if err != nil {
+		log.Printf("Greška pri proveri blokade %d <-> %d: %v", firstID, secondID, err)
+		return false, err
+	}
+	return result.(bool), nil
+}
+
+func (repo *Neo4jRepository) CreateMute(ctx context.Context, muterID, mutedID int64) error {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		query := `
+            MATCH (muter:User {id: $muterId})
+            MATCH (muted:User {id: $mutedId})
+            MERGE (muter)-[:MUTES]->(muted)
+        `
+		params := map[string]any{
+			"muterId": muterID,
+			"mutedId": mutedID,
+		}
+
+		result, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		return result.Consume(ctx)
+	})
+
+	if err != nil {
+		log.Printf("Greška pri utišavanju %d -> %d: %v", muterID, mutedID, err)
+	}
+	return err
+}
+
+func (repo *Neo4jRepository) DeleteMute(ctx context.Context, muterID, mutedID int64) (bool, error) {
+	return repo.deleteRelationship(ctx, "MUTES", muterID, mutedID)
+}
+
+func (repo *Neo4jRepository) GetMuted(ctx context.Context, userID int64) ([]int64, error) {
+	query := `
+        MATCH (:User {id: $userId})-[:MUTES]->(muted:User)
+        RETURN muted.id AS id
+        ORDER BY id
+    `
+	return repo.readUserIDs(ctx, query, map[string]any{"userId": userID})
+}
+
+// deleteRelationship briše usmerenu vezu zadatog tipa. relType je uvek
+// konstanta iz ovog paketa jer Cypher ne dozvoljava parametar za tip veze.
+func (repo *Neo4jRepository) deleteRelationship(ctx context.Context, relType string, fromID, toID int64) (bool, error) {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		query := "MATCH (:User {id: $fromId})-[rel:" + relType + "]->(:User {id: $toId}) DELETE rel"
+		params := map[string]any{
+			"fromId": fromID,
+			"toId":   toID,
+		}
+
+		res, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		summary, err := res.Consume(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return summary.Counters().RelationshipsDeleted() > 0, nil
+	})
+
+	if err != nil {
+		log.Printf("Greška pri brisanju %s veze %d -> %d: %v", relType, fromID, toID, err)
+		return false, err
+	}
+	return result.(bool), nil
+}
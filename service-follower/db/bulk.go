@@ -0,0 +1,149 @@
+package db
+
+import (
+	"context"
+	"log"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// UserRecord je korisnik iz uvoza; Private je nil kada izvor ne navodi
+// privatnost, pa postojeća vrednost ostaje.
+type UserRecord struct {
+	ID      int64
+	Private *bool
+}
+
+type FollowRecord struct {
+	FollowerID int64
+	FollowedID int64
+}
+
+// MergeUsers upisuje grupu korisnika jednim UNWIND upitom; ponovni uvoz
+// istih podataka ne menja graf, a korisnik bez private zadržava privatnost.
+func (repo *Neo4jRepository) MergeUsers(ctx context.Context, users []UserRecord) error {
+	rows := make([]map[string]any, 0, len(users))
+	for _, u := range users {
+		row := map[string]any{"id": u.ID, "private": nil}
+		if u.Private != nil {
+			row["private"] = *u.Private
+		}
+		rows = append(rows, row)
+	}
+
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		query := `
+            UNWIND $rows AS row
+            MERGE (u:User {id: row.id})
+            SET u.private = coalesce(row.private, u.private, false)
+        `
+		result, err := tx.Run(ctx, query, map[string]any{"rows": rows})
+		if err != nil {
+			return nil, err
+		}
+		return result.Consume(ctx)
+	})
+
+	if err != nil {
+		log.Printf("Greška pri uvozu %d korisnika: %v", len(users), err)
+	}
+	return err
+}
+
+// MergeFollows upisuje grupu FOLLOWS veza i vraća koliko ih je primenjeno.
+// Veze čiji korisnici ne postoje ili su međusobno blokirani se preskaču.
+func (repo *Neo4jRepository) MergeFollows(ctx context.Context, follows []FollowRecord) (int64, error) {
+	rows := make([]map[string]any, 0, len(follows))
+	for _, f := range follows {
+		rows = append(rows, map[string]any{"followerId": f.FollowerID, "followedId": f.FollowedID})
+	}
+
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		query := `
+            UNWIND $rows AS row
+            MATCH (follower:User {id: row.followerId})
+            MATCH (followed:User {id: row.followedId})
+            WHERE follower <> followed AND NOT (follower)-[:BLOCKS]-(followed)
+            MERGE (follower)-[:FOLLOWS]->(followed)
+            RETURN count(*) AS applied
+        `
+		res, err := tx.Run(ctx, query, map[string]any{"rows": rows})
+		if err != nil {
+			return nil, err
+		}
+		record, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		applied, _ := record.Get("applied")
+		return applied.(int64), nil
+	})
+
+	if err != nil {
+		log.Printf("Greška pri uvozu %d veza: %v", len(follows), err)
+		return 0, err
+	}
+	return result.(int64), nil
+}
+
+// ExportUsers prolazi kroz sve korisnike redom i za svakog poziva fn,
+// bez učitavanja celog grafa u memoriju.
+func (repo *Neo4jRepository) ExportUsers(ctx context.Context, fn func(UserRecord) error) error {
+	query := `
+        MATCH (u:User)
+        RETURN u.id AS id, coalesce(u.private, false) AS private
+        ORDER BY id
+    `
+	return repo.streamRecords(ctx, query, func(record *neo4j.Record) error {
+		id, _ := record.Get("id")
+		private, _ := record.Get("private")
+		isPrivate := private.(bool)
+		return fn(UserRecord{ID: id.(int64), Private: &isPrivate})
+	})
+}
+
+func (repo *Neo4jRepository) ExportFollows(ctx context.Context, fn func(FollowRecord) error) error {
+	query := `
+        MATCH (follower:User)-[:FOLLOWS]->(followed:User)
+        RETURN follower.id AS followerId, followed.id AS followedId
+        ORDER BY followerId, followedId
+    `
+	return repo.streamRecords(ctx, query, func(record *neo4j.Record) error {
+		followerID, _ := record.Get("followerId")
+		followedID, _ := record.Get("followedId")
+		return fn(FollowRecord{FollowerID: followerID.(int64), FollowedID: followedID.(int64)})
+	})
+}
+
+// streamRecords čita upit u auto-commit transakciji. ExecuteRead bi upit
+// ponovio posle prolazne greške, pa bi fn drugi put dobio zapise koji su
+// već upisani u izvoz; ovako greška prekida izvoz i nijedan red se ne
+// ponavlja.
+func (repo *Neo4jRepository) streamRecords(ctx context.Context, query string, fn func(*neo4j.Record) error) error {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	err := func() error {
+		res, err := session.Run(ctx, query, nil)
+		if err != nil {
+			return err
+		}
+		for res.Next(ctx) {
+			if err := fn(res.Record()); err != nil {
+				return err
+			}
+		}
+		return res.Err()
+	}()
+
+	if err != nil {
+		log.Printf("Greška pri izvozu grafa: %v", err)
+	}
+	return err
+}
@@ -35,9 +35,17 @@ func CloseDB() {
 	}
 }
 
-func UserExists(userId int) (bool, error) {
-	ctx := context.Background()
-	session := Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+// Neo4jRepository je implementacija service.GraphRepository nad Neo4j bazom.
+type Neo4jRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func NewNeo4jRepository(driver neo4j.DriverWithContext) *Neo4jRepository {
+	return &Neo4jRepository{driver: driver}
+}
+
+func (repo *Neo4jRepository) UserExists(ctx context.Context, userId int64) (bool, error) {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
 	defer session.Close(ctx)
 
 	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (interface{}, error) {
@@ -66,10 +74,8 @@ func UserExists(userId int) (bool, error) {
 	return result.(bool), nil
 }
 
-func CreateUserNode(userId int64) error {
-	
-	ctx := context.Background()
-	session := Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+func (repo *Neo4jRepository) CreateUserNode(ctx context.Context, userId int64) error {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
 	defer session.Close(ctx)
 
 	query := "MERGE (u:User {id: $userId})"
@@ -91,9 +97,8 @@ func CreateUserNode(userId int64) error {
 }
 
 
-func DeleteUserNode(userId int64) error {
-	ctx := context.Background()
-	session := Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+func (repo *Neo4jRepository) DeleteUserNode(ctx context.Context, userId int64) error {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
 	defer session.Close(ctx)
 
 	query := "MATCH (u:User {id: $userId}) DETACH DELETE u"
@@ -0,0 +1,218 @@
+package db
+
+import (
+	"context"
+	"log"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Recommendation je korisnik koga prate ljudi koje korisnik već prati.
+type Recommendation struct {
+	UserID      int64 `json:"userId"`
+	MutualCount int64 `json:"mutualCount"`
+}
+
+// FollowResult opisuje šta je CreateFollow zatekao i upisao.
+type FollowResult struct {
+	// Found je false ako neki od dva korisnika ne postoji.
+	Found bool
+	// Blocked je true ako blokada u bilo kom smeru sprečava praćenje.
+	Blocked bool
+	// Requested je true ako je nalog privatan, pa umesto veze čeka zahtev.
+	Requested bool
+	// Created je false ako su veza ili zahtev već postojali.
+	Created bool
+}
+
+// CreateFollow u jednoj transakciji proverava blokadu i privatnost i
+// upisuje FOLLOWS vezu, odnosno REQUESTED zahtev za privatan nalog.
+func (repo *Neo4jRepository) CreateFollow(ctx context.Context, followerID, followedID int64) (FollowResult, error) {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		// SET/REMOVE zaključava oba čvora do kraja transakcije, pa blokiranje
+		// i promena privatnosti ne mogu da uđu između provere i upisa.
+		query := `
+            MATCH (follower:User {id: $followerId})
+            MATCH (followed:User {id: $followedId})
+            SET follower._lock = true, followed._lock = true
+            REMOVE follower._lock, followed._lock
+            WITH follower, followed,
+                 EXISTS { (follower)-[:BLOCKS]-(followed) } AS blocked,
+                 EXISTS { (follower)-[:FOLLOWS]->(followed) } AS following,
+                 EXISTS { (follower)-[:REQUESTED]->(followed) } AS pending,
+                 coalesce(followed.private, false) AS private
+            WITH follower, followed, blocked, following, pending,
+                 private AND NOT following AS requested
+            FOREACH (_ IN CASE WHEN NOT blocked AND NOT following AND NOT requested THEN [1] ELSE [] END |
+                MERGE (follower)-[:FOLLOWS]->(followed))
+            FOREACH (_ IN CASE WHEN NOT blocked AND requested THEN [1] ELSE [] END |
+                MERGE (follower)-[req:REQUESTED]->(followed)
+                ON CREATE SET req.createdAt = datetime())
+            RETURN blocked, requested,
+                   NOT blocked AND NOT following AND NOT (requested AND pending) AS created
+        `
+		params := map[string]any{
+			"followerId": followerID,
+			"followedId": followedID,
+		}
+
+		res, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		records, err := res.Collect(ctx)
+		if err != nil || len(records) == 0 {
+			return FollowResult{}, err
+		}
+		blocked, _ := records[0].Get("blocked")
+		requested, _ := records[0].Get("requested")
+		created, _ := records[0].Get("created")
+		return FollowResult{
+			Found:     true,
+			Blocked:   blocked.(bool),
+			Requested: requested.(bool),
+			Created:   created.(bool),
+		}, nil
+	})
+
+	if err != nil {
+		log.Printf("Greška pri kreiranju FOLLOWS veze %d -> %d: %v", followerID, followedID, err)
+		return FollowResult{}, err
+	}
+	return result.(FollowResult), nil
+}
+
+// DeleteFollow briše FOLLOWS vezu i vraća da li je veza uopšte postojala.
+func (repo *Neo4jRepository) DeleteFollow(ctx context.Context, followerID, followedID int64) (bool, error) {
+	return repo.deleteRelationship(ctx, "FOLLOWS", followerID, followedID)
+}
+
+func (repo *Neo4jRepository) GetFollowers(ctx context.Context, userID int64) ([]int64, error) {
+	query := `
+        MATCH (follower:User)-[:FOLLOWS]->(:User {id: $userId})
+        RETURN follower.id AS id
+        ORDER BY id
+    `
+	return repo.readUserIDs(ctx, query, map[string]any{"userId": userID})
+}
+
+func (repo *Neo4jRepository) GetFollowing(ctx context.Context, userID int64) ([]int64, error) {
+	query := `
+        MATCH (:User {id: $userId})-[:FOLLOWS]->(followed:User)
+        RETURN followed.id AS id
+        ORDER BY id
+    `
+	return repo.readUserIDs(ctx, query, map[string]any{"userId": userID})
+}
+
+func (repo *Neo4jRepository) IsFollowing(ctx context.Context, followerID, followedID int64) (bool, error) {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		query := `
+            OPTIONAL MATCH (:User {id: $followerId})-[f:FOLLOWS]->(:User {id: $followedId})
+            RETURN f IS NOT NULL AS following
+        `
+		params := map[string]any{
+			"followerId": followerID,
+			"followedId": followedID,
+		}
+
+		res, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		record, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		following, _ := record.Get("following")
+		return following.(bool), nil
+	})
+
+	if err != nil {
+		log.Printf("Greška pri proveri FOLLOWS veze %d -> %d: %v", followerID, followedID, err)
+		return false, err
+	}
+	return result.(bool), nil
+}
+
+// GetRecommendations predlaže korisnike koje prate oni koje userID već prati,
+// sortirano po broju zajedničkih veza.
+func (repo *Neo4jRepository) GetRecommendations(ctx context.Context, userID int64, limit int) ([]Recommendation, error) {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		query := `
+            MATCH (u:User {id: $userId})-[:FOLLOWS]->(:User)-[:FOLLOWS]->(rec:User)
+            WHERE rec <> u AND NOT (u)-[:FOLLOWS]->(rec) AND NOT (u)-[:BLOCKS]-(rec)
+            RETURN rec.id AS id, count(*) AS mutual
+            ORDER BY mutual DESC, id
+            LIMIT $limit
+        `
+		params := map[string]any{
+			"userId": userID,
+			"limit":  limit,
+		}
+
+		res, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		records, err := res.Collect(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		recommendations := make([]Recommendation, 0, len(records))
+		for _, record := range records {
+			id, _ := record.Get("id")
+			mutual, _ := record.Get("mutual")
+			recommendations = append(recommendations, Recommendation{
+				UserID:      id.(int64),
+				MutualCount: mutual.(int64),
+			})
+		}
+		return recommendations, nil
+	})
+
+	if err != nil {
+		log.Printf("Greška pri računanju preporuka za korisnika %d: %v", userID, err)
+		return nil, err
+	}
+	return result.([]Recommendation), nil
+}
+
+func (repo *Neo4jRepository) readUserIDs(ctx context.Context, query string, params map[string]any) ([]int64, error) {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		records, err := res.Collect(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := make([]int64, 0, len(records))
+		for _, record := range records {
+			id, _ := record.Get("id")
+			ids = append(ids, id.(int64))
+		}
+		return ids, nil
+	})
+
+	if err != nil {
+		log.Printf("Greška pri čitanju liste korisnika: %v", err)
+		return nil, err
+	}
+	return result.([]int64), nil
+}
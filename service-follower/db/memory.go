@@ -0,0 +1,484 @@
+package db
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+type edge struct {
+	from, to int64
+}
+
+type memoryUser struct {
+	private  bool
+	orphaned bool
+}
+
+// MemoryRepository drži graf u memoriji i ponaša se kao Neo4jRepository.
+// Namenjen je testovima i lokalnom radu bez Neo4j baze.
+type MemoryRepository struct {
+	mu        sync.RWMutex
+	users     map[int64]*memoryUser
+	follows   map[edge]bool
+	blocks    map[edge]bool
+	mutes     map[edge]bool
+	requested map[edge]time.Time
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		users:     map[int64]*memoryUser{},
+		follows:   map[edge]bool{},
+		blocks:    map[edge]bool{},
+		mutes:     map[edge]bool{},
+		requested: map[edge]time.Time{},
+	}
+}
+
+func (m *MemoryRepository) UserExists(ctx context.Context, userId int64) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.users[userId]
+	return ok, nil
+}
+
+func (m *MemoryRepository) CreateUserNode(ctx context.Context, userId int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.users[userId]; !ok {
+		m.users[userId] = &memoryUser{}
+	}
+	return nil
+}
+
+func (m *MemoryRepository) DeleteUserNode(ctx context.Context, userId int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleteUser(userId)
+	return nil
+}
+
+func (m *MemoryRepository) CreateFollow(ctx context.Context, followerID, followedID int64) (FollowResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.bothExist(followerID, followedID) {
+		return FollowResult{}, nil
+	}
+	if m.blockedEitherWay(followerID, followedID) {
+		return FollowResult{Found: true, Blocked: true}, nil
+	}
+	e := edge{followerID, followedID}
+	if m.follows[e] {
+		return FollowResult{Found: true}, nil
+	}
+	if m.users[followedID].private {
+		if _, ok := m.requested[e]; ok {
+			return FollowResult{Found: true, Requested: true}, nil
+		}
+		m.requested[e] = time.Now().UTC()
+		return FollowResult{Found: true, Requested: true, Created: true}, nil
+	}
+	m.follows[e] = true
+	return FollowResult{Found: true, Created: true}, nil
+}
+
+func (m *MemoryRepository) DeleteFollow(ctx context.Context, followerID, followedID int64) (bool, error) {
+	return m.deleteEdge(m.follows, followerID, followedID), nil
+}
+
+func (m *MemoryRepository) GetFollowers(ctx context.Context, userID int64) ([]int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return incoming(m.follows, userID), nil
+}
+
+func (m *MemoryRepository) GetFollowing(ctx context.Context, userID int64) ([]int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return outgoing(m.follows, userID), nil
+}
+
+func (m *MemoryRepository) IsFollowing(ctx context.Context, followerID, followedID int64) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.follows[edge{followerID, followedID}], nil
+}
+
+func (m *MemoryRepository) GetRecommendations(ctx context.Context, userID int64, limit int) ([]Recommendation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	mutual := map[int64]int64{}
+	for _, via := range outgoing(m.follows, userID) {
+		for _, rec := range outgoing(m.follows, via) {
+			if rec == userID || m.follows[edge{userID, rec}] || m.blockedEitherWay(userID, rec) {
+				continue
+			}
+			mutual[rec]++
+		}
+	}
+
+	recommendations := make([]Recommendation, 0, len(mutual))
+	for id, count := range mutual {
+		recommendations = append(recommendations, Recommendation{UserID: id, MutualCount: count})
+	}
+	sort.Slice(recommendations, func(i, j int) bool {
+		if recommendations[i].MutualCount != recommendations[j].MutualCount {
+			return recommendations[i].MutualCount > recommendations[j].MutualCount
+		}
+		return recommendations[i].UserID < recommendations[j].UserID
+	})
+	if len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+	return recommendations, nil
+}
+
+func (m *MemoryRepository) CreateBlock(ctx context.Context, blockerID, blockedID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.bothExist(blockerID, blockedID) {
+		return nil
+	}
+	m.blocks[edge{blockerID, blockedID}] = true
+	for _, e := range []edge{{blockerID, blockedID}, {blockedID, blockerID}} {
+		delete(m.follows, e)
+		delete(m.requested, e)
+	}
+	return nil
+}
+
+func (m *MemoryRepository) DeleteBlock(ctx context.Context, blockerID, blockedID int64) (bool, error) {
+	return m.deleteEdge(m.blocks, blockerID, blockedID), nil
+}
+
+func (m *MemoryRepository) GetBlocked(ctx context.Context, userID int64) ([]int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return outgoing(m.blocks, userID), nil
+}
+
+func (m *MemoryRepository) IsBlockedEitherWay(ctx context.Context, firstID, secondID int64) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.blockedEitherWay(firstID, secondID), nil
+}
+
+func (m *MemoryRepository) CreateMute(ctx context.Context, muterID, mutedID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.bothExist(muterID, mutedID) {
+		m.mutes[edge{muterID, mutedID}] = true
+	}
+	return nil
+}
+
+func (m *MemoryRepository) DeleteMute(ctx context.Context, muterID, mutedID int64) (bool, error) {
+	return m.deleteEdge(m.mutes, muterID, mutedID), nil
+}
+
+func (m *MemoryRepository) GetMuted(ctx context.Context, userID int64) ([]int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return outgoing(m.mutes, userID), nil
+}
+
+func (m *MemoryRepository) SetPrivate(ctx context.Context, userID int64, private bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[userID]
+	if !ok {
+		return nil
+	}
+	user.private = private
+	if private {
+		return nil
+	}
+	for e := range m.requested {
+		if e.to == userID {
+			delete(m.requested, e)
+			m.follows[e] = true
+		}
+	}
+	return nil
+}
+
+func (m *MemoryRepository) IsPrivate(ctx context.Context, userID int64) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	user, ok := m.users[userID]
+	return ok && user.private, nil
+}
+
+func (m *MemoryRepository) ApproveFollowRequest(ctx context.Context, targetID, requesterID int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := edge{requesterID, targetID}
+	if _, ok := m.requested[e]; !ok {
+		return false, nil
+	}
+	delete(m.requested, e)
+	m.follows[e] = true
+	return true, nil
+}
+
+func (m *MemoryRepository) DeleteFollowRequest(ctx context.Context, requesterID, targetID int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := edge{requesterID, targetID}
+	if _, ok := m.requested[e]; !ok {
+		return false, nil
+	}
+	delete(m.requested, e)
+	return true, nil
+}
+
+func (m *MemoryRepository) GetIncomingRequests(ctx context.Context, userID int64) ([]PendingRequest, error) {
+	return m.pendingRequests(func(e edge) bool { return e.to == userID }), nil
+}
+
+func (m *MemoryRepository) GetOutgoingRequests(ctx context.Context, userID int64) ([]PendingRequest, error) {
+	return m.pendingRequests(func(e edge) bool { return e.from == userID }), nil
+}
+
+func (m *MemoryRepository) GetRelationship(ctx context.Context, userID, otherID int64, sampleSize, maxDistance int) (Relationship, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	rel := Relationship{
+		AFollowsB:    m.follows[edge{userID, otherID}],
+		BFollowsA:    m.follows[edge{otherID, userID}],
+		MutualSample: []int64{},
+	}
+	for _, id := range outgoing(m.follows, userID) {
+		if id == otherID || !m.follows[edge{id, otherID}] {
+			continue
+		}
+		rel.MutualCount++
+		if len(rel.MutualSample) < sampleSize {
+			rel.MutualSample = append(rel.MutualSample, id)
+		}
+	}
+	rel.Distance = m.distance(userID, otherID, maxDistance)
+	return rel, nil
+}
+
+func (m *MemoryRepository) GetFollowCounts(ctx context.Context, userID int64) (FollowCounts, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return FollowCounts{
+		Followers: int64(len(incoming(m.follows, userID))),
+		Following: int64(len(outgoing(m.follows, userID))),
+	}, nil
+}
+
+func (m *MemoryRepository) MergeUsers(ctx context.Context, users []UserRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, u := range users {
+		user, ok := m.users[u.ID]
+		if !ok {
+			user = &memoryUser{}
+			m.users[u.ID] = user
+		}
+		if u.Private != nil {
+			user.private = *u.Private
+		}
+	}
+	return nil
+}
+
+func (m *MemoryRepository) MergeFollows(ctx context.Context, follows []FollowRecord) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var applied int64
+	for _, f := range follows {
+		if f.FollowerID == f.FollowedID || !m.bothExist(f.FollowerID, f.FollowedID) ||
+			m.blockedEitherWay(f.FollowerID, f.FollowedID) {
+			continue
+		}
+		m.follows[edge{f.FollowerID, f.FollowedID}] = true
+		applied++
+	}
+	return applied, nil
+}
+
+func (m *MemoryRepository) ExportUsers(ctx context.Context, fn func(UserRecord) error) error {
+	m.mu.RLock()
+	records := make([]UserRecord, 0, len(m.users))
+	for id, user := range m.users {
+		private := user.private
+		records = append(records, UserRecord{ID: id, Private: &private})
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	for _, record := range records {
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MemoryRepository) ExportFollows(ctx context.Context, fn func(FollowRecord) error) error {
+	m.mu.RLock()
+	records := make([]FollowRecord, 0, len(m.follows))
+	for e := range m.follows {
+		records = append(records, FollowRecord{FollowerID: e.from, FollowedID: e.to})
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].FollowerID != records[j].FollowerID {
+			return records[i].FollowerID < records[j].FollowerID
+		}
+		return records[i].FollowedID < records[j].FollowedID
+	})
+	for _, record := range records {
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MemoryRepository) GetAllUserIDs(ctx context.Context) ([]int64, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ids := make([]int64, 0, len(m.users))
+	for id := range m.users {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (m *MemoryRepository) DeleteUsers(ctx context.Context, ids []int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		m.deleteUser(id)
+	}
+	return nil
+}
+
+func (m *MemoryRepository) FlagOrphans(ctx context.Context, ids []int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		if user, ok := m.users[id]; ok {
+			user.orphaned = true
+		}
+	}
+	return nil
+}
+
+func (m *MemoryRepository) ClearOrphanFlags(ctx context.Context, ids []int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var cleared int64
+	for _, id := range ids {
+		if user, ok := m.users[id]; ok && user.orphaned {
+			user.orphaned = false
+			cleared++
+		}
+	}
+	return cleared, nil
+}
+
+func (m *MemoryRepository) bothExist(firstID, secondID int64) bool {
+	_, first := m.users[firstID]
+	_, second := m.users[secondID]
+	return first && second
+}
+
+func (m *MemoryRepository) blockedEitherWay(firstID, secondID int64) bool {
+	return m.blocks[edge{firstID, secondID}] || m.blocks[edge{secondID, firstID}]
+}
+
+func (m *MemoryRepository) deleteEdge(edges map[edge]bool, from, to int64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e := edge{from, to}
+	if !edges[e] {
+		return false
+	}
+	delete(edges, e)
+	return true
+}
+
+func (m *MemoryRepository) deleteUser(id int64) {
+	delete(m.users, id)
+	for _, edges := range []map[edge]bool{m.follows, m.blocks, m.mutes} {
+		for e := range edges {
+			if e.from == id || e.to == id {
+				delete(edges, e)
+			}
+		}
+	}
+	for e := range m.requested {
+		if e.from == id || e.to == id {
+			delete(m.requested, e)
+		}
+	}
+}
+
+func (m *MemoryRepository) pendingRequests(match func(edge) bool) []PendingRequest {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	requests := []PendingRequest{}
+	for e, createdAt := range m.requested {
+		if match(e) {
+			requests = append(requests, PendingRequest{RequesterID: e.from, TargetID: e.to, CreatedAt: createdAt})
+		}
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].CreatedAt.Before(requests[j].CreatedAt) })
+	return requests
+}
+
+// distance traži najkraći FOLLOWS put pretragom u širinu, kao shortestPath.
+func (m *MemoryRepository) distance(from, to int64, maxDistance int) *int64 {
+	visited := map[int64]bool{from: true}
+	frontier := []int64{from}
+	for depth := int64(1); depth <= int64(maxDistance) && len(frontier) > 0; depth++ {
+		var next []int64
+		for _, id := range frontier {
+			for _, neighbour := range outgoing(m.follows, id) {
+				if neighbour == to {
+					return &depth
+				}
+				if !visited[neighbour] {
+					visited[neighbour] = true
+					next = append(next, neighbour)
+				}
+			}
+		}
+		frontier = next
+	}
+	return nil
+}
+
+func outgoing(edges map[edge]bool, userID int64) []int64 {
+	ids := []int64{}
+	for e := range edges {
+		if e.from == userID {
+			ids = append(ids, e.to)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func incoming(edges map[edge]bool, userID int64) []int64 {
+	ids := []int64{}
+	for e := range edges {
+		if e.to == userID {
+			ids = append(ids, e.from)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,141 @@
+//go:build integration
+
+// Integracioni testovi rade nad pravom Neo4j bazom:
+//
+//	NEO4J_TEST_URI=neo4j://localhost:7687 go test -tags integration ./db/
+//
+// Bez NEO4J_TEST_URI testovi se preskaču.
+package db
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func integrationRepository(t *testing.T) *Neo4jRepository {
+	t.Helper()
+	uri := os.Getenv("NEO4J_TEST_URI")
+	if uri == "" {
+		t.Skip("NEO4J_TEST_URI nije postavljen")
+	}
+	user := os.Getenv("NEO4J_TEST_USER")
+	if user == "" {
+		user = "neo4j"
+	}
+	pass := os.Getenv("NEO4J_TEST_PASSWORD")
+	if pass == "" {
+		pass = "password"
+	}
+
+	driver, err := neo4j.NewDriverWithContext(uri, neo4j.BasicAuth(user, pass, ""))
+	if err != nil {
+		t.Fatalf("drajver: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := driver.VerifyConnectivity(ctx); err != nil {
+		driver.Close(context.Background())
+		t.Skipf("Neo4j nije dostupan: %v", err)
+	}
+	t.Cleanup(func() { driver.Close(context.Background()) })
+	return NewNeo4jRepository(driver)
+}
+
+// testUsers pravi korisnike sa ID-jevima koji se ne sudaraju sa stvarnim
+// podacima i briše ih po završetku testa.
+func testUsers(t *testing.T, repo *Neo4jRepository, count int) []int64 {
+	t.Helper()
+	ctx := context.Background()
+	base := time.Now().UnixNano() / 1000
+	ids := make([]int64, count)
+	users := make([]UserRecord, count)
+	for i := range ids {
+		ids[i] = -(base + int64(i))
+		users[i] = UserRecord{ID: ids[i]}
+	}
+	if err := repo.MergeUsers(ctx, users); err != nil {
+		t.Fatalf("MergeUsers: %v", err)
+	}
+	t.Cleanup(func() { repo.DeleteUsers(context.Background(), ids) })
+	return ids
+}
+
+func TestNeo4jFollowAndBlock(t *testing.T) {
+	repo := integrationRepository(t)
+	ctx := context.Background()
+	ids := testUsers(t, repo, 3)
+	a, b, c := ids[0], ids[1], ids[2]
+
+	for _, pair := range [][2]int64{{a, b}, {b, c}} {
+		if result, err := repo.CreateFollow(ctx, pair[0], pair[1]); err != nil || !result.Created {
+			t.Fatalf("CreateFollow = %+v, %v", result, err)
+		}
+	}
+
+	recs, err := repo.GetRecommendations(ctx, a, 10)
+	if err != nil {
+		t.Fatalf("GetRecommendations: %v", err)
+	}
+	if len(recs) != 1 || recs[0].UserID != c || recs[0].MutualCount != 1 {
+		t.Errorf("preporuke = %+v, očekivan korisnik %d", recs, c)
+	}
+
+	rel, err := repo.GetRelationship(ctx, a, c, 3, 6)
+	if err != nil {
+		t.Fatalf("GetRelationship: %v", err)
+	}
+	if rel.Distance == nil || *rel.Distance != 2 || rel.MutualCount != 1 {
+		t.Errorf("odnos = %+v, očekivana udaljenost 2 i jedan zajednički", rel)
+	}
+
+	if err := repo.CreateBlock(ctx, b, a); err != nil {
+		t.Fatalf("CreateBlock: %v", err)
+	}
+	following, err := repo.IsFollowing(ctx, a, b)
+	if err != nil {
+		t.Fatalf("IsFollowing: %v", err)
+	}
+	if following {
+		t.Error("blokada mora da ukloni postojeće praćenje")
+	}
+	blocked, err := repo.IsBlockedEitherWay(ctx, a, b)
+	if err != nil || !blocked {
+		t.Errorf("IsBlockedEitherWay = %t, %v", blocked, err)
+	}
+	result, err := repo.CreateFollow(ctx, a, b)
+	if err != nil || !result.Blocked || result.Created {
+		t.Errorf("CreateFollow posle blokade = %+v, %v", result, err)
+	}
+}
+
+func TestNeo4jFollowRequests(t *testing.T) {
+	repo := integrationRepository(t)
+	ctx := context.Background()
+	ids := testUsers(t, repo, 2)
+	requester, target := ids[0], ids[1]
+
+	if err := repo.SetPrivate(ctx, target, true); err != nil {
+		t.Fatalf("SetPrivate: %v", err)
+	}
+	result, err := repo.CreateFollow(ctx, requester, target)
+	if err != nil || !result.Requested || !result.Created {
+		t.Fatalf("CreateFollow na privatan nalog = %+v, %v", result, err)
+	}
+	incoming, err := repo.GetIncomingRequests(ctx, target)
+	if err != nil || len(incoming) != 1 || incoming[0].RequesterID != requester {
+		t.Fatalf("GetIncomingRequests = %+v, %v", incoming, err)
+	}
+
+	approved, err := repo.ApproveFollowRequest(ctx, target, requester)
+	if err != nil || !approved {
+		t.Fatalf("ApproveFollowRequest = %t, %v", approved, err)
+	}
+	following, err := repo.IsFollowing(ctx, requester, target)
+	if err != nil || !following {
+		t.Errorf("posle odobravanja IsFollowing = %t, %v", following, err)
+	}
+}
@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// PendingRequest je REQUESTED veza koja čeka odobrenje privatnog naloga.
+type PendingRequest struct {
+	RequesterID int64
+	TargetID    int64
+	CreatedAt   time.Time
+}
+
+// SetPrivate menja privacy flag na :User čvoru. Kada nalog postane javan,
+// sve zahteve koji čekaju pretvaramo u FOLLOWS veze u istoj transakciji.
+func (repo *Neo4jRepository) SetPrivate(ctx context.Context, userID int64, private bool) error {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		query := "MATCH (u:User {id: $userId}) SET u.private = $private"
+		params := map[string]any{
+			"userId":  userID,
+			"private": private,
+		}
+		result, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := result.Consume(ctx); err != nil {
+			return nil, err
+		}
+
+		if private {
+			return nil, nil
+		}
+
+		query = `
+            MATCH (requester:User)-[req:REQUESTED]->(u:User {id: $userId})
+            DELETE req
+            MERGE (requester)-[:FOLLOWS]->(u)
+        `
+		result, err = tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		return result.Consume(ctx)
+	})
+
+	if err != nil {
+		log.Printf("Greška pri promeni privatnosti za korisnika %d: %v", userID, err)
+	}
+	return err
+}
+
+func (repo *Neo4jRepository) IsPrivate(ctx context.Context, userID int64) (bool, error) {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		query := `
+            MATCH (u:User {id: $userId})
+            RETURN coalesce(u.private, false) AS private
+        `
+		res, err := tx.Run(ctx, query, map[string]any{"userId": userID})
+		if err != nil {
+			return nil, err
+		}
+		record, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		private, _ := record.Get("private")
+		return private.(bool), nil
+	})
+
+	if err != nil {
+		log.Printf("Greška pri čitanju privatnosti za korisnika %d: %v", userID, err)
+		return false, err
+	}
+	return result.(bool), nil
+}
+
+// ApproveFollowRequest pretvara REQUESTED vezu u FOLLOWS i vraća false
+// ako zahtev nije postojao.
+func (repo *Neo4jRepository) ApproveFollowRequest(ctx context.Context, targetID, requesterID int64) (bool, error) {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		query := `
+            MATCH (requester:User {id: $requesterId})-[req:REQUESTED]->(target:User {id: $targetId})
+            DELETE req
+            MERGE (requester)-[:FOLLOWS]->(target)
+        `
+		params := map[string]any{
+			"requesterId": requesterID,
+			"targetId":    targetID,
+		}
+
+		res, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		summary, err := res.Consume(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return summary.Counters().RelationshipsDeleted() > 0, nil
+	})
+
+	if err != nil {
+		log.Printf("Greška pri odobravanju zahteva %d -> %d: %v", requesterID, targetID, err)
+		return false, err
+	}
+	return result.(bool), nil
+}
+
+func (repo *Neo4jRepository) DeleteFollowRequest(ctx context.Context, requesterID, targetID int64) (bool, error) {
+	return repo.deleteRelationship(ctx, "REQUESTED", requesterID, targetID)
+}
+
+// GetIncomingRequests vraća zahteve upućene korisniku, najstariji prvi.
+func (repo *Neo4jRepository) GetIncomingRequests(ctx context.Context, userID int64) ([]PendingRequest, error) {
+	query := `
+        MATCH (requester:User)-[req:REQUESTED]->(target:User {id: $userId})
+        RETURN requester.id AS requesterId, target.id AS targetId, req.createdAt AS createdAt
+        ORDER BY createdAt
+    `
+	return repo.readPendingRequests(ctx, query, map[string]any{"userId": userID})
+}
+
+// GetOutgoingRequests vraća zahteve koje je korisnik poslao, a još nisu obrađeni.
+func (repo *Neo4jRepository) GetOutgoingRequests(ctx context.Context, userID int64) ([]PendingRequest, error) {
+	query := `
+        MATCH (requester:User {id: $userId})-[req:REQUESTED]->(target:User)
+        RETURN requester.id AS requesterId, target.id AS targetId, req.createdAt AS createdAt
+        ORDER BY createdAt
+    `
+	return repo.readPendingRequests(ctx, query, map[string]any{"userId": userID})
+}
+
+func (repo *Neo4jRepository) readPendingRequests(ctx context.Context, query string, params map[string]any) ([]PendingRequest, error) {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		records, err := res.Collect(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		requests := make([]PendingRequest, 0, len(records))
+		for _, record := range records {
+			requesterID, _ := record.Get("requesterId")
+			targetID, _ := record.Get("targetId")
+			createdAt, _ := record.Get("createdAt")
+
+			req := PendingRequest{
+				RequesterID: requesterID.(int64),
+				TargetID:    targetID.(int64),
+			}
+			if t, ok := createdAt.(time.Time); ok {
+				req.CreatedAt = t
+			}
+			requests = append(requests, req)
+		}
+		return requests, nil
+	})
+
+	if err != nil {
+		log.Printf("Greška pri čitanju zahteva za praćenje: %v", err)
+		return nil, err
+	}
+	return result.([]PendingRequest), nil
+}
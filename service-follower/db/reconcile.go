@@ -0,0 +1,87 @@
+package db
+
+import (
+	"context"
+	"log"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func (repo *Neo4jRepository) GetAllUserIDs(ctx context.Context) ([]int64, error) {
+	query := `
+        MATCH (u:User)
+        RETURN u.id AS id
+        ORDER BY id
+    `
+	return repo.readUserIDs(ctx, query, nil)
+}
+
+// DeleteUsers briše korisnike zajedno sa svim njihovim vezama.
+func (repo *Neo4jRepository) DeleteUsers(ctx context.Context, ids []int64) error {
+	query := `
+        UNWIND $ids AS id
+        MATCH (u:User {id: id})
+        DETACH DELETE u
+    `
+	return repo.writeIDs(ctx, query, ids)
+}
+
+// FlagOrphans označava korisnike koji ne postoje u stakeholders servisu,
+// bez brisanja njihovih veza.
+func (repo *Neo4jRepository) FlagOrphans(ctx context.Context, ids []int64) error {
+	query := `
+        UNWIND $ids AS id
+        MATCH (u:User {id: id})
+        SET u.orphaned = true, u.orphanedAt = coalesce(u.orphanedAt, datetime())
+    `
+	return repo.writeIDs(ctx, query, ids)
+}
+
+// ClearOrphanFlags skida oznaku sa korisnika koji su se ponovo pojavili.
+func (repo *Neo4jRepository) ClearOrphanFlags(ctx context.Context, ids []int64) (int64, error) {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		query := `
+            MATCH (u:User)
+            WHERE u.orphaned = true AND u.id IN $ids
+            REMOVE u.orphaned, u.orphanedAt
+            RETURN count(u) AS cleared
+        `
+		res, err := tx.Run(ctx, query, map[string]any{"ids": ids})
+		if err != nil {
+			return nil, err
+		}
+		record, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		cleared, _ := record.Get("cleared")
+		return cleared.(int64), nil
+	})
+
+	if err != nil {
+		log.Printf("Greška pri skidanju oznake orphaned: %v", err)
+		return 0, err
+	}
+	return result.(int64), nil
+}
+
+func (repo *Neo4jRepository) writeIDs(ctx context.Context, query string, ids []int64) error {
+	session := repo.driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		result, err := tx.Run(ctx, query, map[string]any{"ids": ids})
+		if err != nil {
+			return nil, err
+		}
+		return result.Consume(ctx)
+	})
+
+	if err != nil {
+		log.Printf("Greška pri grupnoj izmeni %d korisnika: %v", len(ids), err)
+	}
+	return err
+}
@@ -0,0 +1,127 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"follower-service/proto/followerpb"
+	"follower-service/service"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FollowerServer izlaže istu logiku kao HTTP handleri, preko gRPC-a.
+type FollowerServer struct {
+	followerpb.UnimplementedFollowerServiceServer
+	service *service.FollowerService
+}
+
+func NewFollowerServer(svc *service.FollowerService) *FollowerServer {
+	return &FollowerServer{service: svc}
+}
+
+func (s *FollowerServer) Follow(ctx context.Context, req *followerpb.FollowRequest) (*followerpb.FollowResponse, error) {
+	follower, err := s.service.Follow(ctx, req.GetFollowerId(), req.GetFollowedId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &followerpb.FollowResponse{
+		FollowerId: int64(follower.FollowerID),
+		FollowedId: int64(follower.FollowedUserID),
+		Status:     follower.Status,
+	}, nil
+}
+
+func (s *FollowerServer) Unfollow(ctx context.Context, req *followerpb.UnfollowRequest) (*followerpb.UnfollowResponse, error) {
+	removed, err := s.service.Unfollow(ctx, req.GetFollowerId(), req.GetFollowedId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &followerpb.UnfollowResponse{Removed: removed}, nil
+}
+
+func (s *FollowerServer) GetFollowers(ctx context.Context, req *followerpb.GetFollowersRequest) (*followerpb.UserListResponse, error) {
+	ids, err := s.service.GetFollowers(ctx, req.GetUserId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &followerpb.UserListResponse{UserIds: ids}, nil
+}
+
+func (s *FollowerServer) GetFollowing(ctx context.Context, req *followerpb.GetFollowingRequest) (*followerpb.UserListResponse, error) {
+	ids, err := s.service.GetFollowing(ctx, req.GetUserId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &followerpb.UserListResponse{UserIds: ids}, nil
+}
+
+func (s *FollowerServer) IsFollowing(ctx context.Context, req *followerpb.IsFollowingRequest) (*followerpb.IsFollowingResponse, error) {
+	following, err := s.service.IsFollowing(ctx, req.GetFollowerId(), req.GetFollowedId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &followerpb.IsFollowingResponse{Following: following}, nil
+}
+
+func (s *FollowerServer) GetRecommendations(ctx context.Context, req *followerpb.GetRecommendationsRequest) (*followerpb.GetRecommendationsResponse, error) {
+	recommendations, err := s.service.GetRecommendations(ctx, req.GetUserId(), int(req.GetLimit()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &followerpb.GetRecommendationsResponse{
+		Recommendations: make([]*followerpb.Recommendation, 0, len(recommendations)),
+	}
+	for _, rec := range recommendations {
+		response.Recommendations = append(response.Recommendations, &followerpb.Recommendation{
+			UserId:      rec.UserID,
+			MutualCount: rec.MutualCount,
+		})
+	}
+	return response, nil
+}
+
+func (s *FollowerServer) GetBlocked(ctx context.Context, req *followerpb.GetBlockedRequest) (*followerpb.UserListResponse, error) {
+	ids, err := s.service.GetBlocked(ctx, req.GetUserId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &followerpb.UserListResponse{UserIds: ids}, nil
+}
+
+func (s *FollowerServer) GetMuted(ctx context.Context, req *followerpb.GetMutedRequest) (*followerpb.UserListResponse, error) {
+	ids, err := s.service.GetMuted(ctx, req.GetUserId())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &followerpb.UserListResponse{UserIds: ids}, nil
+}
+
+// toStatus mapira domenske greške iz service paketa na gRPC kodove.
+// Ostale greške se loguju i klijentu se vraća samo codes.Internal.
+func toStatus(err error) error {
+	var domainErr *service.Error
+	if !errors.As(err, &domainErr) || domainErr.Kind == service.KindInternal {
+		log.Printf("gRPC greška: %v", err)
+		return status.Error(codes.Internal, "Došlo je do greške na serveru.")
+	}
+	if domainErr.Err != nil {
+		log.Printf("gRPC greška: %v", domainErr.Err)
+	}
+
+	switch domainErr.Kind {
+	case service.KindNotFound:
+		return status.Error(codes.NotFound, domainErr.Message)
+	case service.KindInvalid:
+		return status.Error(codes.InvalidArgument, domainErr.Message)
+	case service.KindConflict:
+		return status.Error(codes.AlreadyExists, domainErr.Message)
+	case service.KindForbidden:
+		return status.Error(codes.PermissionDenied, domainErr.Message)
+	default:
+		return status.Error(codes.Internal, domainErr.Message)
+	}
+}
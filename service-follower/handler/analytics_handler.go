@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// GetRelationship: GET /users/{userId}/relationship/{otherId}
+func (h *Handler) GetRelationship(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	userID, err1 := strconv.Atoi(vars["userId"])
+	otherID, err2 := strconv.Atoi(vars["otherId"])
+	if err1 != nil || err2 != nil {
+		writeError(w, r, errInvalidID)
+		return
+	}
+
+	rel, err := h.service.GetRelationship(r.Context(), int64(userID), int64(otherID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rel)
+}
+
+// GetFollowCounts: GET /users/{userId}/counts
+func (h *Handler) GetFollowCounts(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userParam(w, r)
+	if !ok {
+		return
+	}
+
+	counts, err := h.service.GetFollowCounts(r.Context(), int64(userID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, counts)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"follower-service/service"
+	"shared/stakeholders"
+)
+
+// Gateway posle validacije tokena dodaje ove header-e.
+const (
+	headerUsername = "X-Username"
+	headerUserRole = "X-User-Role"
+)
+
+var (
+	errAdminOnly     = service.Forbidden("auth.admin_only", "Samo administratori mogu pristupiti ovoj funkciji.")
+	errMissingCaller = service.Unauthorized("auth.missing_user", "Nedostaje korisnik (X-Username).")
+	errNotOwner      = service.Forbidden("auth.not_owner", "Možete menjati samo sopstvene veze.")
+)
+
+// UserDirectory prevodi korisničko ime iz X-Username u ID iz stakeholders
+// servisa, koji se koristi i u grafu; u produkciji stakeholders.Client.
+type UserDirectory interface {
+	UserByUsername(ctx context.Context, username string) (stakeholders.User, bool, error)
+}
+
+func isAdmin(r *http.Request) bool {
+	role := strings.ToUpper(r.Header.Get(headerUserRole))
+	return role == "ROLE_ADMIN" || role == "ADMIN"
+}
+
+// RequireAdmin propušta zahtev samo ako je gateway označio korisnika kao admina.
+func RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !isAdmin(r) {
+			writeError(w, r, errAdminOnly)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// requireSelf propušta zahtev samo ako je pozivalac korisnik userID ili
+// admin; inače odgovara 401 ili 403 i vraća false.
+func (h *Handler) requireSelf(w http.ResponseWriter, r *http.Request, userID int) bool {
+	if isAdmin(r) {
+		return true
+	}
+	username := r.Header.Get(headerUsername)
+	if username == "" {
+		writeError(w, r, errMissingCaller)
+		return false
+	}
+	caller, found, err := h.users.UserByUsername(r.Context(), username)
+	if err != nil {
+		writeError(w, r, err)
+		return false
+	}
+	if !found || caller.ID != int64(userID) {
+		writeError(w, r, errNotOwner)
+		return false
+	}
+	return true
+}
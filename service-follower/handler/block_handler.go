@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+)
+
+// BlockUser: POST /users/{userId}/blocks/{targetId}
+func (h *Handler) BlockUser(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := userTargetParams(w, r)
+	if !ok || !h.requireSelf(w, r, userID) {
+		return
+	}
+
+	relation, err := h.service.Block(r.Context(), int64(userID), int64(targetID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, relation)
+}
+
+// UnblockUser: DELETE /users/{userId}/blocks/{targetId}
+func (h *Handler) UnblockUser(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := userTargetParams(w, r)
+	if !ok || !h.requireSelf(w, r, userID) {
+		return
+	}
+
+	if err := h.service.Unblock(r.Context(), int64(userID), int64(targetID)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetBlocked: GET /users/{userId}/blocks
+func (h *Handler) GetBlocked(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userParam(w, r)
+	if !ok {
+		return
+	}
+
+	ids, err := h.service.GetBlocked(r.Context(), int64(userID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ids)
+}
+
+// MuteUser: POST /users/{userId}/mutes/{targetId}
+func (h *Handler) MuteUser(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := userTargetParams(w, r)
+	if !ok || !h.requireSelf(w, r, userID) {
+		return
+	}
+
+	relation, err := h.service.Mute(r.Context(), int64(userID), int64(targetID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, relation)
+}
+
+// UnmuteUser: DELETE /users/{userId}/mutes/{targetId}
+func (h *Handler) UnmuteUser(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := userTargetParams(w, r)
+	if !ok || !h.requireSelf(w, r, userID) {
+		return
+	}
+
+	if err := h.service.Unmute(r.Context(), int64(userID), int64(targetID)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetMuted: GET /users/{userId}/mutes, koristi ga blog servis za filtriranje feed-a.
+func (h *Handler) GetMuted(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userParam(w, r)
+	if !ok {
+		return
+	}
+
+	ids, err := h.service.GetMuted(r.Context(), int64(userID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ids)
+}
+
+func userTargetParams(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	vars := mux.Vars(r)
+
+	userID, err1 := strconv.Atoi(vars["userId"])
+	targetID, err2 := strconv.Atoi(vars["targetId"])
+
+	if err1 != nil || err2 != nil {
+		writeError(w, r, errInvalidID)
+		return 0, 0, false
+	}
+	return userID, targetID, true
+}
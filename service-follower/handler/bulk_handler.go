@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"mime"
+	"net/http"
+
+	"follower-service/bulk"
+	"follower-service/service"
+	"shared/validation"
+)
+
+// maxImportBytes ograničava veličinu fajla za uvoz preko HTTP-a;
+// za veće migracije koristi se CLI ("follower-service import").
+const maxImportBytes = 256 << 20
+
+var errUnknownFormat = service.Invalid("bulk.unknown_format", "Format mora biti ndjson ili csv.")
+
+// ImportGraph: POST /admin/graph/import?format=ndjson|csv
+func (h *Handler) ImportGraph(w http.ResponseWriter, r *http.Request) {
+	format := bulkFormat(r)
+	if !bulk.ValidFormat(format) {
+		writeError(w, r, errUnknownFormat)
+		return
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBytes)
+	report, err := bulk.Import(r.Context(), h.store, body, format)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			err = requestError(validation.ErrBodyTooLarge)
+		}
+		writeError(w, r, err)
+		return
+	}
+
+	log.Printf("Uvoz grafa završen: %d korisnika, %d veza, %d preskočeno, %d grešaka",
+		report.Users, report.Follows, report.FollowsSkipped, len(report.Errors))
+	writeJSON(w, http.StatusOK, report)
+}
+
+// ExportGraph: GET /admin/graph/export?format=ndjson|csv
+func (h *Handler) ExportGraph(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = bulk.FormatNDJSON
+	}
+	if !bulk.ValidFormat(format) {
+		writeError(w, r, errUnknownFormat)
+		return
+	}
+
+	if format == bulk.FormatCSV {
+		w.Header().Set("Content-Type", "text/csv")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="follower-graph.`+format+`"`)
+
+	// Odgovor se strimuje, pa posle prvog bajta više ne možemo da vratimo
+	// problem odgovor; greška završava samo u logu.
+	if err := bulk.Export(r.Context(), h.store, w, format); err != nil {
+		log.Printf("Greška pri izvozu grafa: %v", err)
+	}
+}
+
+// bulkFormat čita ?format=, a ako ga nema, zaključuje format iz Content-Type.
+func bulkFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format
+	}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "text/csv" {
+		return bulk.FormatCSV
+	}
+	return bulk.FormatNDJSON
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"follower-service/service"
+	"shared/problem"
+	"shared/validation"
+)
+
+var (
+	errInvalidID    = service.Invalid("request.invalid_id", "ID-jevi moraju biti brojevi.")
+	errInvalidLimit = service.Invalid("request.invalid_limit", "Limit mora biti broj.")
+)
+
+func errMalformedBody(err error) *service.Error {
+	return &service.Error{Kind: service.KindInvalid, Code: "request.malformed_body", Message: "Telo zahteva nije ispravan JSON.", Err: err}
+}
+
+// requestError prevodi greške iz validation paketa u domenske greške.
+func requestError(err error) *service.Error {
+	var fieldErrs validation.Errors
+	switch {
+	case errors.As(err, &fieldErrs):
+		return &service.Error{Kind: service.KindInvalid, Code: "request.validation_failed", Message: "Zahtev nije prošao validaciju.", Fields: fieldErrs}
+	case errors.Is(err, validation.ErrBodyTooLarge):
+		return &service.Error{Kind: service.KindPayloadTooLarge, Code: "request.body_too_large", Message: "Telo zahteva je preveliko."}
+	default:
+		return errMalformedBody(err)
+	}
+}
+
+func statusFor(kind service.ErrorKind) int {
+	switch kind {
+	case service.KindNotFound:
+		return http.StatusNotFound
+	case service.KindInvalid:
+		return http.StatusBadRequest
+	case service.KindConflict:
+		return http.StatusConflict
+	case service.KindForbidden:
+		return http.StatusForbidden
+	case service.KindUnauthorized:
+		return http.StatusUnauthorized
+	case service.KindPayloadTooLarge:
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeError je jedino mesto gde se greške prevode u HTTP odgovor.
+// Greške drajvera se loguju, a klijent dobija samo generičku poruku.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var domainErr *service.Error
+	if errors.As(err, &domainErr) && domainErr.Kind != service.KindInternal {
+		if domainErr.Err != nil {
+			log.Printf("%s %s: %v", r.Method, r.URL.Path, domainErr.Err)
+		}
+		p := problem.New(statusFor(domainErr.Kind), domainErr.Code, domainErr.Message)
+		p.Errors = domainErr.Fields
+		problem.Write(w, r, p)
+		return
+	}
+
+	log.Printf("Greška pri obradi %s %s: %v", r.Method, r.URL.Path, err)
+	problem.Write(w, r, problem.New(http.StatusInternalServerError, "internal_error", "Došlo je do greške na serveru."))
+}
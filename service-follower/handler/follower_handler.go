@@ -1,110 +1,223 @@
 package handler
 
 import (
-	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
 
-	"follower-service/db"
+	"follower-service/bulk"
 	"follower-service/model"
+	"follower-service/service"
+	"shared/validation"
 
 	"github.com/gorilla/mux"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 )
 
-// createFollowerLogic je JEDINA funkcija koja komunicira sa bazom.
-// Ona sadrži svu logiku za proveru i kreiranje veze.
-func createFollowerLogic(w http.ResponseWriter, followerID int, followedID int) {
-	// 1. Proveravamo da li oba korisnika zaista postoje u bazi
-	followerExists, err := db.UserExists(followerID)
+// maxBodyBytes ograničava veličinu JSON tela zahteva.
+const maxBodyBytes = 64 << 10
+
+type Handler struct {
+	service *service.FollowerService
+	store   bulk.Store
+	users   UserDirectory
+}
+
+// NewHandler prima servis, skladište za uvoz/izvoz grafa i imenik korisnika
+// za proveru pozivaoca; zavisnosti se prosleđuju spolja da bi testovi mogli
+// da koriste memorijski graf.
+func NewHandler(svc *service.FollowerService, store bulk.Store, users UserDirectory) *Handler {
+	return &Handler{service: svc, store: store, users: users}
+}
+
+// RegisterRoutes registruje sve rute osim /admin/reconcile, kojoj treba i reconciler.
+func (h *Handler) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/followers", h.CreateFollowerFromJSON).Methods("POST")
+	r.HandleFunc("/follow/{followedId}/{followerId}", h.CreateFollowerFromParams).Methods("POST")
+	r.HandleFunc("/follow/{followedId}/{followerId}", h.DeleteFollower).Methods("DELETE")
+	r.HandleFunc("/follow/{followedId}/{followerId}", h.IsFollowing).Methods("GET")
+	r.HandleFunc("/users/{userId}/followers", h.GetFollowers).Methods("GET")
+	r.HandleFunc("/users/{userId}/following", h.GetFollowing).Methods("GET")
+	r.HandleFunc("/users/{userId}/recommendations", h.GetRecommendations).Methods("GET")
+	r.HandleFunc("/users/{userId}/relationship/{otherId}", h.GetRelationship).Methods("GET")
+	r.HandleFunc("/users/{userId}/counts", h.GetFollowCounts).Methods("GET")
+	r.HandleFunc("/users/{userId}/blocks", h.GetBlocked).Methods("GET")
+	r.HandleFunc("/users/{userId}/blocks/{targetId}", h.BlockUser).Methods("POST")
+	r.HandleFunc("/users/{userId}/blocks/{targetId}", h.UnblockUser).Methods("DELETE")
+	r.HandleFunc("/users/{userId}/mutes", h.GetMuted).Methods("GET")
+	r.HandleFunc("/users/{userId}/mutes/{targetId}", h.MuteUser).Methods("POST")
+	r.HandleFunc("/users/{userId}/mutes/{targetId}", h.UnmuteUser).Methods("DELETE")
+	r.HandleFunc("/users/{userId}/privacy", h.GetPrivacy).Methods("GET")
+	r.HandleFunc("/users/{userId}/privacy", h.SetPrivacy).Methods("PUT")
+	r.HandleFunc("/users/{userId}/follow-requests", h.GetIncomingRequests).Methods("GET")
+	r.HandleFunc("/users/{userId}/follow-requests/{requesterId}/approve", h.ApproveFollowRequest).Methods("POST")
+	r.HandleFunc("/users/{userId}/follow-requests/{requesterId}/reject", h.RejectFollowRequest).Methods("POST")
+	r.HandleFunc("/users/{userId}/sent-requests", h.GetOutgoingRequests).Methods("GET")
+	r.HandleFunc("/users/{userId}/sent-requests/{targetId}", h.CancelFollowRequest).Methods("DELETE")
+
+	r.HandleFunc("/admin/graph/import", RequireAdmin(h.ImportGraph)).Methods("POST")
+	r.HandleFunc("/admin/graph/export", RequireAdmin(h.ExportGraph)).Methods("GET")
+}
+
+// createFollowerLogic prevodi rezultat zajedničke logike iz service paketa
+// (koju koristi i gRPC server) u HTTP odgovor.
+func (h *Handler) createFollowerLogic(w http.ResponseWriter, r *http.Request, followerID int, followedID int) {
+	response, err := h.service.Follow(r.Context(), int64(followerID), int64(followedID))
 	if err != nil {
-		http.Error(w, "Greška servera pri proveri korisnika (follower): "+err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
-	followedExists, err := db.UserExists(followedID)
+
+	// Zahtev ka privatnom nalogu je prihvaćen, ali veza još ne postoji
+	if response.Status == model.FollowStatusRequested {
+		writeJSON(w, http.StatusAccepted, response)
+		return
+	}
+	writeJSON(w, http.StatusCreated, response)
+}
+
+// CreateFollowerFromJSON samo dekodira JSON i poziva glavnu logiku.
+func (h *Handler) CreateFollowerFromJSON(w http.ResponseWriter, r *http.Request) {
+	var newFollower model.FollowRequest
+	if err := validation.DecodeJSON(w, r, &newFollower, maxBodyBytes); err != nil {
+		writeError(w, r, requestError(err))
+		return
+	}
+	if err := validation.Struct(newFollower); err != nil {
+		writeError(w, r, requestError(err))
+		return
+	}
+
+	// Pozivamo zajedničku, centralizovanu logiku
+	h.createFollowerLogic(w, r, newFollower.FollowerID, newFollower.FollowedUserID)
+}
+
+// CreateFollowerFromParams samo vadi parametre iz URL-a i poziva glavnu logiku.
+func (h *Handler) CreateFollowerFromParams(w http.ResponseWriter, r *http.Request) {
+	followerID, followedID, ok := followParams(w, r)
+	if !ok {
+		return
+	}
+
+	// Pozivamo zajedničku, centralizovanu logiku
+	h.createFollowerLogic(w, r, followerID, followedID)
+}
+
+// DeleteFollower uklanja FOLLOWS vezu zadatu parametrima iz URL-a.
+func (h *Handler) DeleteFollower(w http.ResponseWriter, r *http.Request) {
+	followerID, followedID, ok := followParams(w, r)
+	if !ok {
+		return
+	}
+
+	removed, err := h.service.Unfollow(r.Context(), int64(followerID), int64(followedID))
 	if err != nil {
-		http.Error(w, "Greška servera pri proveri korisnika (followed): "+err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
+	if !removed {
+		writeError(w, r, service.ErrFollowNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
 
-	// 2. Ako bilo koji od korisnika ne postoji, vraćamo grešku i prekidamo
-	if !followerExists {
-		http.Error(w, "Korisnik koji prati (follower) ne postoji.", http.StatusNotFound)
+// IsFollowing proverava da li follower prati followed korisnika.
+func (h *Handler) IsFollowing(w http.ResponseWriter, r *http.Request) {
+	followerID, followedID, ok := followParams(w, r)
+	if !ok {
 		return
 	}
-	if !followedExists {
-		http.Error(w, "Korisnik koji se prati (followed) ne postoji.", http.StatusNotFound)
+
+	following, err := h.service.IsFollowing(r.Context(), int64(followerID), int64(followedID))
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
-	
-	// 3. Tek ako oba korisnika postoje, krećemo sa upisom u bazu
-	ctx := context.Background()
-	session := db.Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
-	defer session.Close(ctx)
 
-	_, err = session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
-		// Koristimo MATCH za korisnike (jer smo već proverili da postoje)
-		// i MERGE samo za vezu (da sprečimo duplikate).
-		query := `
-            MATCH (follower:User {id: $followerId})
-            MATCH (followed:User {id: $followedId})
-            MERGE (follower)-[:FOLLOWS]->(followed)
-        `
-		params := map[string]any{
-			"followerId": followerID,
-			"followedId": followedID,
-		}
+	writeJSON(w, http.StatusOK, map[string]bool{"following": following})
+}
 
-		result, err := tx.Run(ctx, query, params)
-		if err != nil {
-			return nil, err
-		}
-		
-		return result.Consume(ctx)
-	})
+func (h *Handler) GetFollowers(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userParam(w, r)
+	if !ok {
+		return
+	}
 
+	ids, err := h.service.GetFollowers(r.Context(), int64(userID))
 	if err != nil {
-		http.Error(w, "Greška prilikom upisa veze u bazu: "+err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ids)
+}
+
+func (h *Handler) GetFollowing(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userParam(w, r)
+	if !ok {
 		return
 	}
 
-	// Ako je sve prošlo kako treba, šaljemo uspešan odgovor
-	response := model.Follower{
-		FollowerID:     followerID,
-		FollowedUserID: followedID,
+	ids, err := h.service.GetFollowing(r.Context(), int64(userID))
+	if err != nil {
+		writeError(w, r, err)
+		return
 	}
-	
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(response)
+
+	writeJSON(w, http.StatusOK, ids)
 }
 
-// CreateFollowerFromJSON samo dekodira JSON i poziva glavnu logiku.
-func CreateFollowerFromJSON(w http.ResponseWriter, r *http.Request) {
-	var newFollower model.Follower
-	err := json.NewDecoder(r.Body).Decode(&newFollower)
+// GetRecommendations vraća predloge za praćenje; ?limit= je opcion.
+func (h *Handler) GetRecommendations(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userParam(w, r)
+	if !ok {
+		return
+	}
+
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			writeError(w, r, errInvalidLimit)
+			return
+		}
+		limit = parsed
+	}
+
+	recommendations, err := h.service.GetRecommendations(r.Context(), int64(userID), limit)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
-	
-	// Pozivamo zajedničku, centralizovanu logiku
-	createFollowerLogic(w, newFollower.FollowerID, newFollower.FollowedUserID)
+
+	writeJSON(w, http.StatusOK, recommendations)
 }
 
-// CreateFollowerFromParams samo vadi parametre iz URL-a i poziva glavnu logiku.
-func CreateFollowerFromParams(w http.ResponseWriter, r *http.Request) {
+func followParams(w http.ResponseWriter, r *http.Request) (int, int, bool) {
 	vars := mux.Vars(r)
 
 	followedID, err1 := strconv.Atoi(vars["followedId"])
 	followerID, err2 := strconv.Atoi(vars["followerId"])
 
 	if err1 != nil || err2 != nil {
-		http.Error(w, "ID-jevi moraju biti brojevi.", http.StatusBadRequest)
-		return
+		writeError(w, r, errInvalidID)
+		return 0, 0, false
 	}
+	return followerID, followedID, true
+}
 
-	// Pozivamo zajedničku, centralizovanu logiku
-	createFollowerLogic(w, followerID, followedID)
-}
\ No newline at end of file
+func userParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	userID, err := strconv.Atoi(mux.Vars(r)["userId"])
+	if err != nil {
+		writeError(w, r, errInvalidID)
+		return 0, false
+	}
+	return userID, true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"follower-service/db"
+	"follower-service/service"
+	"shared/problem"
+	"shared/stakeholders"
+
+	"github.com/gorilla/mux"
+)
+
+var _ service.GraphRepository = (*db.MemoryRepository)(nil)
+
+// seedGraph: korisnici 1-5, 1 prati 2, 2 prati 3, 4 je privatan, 5 je blokirao 1.
+func seedGraph(t *testing.T) *db.MemoryRepository {
+	t.Helper()
+	ctx := context.Background()
+	repo := db.NewMemoryRepository()
+	private := true
+	if err := repo.MergeUsers(ctx, []db.UserRecord{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4, Private: &private}, {ID: 5}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.MergeFollows(ctx, []db.FollowRecord{{FollowerID: 1, FollowedID: 2}, {FollowerID: 2, FollowedID: 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.CreateBlock(ctx, 5, 1); err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+// testUsers: korisničko ime "korisnikN" ima ID N u stakeholders servisu.
+type testUsers struct{}
+
+func (testUsers) UserByUsername(ctx context.Context, username string) (stakeholders.User, bool, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(username, "korisnik"), 10, 64)
+	if err != nil {
+		return stakeholders.User{}, false, nil
+	}
+	return stakeholders.User{ID: id, Username: username}, true, nil
+}
+
+func newTestRouter(repo *db.MemoryRepository) *mux.Router {
+	r := mux.NewRouter()
+	NewHandler(service.NewFollowerService(repo, nil), repo, testUsers{}).RegisterRoutes(r)
+	return r
+}
+
+func TestHandlers(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		headers    map[string]string
+		wantStatus int
+		wantCode   string
+		wantBody   string
+	}{
+		{name: "follow iz JSON-a", method: http.MethodPost, path: "/followers", body: `{"followerId":3,"followedUserId":1}`, wantStatus: http.StatusCreated},
+		{name: "follow iz URL-a", method: http.MethodPost, path: "/follow/1/3", wantStatus: http.StatusCreated},
+		{name: "follow privatnog naloga je zahtev", method: http.MethodPost, path: "/follow/4/1", wantStatus: http.StatusAccepted},
+		{name: "samopraćenje", method: http.MethodPost, path: "/follow/1/1", wantStatus: http.StatusBadRequest, wantCode: "follow.self_follow"},
+		{name: "nepostojeći korisnik", method: http.MethodPost, path: "/follow/99/1", wantStatus: http.StatusNotFound},
+		{name: "blokiran korisnik", method: http.MethodPost, path: "/follow/5/1", wantStatus: http.StatusForbidden, wantCode: "follow.blocked"},
+		{name: "neispravan ID", method: http.MethodPost, path: "/follow/abc/1", wantStatus: http.StatusBadRequest, wantCode: "request.invalid_id"},
+		{name: "nepoznato polje u telu", method: http.MethodPost, path: "/followers", body: `{"followerId":1,"followedUserId":2,"x":1}`, wantStatus: http.StatusBadRequest, wantCode: "request.validation_failed"},
+		{name: "neispravan JSON", method: http.MethodPost, path: "/followers", body: `{`, wantStatus: http.StatusBadRequest, wantCode: "request.malformed_body"},
+		{name: "unfollow", method: http.MethodDelete, path: "/follow/2/1", wantStatus: http.StatusNoContent},
+		{name: "unfollow nepostojeće veze", method: http.MethodDelete, path: "/follow/1/2", wantStatus: http.StatusNotFound},
+		{name: "da li prati", method: http.MethodGet, path: "/follow/2/1", wantStatus: http.StatusOK, wantBody: `{"following":true}`},
+		{name: "pratioci", method: http.MethodGet, path: "/users/2/followers", wantStatus: http.StatusOK, wantBody: `[1]`},
+		{name: "pratioci nepostojećeg korisnika", method: http.MethodGet, path: "/users/99/followers", wantStatus: http.StatusNotFound, wantCode: "user.not_found"},
+		{name: "preporuke", method: http.MethodGet, path: "/users/1/recommendations", wantStatus: http.StatusOK, wantBody: `[{"userId":3,"mutualCount":1}]`},
+		{name: "neispravan limit", method: http.MethodGet, path: "/users/1/recommendations?limit=x", wantStatus: http.StatusBadRequest, wantCode: "request.invalid_limit"},
+		{name: "brojevi praćenja", method: http.MethodGet, path: "/users/2/counts", wantStatus: http.StatusOK, wantBody: `{"userId":2,"followers":1,"following":1}`},
+		{name: "blokiranje", method: http.MethodPost, path: "/users/1/blocks/3", headers: map[string]string{headerUsername: "korisnik1"}, wantStatus: http.StatusCreated},
+		{name: "samoblokiranje", method: http.MethodPost, path: "/users/1/blocks/1", headers: map[string]string{headerUsername: "korisnik1"}, wantStatus: http.StatusBadRequest, wantCode: "block.self_block"},
+		{name: "blokiranje bez korisnika", method: http.MethodPost, path: "/users/1/blocks/3", wantStatus: http.StatusUnauthorized, wantCode: "auth.missing_user"},
+		{name: "tuđa blokada se ne uklanja", method: http.MethodDelete, path: "/users/5/blocks/1", headers: map[string]string{headerUsername: "korisnik1"}, wantStatus: http.StatusForbidden, wantCode: "auth.not_owner"},
+		{name: "tuđe utišavanje", method: http.MethodPost, path: "/users/2/mutes/3", headers: map[string]string{headerUsername: "korisnik1"}, wantStatus: http.StatusForbidden, wantCode: "auth.not_owner"},
+		{name: "admin uklanja blokadu", method: http.MethodDelete, path: "/users/5/blocks/1", headers: map[string]string{headerUserRole: "ROLE_ADMIN"}, wantStatus: http.StatusNoContent},
+		{name: "privatnost tuđeg naloga", method: http.MethodPut, path: "/users/4/privacy", body: `{"private":false}`, headers: map[string]string{headerUsername: "korisnik1"}, wantStatus: http.StatusForbidden, wantCode: "auth.not_owner"},
+		{name: "privatnost", method: http.MethodPut, path: "/users/4/privacy", body: `{"private":false}`, headers: map[string]string{headerUsername: "korisnik4"}, wantStatus: http.StatusOK},
+		{name: "tuđi zahtev se ne povlači", method: http.MethodDelete, path: "/users/1/sent-requests/4", headers: map[string]string{headerUsername: "korisnik4"}, wantStatus: http.StatusForbidden, wantCode: "auth.not_owner"},
+		{name: "izvoz bez admin uloge", method: http.MethodGet, path: "/admin/graph/export", wantStatus: http.StatusForbidden, wantCode: "auth.admin_only"},
+		{name: "izvoz", method: http.MethodGet, path: "/admin/graph/export?format=csv", headers: map[string]string{headerUserRole: "ROLE_ADMIN"}, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(seedGraph(t))
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, očekivano %d; telo: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantCode != "" {
+				var p problem.Problem
+				if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+					t.Fatalf("odgovor nije problem+json: %v", err)
+				}
+				if p.Code != tt.wantCode {
+					t.Errorf("code = %q, očekivano %q", p.Code, tt.wantCode)
+				}
+			}
+			if tt.wantBody != "" && strings.TrimSpace(rec.Body.String()) != tt.wantBody {
+				t.Errorf("telo = %s, očekivano %s", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestApproveFollowRequestCreatesFollow(t *testing.T) {
+	router := newTestRouter(seedGraph(t))
+
+	steps := []struct {
+		method     string
+		path       string
+		user       string
+		wantStatus int
+	}{
+		{http.MethodPost, "/follow/4/1", "korisnik1", http.StatusAccepted},
+		{http.MethodPost, "/users/4/follow-requests/1/approve", "korisnik1", http.StatusForbidden},
+		{http.MethodPost, "/users/4/follow-requests/1/approve", "korisnik4", http.StatusOK},
+		{http.MethodPost, "/users/4/follow-requests/1/approve", "korisnik4", http.StatusNotFound},
+		{http.MethodGet, "/users/4/followers", "", http.StatusOK},
+	}
+	for _, step := range steps {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(step.method, step.path, nil)
+		req.Header.Set(headerUsername, step.user)
+		router.ServeHTTP(rec, req)
+		if rec.Code != step.wantStatus {
+			t.Fatalf("%s %s: status = %d, očekivano %d", step.method, step.path, rec.Code, step.wantStatus)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/4/followers", nil))
+	if body := strings.TrimSpace(rec.Body.String()); body != "[1]" {
+		t.Errorf("pratioci = %s, očekivano [1]", body)
+	}
+}
+
+func TestImportWithoutPrivateKeepsPrivacy(t *testing.T) {
+	router := newTestRouter(seedGraph(t))
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/graph/import?format=ndjson", strings.NewReader(`{"type":"user","id":4}`+"\n"))
+	req.Header.Set(headerUserRole, "ROLE_ADMIN")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("uvoz: status = %d; telo: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/4/privacy", nil))
+	if body := strings.TrimSpace(rec.Body.String()); body != `{"private":true}` {
+		t.Errorf("privatnost = %s, očekivano {\"private\":true}", body)
+	}
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"follower-service/reconcile"
+	"follower-service/service"
+)
+
+var (
+	errReconcileRunning     = service.Conflict("reconcile.already_running", "Usklađivanje je već u toku.")
+	errReconcileEmptySource = service.Conflict("reconcile.empty_source", "Stakeholders je vratio praznu listu korisnika; graf nije menjan.")
+)
+
+// Reconcile: POST /admin/reconcile?dryRun=true&orphans=flag|delete
+func Reconcile(rc *reconcile.Reconciler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		opts := reconcile.Options{
+			Orphans: query.Get("orphans"),
+			DryRun:  query.Get("dryRun") == "true",
+		}
+
+		report, err := rc.Run(r.Context(), opts)
+		if err != nil {
+			switch {
+			case errors.Is(err, reconcile.ErrAlreadyRunning):
+				writeError(w, r, errReconcileRunning)
+			case errors.Is(err, reconcile.ErrEmptySource):
+				writeError(w, r, errReconcileEmptySource)
+			default:
+				writeError(w, r, err)
+			}
+			return
+		}
+
+		writeJSON(w, http.StatusOK, report)
+	}
+}
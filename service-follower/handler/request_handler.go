@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"follower-service/model"
+	"shared/validation"
+
+	"github.com/gorilla/mux"
+)
+
+// SetPrivacy: PUT /users/{userId}/privacy
+func (h *Handler) SetPrivacy(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userParam(w, r)
+	if !ok || !h.requireSelf(w, r, userID) {
+		return
+	}
+
+	var settings model.PrivacySettings
+	if err := validation.DecodeJSON(w, r, &settings, maxBodyBytes); err != nil {
+		writeError(w, r, requestError(err))
+		return
+	}
+	if err := validation.Struct(settings); err != nil {
+		writeError(w, r, requestError(err))
+		return
+	}
+
+	if err := h.service.SetPrivate(r.Context(), int64(userID), *settings.Private); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// GetPrivacy: GET /users/{userId}/privacy
+func (h *Handler) GetPrivacy(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userParam(w, r)
+	if !ok {
+		return
+	}
+
+	private, err := h.service.IsPrivate(r.Context(), int64(userID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, model.PrivacySettings{Private: &private})
+}
+
+// GetIncomingRequests: GET /users/{userId}/follow-requests
+func (h *Handler) GetIncomingRequests(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userParam(w, r)
+	if !ok {
+		return
+	}
+
+	requests, err := h.service.GetIncomingRequests(r.Context(), int64(userID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, requests)
+}
+
+// GetOutgoingRequests: GET /users/{userId}/sent-requests
+func (h *Handler) GetOutgoingRequests(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userParam(w, r)
+	if !ok {
+		return
+	}
+
+	requests, err := h.service.GetOutgoingRequests(r.Context(), int64(userID))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, requests)
+}
+
+// ApproveFollowRequest: POST /users/{userId}/follow-requests/{requesterId}/approve
+func (h *Handler) ApproveFollowRequest(w http.ResponseWriter, r *http.Request) {
+	userID, requesterID, ok := requestParams(w, r)
+	if !ok || !h.requireSelf(w, r, userID) {
+		return
+	}
+
+	if err := h.service.ApproveFollowRequest(r.Context(), int64(userID), int64(requesterID)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, model.Follower{
+		FollowerID:     requesterID,
+		FollowedUserID: userID,
+		Status:         model.FollowStatusFollowing,
+	})
+}
+
+// RejectFollowRequest: POST /users/{userId}/follow-requests/{requesterId}/reject
+func (h *Handler) RejectFollowRequest(w http.ResponseWriter, r *http.Request) {
+	userID, requesterID, ok := requestParams(w, r)
+	if !ok || !h.requireSelf(w, r, userID) {
+		return
+	}
+
+	if err := h.service.RejectFollowRequest(r.Context(), int64(userID), int64(requesterID)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CancelFollowRequest: DELETE /users/{userId}/sent-requests/{targetId};
+// userId je korisnik koji je poslao zahtev.
+func (h *Handler) CancelFollowRequest(w http.ResponseWriter, r *http.Request) {
+	userID, targetID, ok := userTargetParams(w, r)
+	if !ok || !h.requireSelf(w, r, userID) {
+		return
+	}
+
+	if err := h.service.CancelFollowRequest(r.Context(), int64(userID), int64(targetID)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func requestParams(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	vars := mux.Vars(r)
+
+	userID, err1 := strconv.Atoi(vars["userId"])
+	requesterID, err2 := strconv.Atoi(vars["requesterId"])
+
+	if err1 != nil || err2 != nil {
+		writeError(w, r, errInvalidID)
+		return 0, 0, false
+	}
+	return userID, requesterID, true
+}
@@ -0,0 +1,264 @@
+// Package migrations izvršava verzionisane Cypher migracije nad Neo4j bazom.
+// Primenjene verzije se čuvaju kao (:SchemaMigration) čvorovi, a
+// (:MigrationLock) čvor sprečava da više replika migrira istovremeno.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"follower-service/db"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Migration je jedan korak šeme. Naredbe se izvršavaju redom, svaka u svojoj
+// transakciji (Neo4j ne dozvoljava izmene šeme i podataka u istoj), pa
+// moraju biti idempotentne za slučaj da migracija pukne na pola.
+type Migration struct {
+	Version     int
+	Description string
+	Statements  []string
+}
+
+// relationshipTypes su sve veze između :User čvorova koje servis koristi.
+var relationshipTypes = []string{"FOLLOWS", "BLOCKS", "MUTES", "REQUESTED"}
+
+var all = []Migration{
+	{
+		Version:     1,
+		Description: "spajanje dupliranih :User čvorova",
+		Statements:  dedupeUsersStatements(),
+	},
+	{
+		Version:     2,
+		Description: "jedinstven :User(id)",
+		Statements: []string{
+			"CREATE CONSTRAINT user_id_unique IF NOT EXISTS FOR (u:User) REQUIRE u.id IS UNIQUE",
+		},
+	},
+	{
+		Version:     3,
+		Description: "indeks za :User(orphaned)",
+		Statements: []string{
+			"CREATE INDEX user_orphaned IF NOT EXISTS FOR (u:User) ON (u.orphaned)",
+		},
+	},
+}
+
+const (
+	lockTimeout = 2 * time.Minute
+	lockPoll    = 2 * time.Second
+	// staleLockAfter oslobađa zaključavanje replike koja je pala usred migracije.
+	staleLockAfter = 10 * time.Minute
+)
+
+// Run primenjuje sve migracije koje još nisu upisane u graf.
+func Run(ctx context.Context) error {
+	if err := bootstrap(ctx); err != nil {
+		return err
+	}
+
+	owner := lockOwner()
+	if err := acquireLock(ctx, owner); err != nil {
+		return err
+	}
+	defer releaseLock(context.Background(), owner)
+
+	// Verzije čitamo tek pod zaključavanjem, jer ih je druga replika
+	// možda upravo primenila.
+	applied, err := appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range all {
+		if applied[m.Version] {
+			continue
+		}
+
+		log.Printf("Primena Neo4j migracije %d: %s", m.Version, m.Description)
+		for _, stmt := range m.Statements {
+			if err := run(ctx, stmt, nil); err != nil {
+				return fmt.Errorf("migracija %d: %w", m.Version, err)
+			}
+		}
+		if err := recordVersion(ctx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dedupeUsersStatements() []string {
+	// Čvorove sortiramo po elementId da bi svaka naredba zadržala isti čvor.
+	const duplicates = `
+        MATCH (u:User)
+        WITH u ORDER BY elementId(u)
+        WITH u.id AS id, collect(u) AS nodes
+        WHERE size(nodes) > 1
+        WITH head(nodes) AS keep, tail(nodes) AS dups
+        UNWIND dups AS dup
+    `
+
+	var stmts []string
+	for _, rel := range relationshipTypes {
+		stmts = append(stmts,
+			duplicates+`
+        MATCH (dup)-[:`+rel+`]->(other:User)
+        WHERE other <> keep
+        MERGE (keep)-[:`+rel+`]->(other)`,
+			duplicates+`
+        MATCH (other:User)-[:`+rel+`]->(dup)
+        WHERE other <> keep
+        MERGE (other)-[:`+rel+`]->(keep)`,
+		)
+	}
+	return append(stmts, duplicates+`
+        DETACH DELETE dup`)
+}
+
+func bootstrap(ctx context.Context) error {
+	stmts := []string{
+		"CREATE CONSTRAINT migration_lock_name IF NOT EXISTS FOR (l:MigrationLock) REQUIRE l.name IS UNIQUE",
+		"CREATE CONSTRAINT schema_migration_version IF NOT EXISTS FOR (m:SchemaMigration) REQUIRE m.version IS UNIQUE",
+	}
+	for _, stmt := range stmts {
+		if err := run(ctx, stmt, nil); err != nil {
+			return fmt.Errorf("priprema migracija: %w", err)
+		}
+	}
+	return nil
+}
+
+func acquireLock(ctx context.Context, owner string) error {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		acquired, err := tryLock(ctx, owner)
+		if err != nil {
+			return err
+		}
+		if acquired {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("nije moguće dobiti zaključavanje migracija u roku od %s", lockTimeout)
+		}
+
+		log.Printf("Migracije izvršava druga replika, čekam...")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockPoll):
+		}
+	}
+}
+
+func tryLock(ctx context.Context, owner string) (bool, error) {
+	session := db.Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		// SET touchedAt uzima write lock nad čvorom, pa sledeća provera
+		// vidi poslednje potvrđeno stanje i dve replike ne mogu obe da prođu.
+		query := `
+            MERGE (l:MigrationLock {name: 'schema'})
+            SET l.touchedAt = datetime()
+            WITH l
+            WHERE l.lockedBy IS NULL
+               OR l.lockedBy = $owner
+               OR l.lockedAt < datetime() - duration({seconds: $staleSeconds})
+            SET l.lockedBy = $owner, l.lockedAt = datetime()
+            RETURN count(l) > 0 AS acquired
+        `
+		params := map[string]any{
+			"owner":        owner,
+			"staleSeconds": int64(staleLockAfter.Seconds()),
+		}
+
+		res, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		record, err := res.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		acquired, _ := record.Get("acquired")
+		return acquired.(bool), nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return result.(bool), nil
+}
+
+func releaseLock(ctx context.Context, owner string) {
+	query := `
+        MATCH (l:MigrationLock {name: 'schema', lockedBy: $owner})
+        REMOVE l.lockedBy, l.lockedAt
+    `
+	if err := run(ctx, query, map[string]any{"owner": owner}); err != nil {
+		log.Printf("Greška pri oslobađanju zaključavanja migracija: %v", err)
+	}
+}
+
+func appliedVersions(ctx context.Context) (map[int]bool, error) {
+	session := db.Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close(ctx)
+
+	result, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		res, err := tx.Run(ctx, "MATCH (m:SchemaMigration) RETURN m.version AS version", nil)
+		if err != nil {
+			return nil, err
+		}
+		records, err := res.Collect(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		applied := make(map[int]bool, len(records))
+		for _, record := range records {
+			version, _ := record.Get("version")
+			applied[int(version.(int64))] = true
+		}
+		return applied, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(map[int]bool), nil
+}
+
+func recordVersion(ctx context.Context, m Migration) error {
+	query := `
+        MERGE (m:SchemaMigration {version: $version})
+        ON CREATE SET m.description = $description, m.appliedAt = datetime()
+    `
+	return run(ctx, query, map[string]any{
+		"version":     m.Version,
+		"description": m.Description,
+	})
+}
+
+func run(ctx context.Context, query string, params map[string]any) error {
+	session := db.Driver.NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite})
+	defer session.Close(ctx)
+
+	_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		result, err := tx.Run(ctx, query, params)
+		if err != nil {
+			return nil, err
+		}
+		return result.Consume(ctx)
+	})
+	return err
+}
+
+func lockOwner() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d-%d", host, os.Getpid(), time.Now().UnixNano())
+}
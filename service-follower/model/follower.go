@@ -1,6 +1,58 @@
 package model
 
+import "time"
+
+const (
+	FollowStatusFollowing = "following"
+	FollowStatusRequested = "requested"
+)
+
+// Follower je rezultat praćenja; Status je "requested" kada je nalog privatan
+// i veza čeka odobrenje.
 type Follower struct {
-	FollowedUserID int `json:"followedUserId"`
-	FollowerID     int `json:"followerId"`
-}
\ No newline at end of file
+	FollowedUserID int    `json:"followedUserId"`
+	FollowerID     int    `json:"followerId"`
+	Status         string `json:"status"`
+}
+
+// FollowRequest je telo za POST /followers.
+type FollowRequest struct {
+	FollowedUserID int `json:"followedUserId" validate:"required,gt=0,nefield=FollowerID"`
+	FollowerID     int `json:"followerId" validate:"required,gt=0"`
+}
+
+// UserRelation opisuje usmerenu BLOCKS ili MUTES vezu.
+type UserRelation struct {
+	UserID   int `json:"userId"`
+	TargetID int `json:"targetId"`
+}
+
+// PendingFollowRequest je zahtev za praćenje privatnog naloga.
+type PendingFollowRequest struct {
+	RequesterID int       `json:"requesterId"`
+	TargetID    int       `json:"targetId"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// PrivacySettings je telo za PUT /users/{userId}/privacy.
+type PrivacySettings struct {
+	Private *bool `json:"private" validate:"required"`
+}
+
+// Relationship je odgovor na GET /users/{userId}/relationship/{otherId}.
+// Distance je nil kada ne postoji FOLLOWS put u okviru ograničenja.
+type Relationship struct {
+	UserID       int   `json:"userId"`
+	OtherUserID  int   `json:"otherUserId"`
+	Follows      bool  `json:"follows"`
+	FollowedBy   bool  `json:"followedBy"`
+	MutualCount  int   `json:"mutualCount"`
+	MutualSample []int `json:"mutualSample"`
+	Distance     *int  `json:"distance"`
+}
+
+type FollowCounts struct {
+	UserID    int `json:"userId"`
+	Followers int `json:"followers"`
+	Following int `json:"following"`
+}
@@ -0,0 +1,189 @@
+// Package reconcile usklađuje :User čvorove u Neo4j sa korisnicima iz
+// stakeholders servisa, za slučaj da consumer propusti user.created ili
+// user.deleted događaj.
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"follower-service/db"
+)
+
+const (
+	OrphansFlag   = "flag"
+	OrphansDelete = "delete"
+)
+
+// ErrEmptySource štiti graf od brisanja kada stakeholders vrati praznu listu,
+// što je skoro uvek greška u konfiguraciji, a ne stvarno stanje.
+var ErrEmptySource = errors.New("stakeholders je vratio praznu listu korisnika, usklađivanje je prekinuto")
+
+// ErrAlreadyRunning se vraća kada je usklađivanje već u toku.
+var ErrAlreadyRunning = errors.New("usklađivanje je već u toku")
+
+type Options struct {
+	// Orphans je "flag" (podrazumevano) ili "delete".
+	Orphans string
+	// DryRun samo računa razlike, bez izmena u grafu.
+	DryRun bool
+}
+
+type Report struct {
+	StartedAt    time.Time `json:"startedAt"`
+	Duration     string    `json:"duration"`
+	DryRun       bool      `json:"dryRun"`
+	OrphanMode   string    `json:"orphanMode"`
+	SourceUsers  int       `json:"sourceUsers"`
+	GraphUsers   int       `json:"graphUsers"`
+	Created      []int64   `json:"created"`
+	Orphans      []int64   `json:"orphans"`
+	FlagsCleared int64     `json:"flagsCleared"`
+}
+
+// Graph je deo grafa koji usklađivanje menja; implementira ga db.Neo4jRepository.
+type Graph interface {
+	GetAllUserIDs(ctx context.Context) ([]int64, error)
+	MergeUsers(ctx context.Context, users []db.UserRecord) error
+	DeleteUsers(ctx context.Context, ids []int64) error
+	FlagOrphans(ctx context.Context, ids []int64) error
+	ClearOrphanFlags(ctx context.Context, ids []int64) (int64, error)
+}
+
+type Reconciler struct {
+	source *StakeholdersClient
+	graph  Graph
+	mu     sync.Mutex
+}
+
+func NewReconciler(source *StakeholdersClient, graph Graph) *Reconciler {
+	return &Reconciler{source: source, graph: graph}
+}
+
+// Run izvršava jedno usklađivanje. Istovremeno sme da radi samo jedno.
+func (rc *Reconciler) Run(ctx context.Context, opts Options) (Report, error) {
+	if !rc.mu.TryLock() {
+		return Report{}, ErrAlreadyRunning
+	}
+	defer rc.mu.Unlock()
+
+	if opts.Orphans != OrphansDelete {
+		opts.Orphans = OrphansFlag
+	}
+	report := Report{
+		StartedAt:  time.Now(),
+		DryRun:     opts.DryRun,
+		OrphanMode: opts.Orphans,
+		Created:    []int64{},
+		Orphans:    []int64{},
+	}
+
+	sourceIDs, err := rc.source.FetchUserIDs(ctx)
+	if err != nil {
+		return report, err
+	}
+	graphIDs, err := rc.graph.GetAllUserIDs(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.SourceUsers = len(sourceIDs)
+	report.GraphUsers = len(graphIDs)
+
+	if len(sourceIDs) == 0 && len(graphIDs) > 0 {
+		return report, ErrEmptySource
+	}
+
+	report.Created, report.Orphans = diff(sourceIDs, graphIDs)
+
+	if !opts.DryRun {
+		if err := rc.apply(ctx, opts, sourceIDs, &report); err != nil {
+			return report, err
+		}
+	}
+
+	report.Duration = time.Since(report.StartedAt).Round(time.Millisecond).String()
+	log.Printf("Usklađivanje korisnika: %d kreirano, %d orphan (%s), dryRun=%t",
+		len(report.Created), len(report.Orphans), report.OrphanMode, report.DryRun)
+	return report, nil
+}
+
+func (rc *Reconciler) apply(ctx context.Context, opts Options, sourceIDs []int64, report *Report) error {
+	if len(report.Created) > 0 {
+		users := make([]db.UserRecord, 0, len(report.Created))
+		for _, id := range report.Created {
+			users = append(users, db.UserRecord{ID: id})
+		}
+		if err := rc.graph.MergeUsers(ctx, users); err != nil {
+			return err
+		}
+	}
+
+	if len(report.Orphans) > 0 {
+		var err error
+		if opts.Orphans == OrphansDelete {
+			err = rc.graph.DeleteUsers(ctx, report.Orphans)
+		} else {
+			err = rc.graph.FlagOrphans(ctx, report.Orphans)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	cleared, err := rc.graph.ClearOrphanFlags(ctx, sourceIDs)
+	if err != nil {
+		return err
+	}
+	report.FlagsCleared = cleared
+	return nil
+}
+
+// diff vraća ID-jeve koji fale u grafu i one koji postoje samo u grafu.
+func diff(sourceIDs, graphIDs []int64) (missing, orphans []int64) {
+	inSource := make(map[int64]bool, len(sourceIDs))
+	for _, id := range sourceIDs {
+		inSource[id] = true
+	}
+	inGraph := make(map[int64]bool, len(graphIDs))
+	for _, id := range graphIDs {
+		inGraph[id] = true
+	}
+
+	missing = []int64{}
+	for id := range inSource {
+		if !inGraph[id] {
+			missing = append(missing, id)
+		}
+	}
+	orphans = []int64{}
+	for id := range inGraph {
+		if !inSource[id] {
+			orphans = append(orphans, id)
+		}
+	}
+
+	sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i] < orphans[j] })
+	return missing, orphans
+}
+
+// StartScheduler pokreće usklađivanje na svakih interval dok se ctx ne otkaže.
+func (rc *Reconciler) StartScheduler(ctx context.Context, interval time.Duration, opts Options) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := rc.Run(ctx, opts); err != nil {
+				log.Printf("Greška pri periodičnom usklađivanju korisnika: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package reconcile
+
+import (
+	"context"
+	"time"
+
+	"shared/stakeholders"
+)
+
+// serviceName je identitet koji follower šalje service-stakeholders.
+const serviceName = "follower-service"
+
+// StakeholdersClient čita autoritativnu listu korisnika iz stakeholders servisa.
+type StakeholdersClient struct {
+	client *stakeholders.Client
+}
+
+func NewStakeholdersClient(baseURL string) *StakeholdersClient {
+	return &StakeholdersClient{
+		client: stakeholders.NewClient(baseURL, serviceName, 30*time.Second),
+	}
+}
+
+// FetchUserIDs vraća ID-jeve svih korisnika. Stakeholders deli korisnike na
+// aktivne i blokirane, pa spajamo obe liste; blokirani i dalje imaju čvor.
+func (c *StakeholdersClient) FetchUserIDs(ctx context.Context) ([]int64, error) {
+	var ids []int64
+	for _, fetch := range []func(context.Context) ([]stakeholders.User, error){
+		c.client.ActiveUsers,
+		c.client.BlockedUsers,
+	} {
+		users, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+
+	"follower-service/model"
+)
+
+const (
+	// MutualSampleSize je broj zajedničkih korisnika koje vraćamo imenom.
+	MutualSampleSize = 3
+	// MaxFollowDistance ograničava dubinu pretrage najkraćeg puta.
+	MaxFollowDistance = 6
+)
+
+var ErrSelfRelationship = Invalid("relationship.self", "Odnos se računa između dva različita korisnika.")
+
+func (s *FollowerService) GetRelationship(ctx context.Context, userID, otherID int64) (model.Relationship, error) {
+	if userID == otherID {
+		return model.Relationship{}, ErrSelfRelationship
+	}
+	if err := s.ensureBothExist(ctx, userID, otherID); err != nil {
+		return model.Relationship{}, err
+	}
+
+	rel, err := s.repo.GetRelationship(ctx, userID, otherID, MutualSampleSize, MaxFollowDistance)
+	if err != nil {
+		return model.Relationship{}, err
+	}
+
+	response := model.Relationship{
+		UserID:       int(userID),
+		OtherUserID:  int(otherID),
+		Follows:      rel.AFollowsB,
+		FollowedBy:   rel.BFollowsA,
+		MutualCount:  int(rel.MutualCount),
+		MutualSample: make([]int, 0, len(rel.MutualSample)),
+	}
+	for _, id := range rel.MutualSample {
+		response.MutualSample = append(response.MutualSample, int(id))
+	}
+	if rel.Distance != nil {
+		distance := int(*rel.Distance)
+		response.Distance = &distance
+	}
+	return response, nil
+}
+
+func (s *FollowerService) GetFollowCounts(ctx context.Context, userID int64) (model.FollowCounts, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return model.FollowCounts{}, err
+	}
+
+	counts, err := s.repo.GetFollowCounts(ctx, userID)
+	if err != nil {
+		return model.FollowCounts{}, err
+	}
+	return model.FollowCounts{
+		UserID:    int(userID),
+		Followers: int(counts.Followers),
+		Following: int(counts.Following),
+	}, nil
+}
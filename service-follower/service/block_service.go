@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+
+	"follower-service/model"
+)
+
+var (
+	ErrSelfBlock     = Invalid("block.self_block", "Korisnik ne može da blokira samog sebe.")
+	ErrSelfMute      = Invalid("mute.self_mute", "Korisnik ne može da utiša samog sebe.")
+	ErrBlockNotFound = NotFound("block.not_found", "Blokada ne postoji.")
+	ErrMuteNotFound  = NotFound("mute.not_found", "Utišavanje ne postoji.")
+	ErrFollowBlocked = Forbidden("follow.blocked", "Praćenje nije moguće jer je jedan od korisnika blokirao drugog.")
+)
+
+// Block upisuje BLOCKS vezu; postojeća praćenja u oba smera se uklanjaju.
+func (s *FollowerService) Block(ctx context.Context, blockerID, blockedID int64) (model.UserRelation, error) {
+	if blockerID == blockedID {
+		return model.UserRelation{}, ErrSelfBlock
+	}
+	if err := s.ensureBothExist(ctx, blockerID, blockedID); err != nil {
+		return model.UserRelation{}, err
+	}
+	if err := s.repo.CreateBlock(ctx, blockerID, blockedID); err != nil {
+		return model.UserRelation{}, err
+	}
+	return model.UserRelation{UserID: int(blockerID), TargetID: int(blockedID)}, nil
+}
+
+func (s *FollowerService) Unblock(ctx context.Context, blockerID, blockedID int64) error {
+	removed, err := s.repo.DeleteBlock(ctx, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrBlockNotFound
+	}
+	return nil
+}
+
+func (s *FollowerService) GetBlocked(ctx context.Context, userID int64) ([]int64, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetBlocked(ctx, userID)
+}
+
+// Mute ne utiče na praćenje; služi samo da drugi servisi (npr. blog feed)
+// mogu da sakriju sadržaj utišanih korisnika.
+func (s *FollowerService) Mute(ctx context.Context, muterID, mutedID int64) (model.UserRelation, error) {
+	if muterID == mutedID {
+		return model.UserRelation{}, ErrSelfMute
+	}
+	if err := s.ensureBothExist(ctx, muterID, mutedID); err != nil {
+		return model.UserRelation{}, err
+	}
+	if err := s.repo.CreateMute(ctx, muterID, mutedID); err != nil {
+		return model.UserRelation{}, err
+	}
+	return model.UserRelation{UserID: int(muterID), TargetID: int(mutedID)}, nil
+}
+
+func (s *FollowerService) Unmute(ctx context.Context, muterID, mutedID int64) error {
+	removed, err := s.repo.DeleteMute(ctx, muterID, mutedID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrMuteNotFound
+	}
+	return nil
+}
+
+func (s *FollowerService) GetMuted(ctx context.Context, userID int64) ([]int64, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetMuted(ctx, userID)
+}
+
+func (s *FollowerService) ensureBothExist(ctx context.Context, userID, targetID int64) error {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return err
+	}
+	return s.ensureUserExists(ctx, targetID)
+}
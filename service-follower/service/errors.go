@@ -0,0 +1,64 @@
+package service
+
+import "shared/problem"
+
+type ErrorKind int
+
+const (
+	KindInternal ErrorKind = iota
+	KindNotFound
+	KindInvalid
+	KindConflict
+	KindForbidden
+	KindPayloadTooLarge
+	KindUnauthorized
+)
+
+// Error je domenska greška follower servisa. Message je bezbedan za klijenta,
+// a Err (ako postoji) ide samo u log.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Fields  []problem.FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+func Invalid(code, message string) *Error {
+	return &Error{Kind: KindInvalid, Code: code, Message: message}
+}
+
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+var (
+	ErrFollowerNotFound = NotFound("follow.follower_not_found", "Korisnik koji prati (follower) ne postoji.")
+	ErrFollowedNotFound = NotFound("follow.followed_not_found", "Korisnik koji se prati (followed) ne postoji.")
+	ErrUserNotFound     = NotFound("user.not_found", "Korisnik ne postoji.")
+	ErrFollowNotFound   = NotFound("follow.not_found", "Veza ne postoji.")
+	ErrSelfFollow       = Invalid("follow.self_follow", "Korisnik ne može da prati samog sebe.")
+)
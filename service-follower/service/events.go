@@ -0,0 +1,41 @@
+package service
+
+import (
+	"log"
+	"time"
+)
+
+// Follower servis objavljuje follow.* događaje na topic exchange
+// EventsExchange; telo poruke je JSON. Prati ih notification servis.
+const (
+	EventsExchange = "follower-events-exchange"
+
+	RoutingKeyFollowCreated   = "follow.created"
+	RoutingKeyFollowRequested = "follow.requested"
+)
+
+// EventPublisher šalje događaje praćenja drugim servisima; u produkciji je
+// to events.Background iz shared/events, pa zahtev za praćenje ne čeka broker.
+type EventPublisher interface {
+	Publish(routingKey string, event any) error
+}
+
+// FollowEvent je telo follow.* događaja: FollowerID prati (ili traži da
+// prati) FollowedID.
+type FollowEvent struct {
+	FollowerID int64     `json:"followerId"`
+	FollowedID int64     `json:"followedId"`
+	At         time.Time `json:"at"`
+}
+
+// notify objavljuje događaj posle upisa u graf; veza je već sačuvana, pa
+// greška samo završava u logu.
+func (s *FollowerService) notify(routingKey string, followerID, followedID int64) {
+	if s.events == nil {
+		return
+	}
+	event := FollowEvent{FollowerID: followerID, FollowedID: followedID, At: time.Now()}
+	if err := s.events.Publish(routingKey, event); err != nil {
+		log.Printf("Događaj %s (%d -> %d) nije poslat: %s", routingKey, followerID, followedID, err)
+	}
+}
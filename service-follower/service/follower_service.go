@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+
+	"follower-service/db"
+	"follower-service/model"
+)
+
+const (
+	DefaultRecommendationLimit = 10
+	MaxRecommendationLimit     = 50
+)
+
+// Follow proverava da li oba korisnika postoje i tek onda upisuje vezu.
+func (s *FollowerService) Follow(ctx context.Context, followerID, followedID int64) (model.Follower, error) {
+	if followerID == followedID {
+		return model.Follower{}, ErrSelfFollow
+	}
+
+	// 1. Proveravamo da li oba korisnika zaista postoje u bazi
+	followerExists, err := s.repo.UserExists(ctx, followerID)
+	if err != nil {
+		return model.Follower{}, err
+	}
+	followedExists, err := s.repo.UserExists(ctx, followedID)
+	if err != nil {
+		return model.Follower{}, err
+	}
+
+	// 2. Ako bilo koji od korisnika ne postoji, prekidamo
+	if !followerExists {
+		return model.Follower{}, ErrFollowerNotFound
+	}
+	if !followedExists {
+		return model.Follower{}, ErrFollowedNotFound
+	}
+
+	// 3. Blokada i privatnost se proveravaju u istoj transakciji sa upisom,
+	// pa ih istovremeno blokiranje ne može zaobići.
+	result, err := s.repo.CreateFollow(ctx, followerID, followedID)
+	if err != nil {
+		return model.Follower{}, err
+	}
+	switch {
+	case !result.Found:
+		return model.Follower{}, ErrFollowedNotFound
+	case result.Blocked:
+		return model.Follower{}, ErrFollowBlocked
+	}
+
+	response := model.Follower{
+		FollowerID:     int(followerID),
+		FollowedUserID: int(followedID),
+		Status:         model.FollowStatusFollowing,
+	}
+	if result.Requested {
+		// Privatan nalog dobija zahtev umesto veze.
+		response.Status = model.FollowStatusRequested
+		if result.Created {
+			s.notify(RoutingKeyFollowRequested, followerID, followedID)
+		}
+		return response, nil
+	}
+	if result.Created {
+		s.notify(RoutingKeyFollowCreated, followerID, followedID)
+	}
+	return response, nil
+}
+
+// Unfollow vraća false ako veza nije ni postojala.
+func (s *FollowerService) Unfollow(ctx context.Context, followerID, followedID int64) (bool, error) {
+	return s.repo.DeleteFollow(ctx, followerID, followedID)
+}
+
+func (s *FollowerService) GetFollowers(ctx context.Context, userID int64) ([]int64, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetFollowers(ctx, userID)
+}
+
+func (s *FollowerService) GetFollowing(ctx context.Context, userID int64) ([]int64, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetFollowing(ctx, userID)
+}
+
+func (s *FollowerService) IsFollowing(ctx context.Context, followerID, followedID int64) (bool, error) {
+	return s.repo.IsFollowing(ctx, followerID, followedID)
+}
+
+// GetRecommendations ograničava limit na [1, MaxRecommendationLimit].
+func (s *FollowerService) GetRecommendations(ctx context.Context, userID int64, limit int) ([]db.Recommendation, error) {
+	if limit <= 0 {
+		limit = DefaultRecommendationLimit
+	}
+	if limit > MaxRecommendationLimit {
+		limit = MaxRecommendationLimit
+	}
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetRecommendations(ctx, userID, limit)
+}
+
+func (s *FollowerService) ensureUserExists(ctx context.Context, userID int64) error {
+	exists, err := s.repo.UserExists(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrUserNotFound
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	"follower-service/db"
+)
+
+// GraphRepository je sve što servis traži od skladišta grafa. U produkciji
+// je to db.Neo4jRepository, a u testovima db.MemoryRepository.
+type GraphRepository interface {
+	UserExists(ctx context.Context, userID int64) (bool, error)
+
+	CreateFollow(ctx context.Context, followerID, followedID int64) (db.FollowResult, error)
+	DeleteFollow(ctx context.Context, followerID, followedID int64) (bool, error)
+	GetFollowers(ctx context.Context, userID int64) ([]int64, error)
+	GetFollowing(ctx context.Context, userID int64) ([]int64, error)
+	IsFollowing(ctx context.Context, followerID, followedID int64) (bool, error)
+	GetRecommendations(ctx context.Context, userID int64, limit int) ([]db.Recommendation, error)
+
+	CreateBlock(ctx context.Context, blockerID, blockedID int64) error
+	DeleteBlock(ctx context.Context, blockerID, blockedID int64) (bool, error)
+	GetBlocked(ctx context.Context, userID int64) ([]int64, error)
+	CreateMute(ctx context.Context, muterID, mutedID int64) error
+	DeleteMute(ctx context.Context, muterID, mutedID int64) (bool, error)
+	GetMuted(ctx context.Context, userID int64) ([]int64, error)
+
+	SetPrivate(ctx context.Context, userID int64, private bool) error
+	IsPrivate(ctx context.Context, userID int64) (bool, error)
+	ApproveFollowRequest(ctx context.Context, targetID, requesterID int64) (bool, error)
+	DeleteFollowRequest(ctx context.Context, requesterID, targetID int64) (bool, error)
+	GetIncomingRequests(ctx context.Context, userID int64) ([]db.PendingRequest, error)
+	GetOutgoingRequests(ctx context.Context, userID int64) ([]db.PendingRequest, error)
+
+	GetRelationship(ctx context.Context, userID, otherID int64, sampleSize, maxDistance int) (db.Relationship, error)
+	GetFollowCounts(ctx context.Context, userID int64) (db.FollowCounts, error)
+}
+
+// FollowerService sadrži logiku koju dele HTTP i gRPC transport.
+type FollowerService struct {
+	repo   GraphRepository
+	events EventPublisher
+}
+
+// NewFollowerService: events može biti nil, tada se praćenja ne objavljuju.
+func NewFollowerService(repo GraphRepository, events EventPublisher) *FollowerService {
+	return &FollowerService{repo: repo, events: events}
+}
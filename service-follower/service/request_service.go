@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+
+	"follower-service/db"
+	"follower-service/model"
+)
+
+var ErrFollowRequestNotFound = NotFound("follow_request.not_found", "Zahtev za praćenje ne postoji.")
+
+// SetPrivate uključuje ili isključuje privatnost naloga. Isključivanje
+// automatski odobrava sve zahteve koji čekaju.
+func (s *FollowerService) SetPrivate(ctx context.Context, userID int64, private bool) error {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return err
+	}
+	return s.repo.SetPrivate(ctx, userID, private)
+}
+
+func (s *FollowerService) IsPrivate(ctx context.Context, userID int64) (bool, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return false, err
+	}
+	return s.repo.IsPrivate(ctx, userID)
+}
+
+// ApproveFollowRequest pretvara zahtev u vezu i objavljuje follow.created.
+func (s *FollowerService) ApproveFollowRequest(ctx context.Context, targetID, requesterID int64) error {
+	approved, err := s.repo.ApproveFollowRequest(ctx, targetID, requesterID)
+	if err != nil {
+		return err
+	}
+	if !approved {
+		return ErrFollowRequestNotFound
+	}
+	s.notify(RoutingKeyFollowCreated, requesterID, targetID)
+	return nil
+}
+
+// RejectFollowRequest poziva vlasnik privatnog naloga.
+func (s *FollowerService) RejectFollowRequest(ctx context.Context, targetID, requesterID int64) error {
+	return s.deleteFollowRequest(ctx, requesterID, targetID)
+}
+
+// CancelFollowRequest poziva korisnik koji je poslao zahtev.
+func (s *FollowerService) CancelFollowRequest(ctx context.Context, requesterID, targetID int64) error {
+	return s.deleteFollowRequest(ctx, requesterID, targetID)
+}
+
+func (s *FollowerService) GetIncomingRequests(ctx context.Context, userID int64) ([]model.PendingFollowRequest, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+	requests, err := s.repo.GetIncomingRequests(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toPendingFollowRequests(requests), nil
+}
+
+func (s *FollowerService) GetOutgoingRequests(ctx context.Context, userID int64) ([]model.PendingFollowRequest, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+	requests, err := s.repo.GetOutgoingRequests(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toPendingFollowRequests(requests), nil
+}
+
+func (s *FollowerService) deleteFollowRequest(ctx context.Context, requesterID, targetID int64) error {
+	removed, err := s.repo.DeleteFollowRequest(ctx, requesterID, targetID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return ErrFollowRequestNotFound
+	}
+	return nil
+}
+
+func toPendingFollowRequests(requests []db.PendingRequest) []model.PendingFollowRequest {
+	out := make([]model.PendingFollowRequest, 0, len(requests))
+	for _, req := range requests {
+		out = append(out, model.PendingFollowRequest{
+			RequesterID: int(req.RequesterID),
+			TargetID:    int(req.TargetID),
+			CreatedAt:   req.CreatedAt,
+		})
+	}
+	return out
+}
@@ -0,0 +1,285 @@
+// Package events prima događaje blog i follower servisa sa RabbitMQ-a i
+// pretvara ih u obaveštenja.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"notification-service/internal/notification"
+)
+
+// Oba servisa objavljuju JSON događaje na svoj topic exchange; jedan red
+// prima sve što pravi obaveštenja.
+const (
+	blogExchange     = "blog-events-exchange"
+	followerExchange = "follower-events-exchange"
+	exchangeType     = "topic"
+	queueName        = "notification-events-queue"
+
+	// Poruka koja ne može da se obradi posle maxAttempts pokušaja, kao i
+	// neispravan događaj, završava u deadLetterQueue, gde čeka ručnu
+	// proveru. Između pokušaja poruka čeka retryDelay u retryQueue, koji
+	// je po isteku vraća u queueName.
+	deadLetterExchange = "notification-events-dlx"
+	deadLetterQueue    = "notification-events-dead-letter"
+	retryQueue         = "notification-events-retry"
+	retryDelay         = 5 * time.Second
+	maxAttempts        = 5
+
+	// Poruka iz retryQueue stiže sa routing key-em queueName, pa se
+	// originalni key i broj pokušaja čuvaju u zaglavljima.
+	headerRoutingKey = "x-original-routing-key"
+	headerAttempts   = "x-attempts"
+
+	RoutingKeyBlogLiked       = "blog.liked"
+	RoutingKeyBlogCommented   = "blog.commented"
+	RoutingKeyBlogMentioned   = "blog.mentioned"
+	RoutingKeyFollowCreated   = "follow.created"
+	RoutingKeyFollowRequested = "follow.requested"
+
+	reconnectDelay = 10 * time.Second
+)
+
+var bindings = map[string][]string{
+	blogExchange:     {RoutingKeyBlogLiked, RoutingKeyBlogCommented, RoutingKeyBlogMentioned},
+	followerExchange: {RoutingKeyFollowCreated, RoutingKeyFollowRequested},
+}
+
+var errMalformed = errors.New("neispravan događaj")
+
+// Recorder upisuje obaveštenje; u produkciji notification.Service.
+type Recorder interface {
+	Record(activity notification.Activity) error
+}
+
+// retryPublisher vraća poruku u retryQueue; u produkciji *amqp.Channel.
+type retryPublisher interface {
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+}
+
+// Users prevodi ID korisnika iz follower servisa u korisničko ime.
+type Users interface {
+	Username(ctx context.Context, userID int64) (string, bool, error)
+}
+
+// blogActivity je telo blog.* događaja; Mentioned postoji samo u
+// blog.mentioned, jedan događaj po pomenutom korisniku.
+type blogActivity struct {
+	BlogID     string    `json:"blogId"`
+	BlogAuthor string    `json:"blogAuthor"`
+	BlogTitle  string    `json:"blogTitle"`
+	UserID     string    `json:"userId"`
+	CommentID  string    `json:"commentId"`
+	Mentioned  string    `json:"mentioned"`
+	At         time.Time `json:"at"`
+}
+
+// followEvent je telo follow.* događaja; ID-jevi su iz stakeholders servisa.
+type followEvent struct {
+	FollowerID int64     `json:"followerId"`
+	FollowedID int64     `json:"followedId"`
+	At         time.Time `json:"at"`
+}
+
+// StartConsumer prati događaje dok se ctx ne otkaže; nedostupan RabbitMQ
+// ne ruši servis, veza se obnavlja na svakih reconnectDelay.
+func StartConsumer(ctx context.Context, url string, recorder Recorder, users Users) {
+	for {
+		if err := consume(ctx, url, recorder, users); err != nil {
+			log.Printf("⚠️ Potrošač događaja: %s; novi pokušaj za %s", err, reconnectDelay)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func consume(ctx context.Context, url string, recorder Recorder, users Users) error {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	if err := declareDeadLetters(ch); err != nil {
+		return err
+	}
+	q, err := ch.QueueDeclare(queueName, true, false, false, false, amqp.Table{
+		"x-dead-letter-exchange": deadLetterExchange,
+	})
+	if err != nil {
+		return err
+	}
+	for exchange, keys := range bindings {
+		if err := ch.ExchangeDeclare(exchange, exchangeType, true, false, false, false, nil); err != nil {
+			return err
+		}
+		for _, key := range keys {
+			if err := ch.QueueBind(q.Name, key, exchange, false, nil); err != nil {
+				return err
+			}
+		}
+	}
+	msgs, err := ch.Consume(q.Name, "", false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+	log.Println("✅ Potrošač događaja pokrenut")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-msgs:
+			if !ok {
+				return amqp.ErrClosed
+			}
+			handle(ctx, d, ch, recorder, users)
+		}
+	}
+}
+
+// declareDeadLetters pravi deadLetterQueue i retryQueue. retryQueue nema
+// potrošača: poruka u njemu ističe posle retryDelay i preko podrazumevanog
+// exchange-a se vraća u queueName.
+func declareDeadLetters(ch *amqp.Channel) error {
+	if err := ch.ExchangeDeclare(deadLetterExchange, "fanout", true, false, false, false, nil); err != nil {
+		return err
+	}
+	if _, err := ch.QueueDeclare(deadLetterQueue, true, false, false, false, nil); err != nil {
+		return err
+	}
+	if err := ch.QueueBind(deadLetterQueue, "", deadLetterExchange, false, nil); err != nil {
+		return err
+	}
+	_, err := ch.QueueDeclare(retryQueue, true, false, false, false, amqp.Table{
+		"x-message-ttl":             retryDelay.Milliseconds(),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": queueName,
+	})
+	return err
+}
+
+func handle(ctx context.Context, d amqp.Delivery, retries retryPublisher, recorder Recorder, users Users) {
+	routingKey := originalRoutingKey(d)
+	activity, ok, err := toActivity(ctx, routingKey, d.Body, users)
+	if err == nil && ok {
+		err = recorder.Record(activity)
+	}
+	if err == nil {
+		d.Ack(false)
+		return
+	}
+
+	// Neispravan događaj i nepoznat tip neće uspeti ni iz ponovnog pokušaja.
+	if errors.Is(err, errMalformed) || errors.Is(err, notification.ErrUnknownType) {
+		log.Printf("❌ Događaj %s ide u %s: %s", routingKey, deadLetterQueue, err)
+		d.Nack(false, false)
+		return
+	}
+
+	attempt := attempts(d) + 1
+	if attempt >= maxAttempts {
+		log.Printf("❌ Događaj %s ide u %s posle %d pokušaja: %s", routingKey, deadLetterQueue, attempt, err)
+		d.Nack(false, false)
+		return
+	}
+
+	// Record je idempotentan, pa ponovljen događaj ne pravi duplikat.
+	log.Printf("⚠️ Greška pri obradi događaja %s (pokušaj %d od %d): %s", routingKey, attempt, maxAttempts, err)
+	retry := amqp.Publishing{
+		Headers:      amqp.Table{headerRoutingKey: routingKey, headerAttempts: int32(attempt)},
+		ContentType:  d.ContentType,
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    d.Timestamp,
+		Body:         d.Body,
+	}
+	if err := retries.PublishWithContext(ctx, "", retryQueue, false, false, retry); err != nil {
+		// Bez retryQueue poruka ostaje u redu; kanal je verovatno zatvoren,
+		// pa je broker ponovo isporučuje tek posle obnove veze.
+		log.Printf("⚠️ Događaj %s nije vraćen u %s: %s", routingKey, retryQueue, err)
+		d.Nack(false, true)
+		return
+	}
+	d.Ack(false)
+}
+
+// originalRoutingKey vraća routing key pod kojim je događaj objavljen.
+func originalRoutingKey(d amqp.Delivery) string {
+	if key, ok := d.Headers[headerRoutingKey].(string); ok && key != "" {
+		return key
+	}
+	return d.RoutingKey
+}
+
+// attempts vraća broj dosadašnjih neuspelih pokušaja obrade.
+func attempts(d amqp.Delivery) int {
+	if n, ok := d.Headers[headerAttempts].(int32); ok {
+		return int(n)
+	}
+	return 0
+}
+
+// toActivity prevodi događaj u obaveštenje; ok je false za događaj koji
+// ne pravi obaveštenje, npr. praćenje korisnika koji je u međuvremenu obrisan.
+func toActivity(ctx context.Context, routingKey string, body []byte, users Users) (notification.Activity, bool, error) {
+	switch routingKey {
+	case RoutingKeyBlogLiked, RoutingKeyBlogCommented, RoutingKeyBlogMentioned:
+		var e blogActivity
+		if err := json.Unmarshal(body, &e); err != nil || e.BlogID == "" || e.UserID == "" {
+			return notification.Activity{}, false, fmt.Errorf("%w: %s", errMalformed, body)
+		}
+		activity := notification.Activity{
+			Type:      notification.TypeLike,
+			Recipient: e.BlogAuthor,
+			Actor:     e.UserID,
+			BlogID:    e.BlogID,
+			BlogTitle: e.BlogTitle,
+			CommentID: e.CommentID,
+			At:        e.At,
+		}
+		switch routingKey {
+		case RoutingKeyBlogCommented:
+			activity.Type = notification.TypeComment
+		case RoutingKeyBlogMentioned:
+			activity.Type = notification.TypeMention
+			activity.Recipient = e.Mentioned
+		}
+		return activity, activity.Recipient != "", nil
+
+	case RoutingKeyFollowCreated, RoutingKeyFollowRequested:
+		var e followEvent
+		if err := json.Unmarshal(body, &e); err != nil || e.FollowerID == 0 || e.FollowedID == 0 {
+			return notification.Activity{}, false, fmt.Errorf("%w: %s", errMalformed, body)
+		}
+		follower, found, err := users.Username(ctx, e.FollowerID)
+		if err != nil || !found {
+			return notification.Activity{}, false, err
+		}
+		followed, found, err := users.Username(ctx, e.FollowedID)
+		if err != nil || !found {
+			return notification.Activity{}, false, err
+		}
+		activity := notification.Activity{Type: notification.TypeFollow, Recipient: followed, Actor: follower, At: e.At}
+		if routingKey == RoutingKeyFollowRequested {
+			activity.Type = notification.TypeFollowRequest
+		}
+		return activity, true, nil
+	}
+	return notification.Activity{}, false, fmt.Errorf("%w: nepoznat routing key %s", errMalformed, routingKey)
+}
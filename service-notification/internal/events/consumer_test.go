@@ -0,0 +1,142 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"notification-service/internal/notification"
+)
+
+// fakeUsers zna korisnike 1 (ana) i 2 (marko); za ID 99 vraća grešku.
+type fakeUsers struct{}
+
+func (fakeUsers) Username(ctx context.Context, userID int64) (string, bool, error) {
+	switch userID {
+	case 1:
+		return "ana", true, nil
+	case 2:
+		return "marko", true, nil
+	case 99:
+		return "", false, errors.New("stakeholders nedostupan")
+	}
+	return "", false, nil
+}
+
+func TestToActivity(t *testing.T) {
+	blog := `{"blogId":"b1","blogAuthor":"ana","blogTitle":"Tara","userId":"marko","commentId":"c1","mentioned":"jovan","at":"2026-10-19T10:00:00Z"}`
+	tests := []struct {
+		name      string
+		key       string
+		body      string
+		want      notification.Activity
+		wantOK    bool
+		malformed bool
+		wantErr   bool
+	}{
+		{name: "lajk", key: RoutingKeyBlogLiked, body: blog, wantOK: true,
+			want: notification.Activity{Type: notification.TypeLike, Recipient: "ana", Actor: "marko", BlogID: "b1", BlogTitle: "Tara", CommentID: "c1"}},
+		{name: "komentar", key: RoutingKeyBlogCommented, body: blog, wantOK: true,
+			want: notification.Activity{Type: notification.TypeComment, Recipient: "ana", Actor: "marko", BlogID: "b1", BlogTitle: "Tara", CommentID: "c1"}},
+		{name: "pominjanje ide pomenutom", key: RoutingKeyBlogMentioned, body: blog, wantOK: true,
+			want: notification.Activity{Type: notification.TypeMention, Recipient: "jovan", Actor: "marko", BlogID: "b1", BlogTitle: "Tara", CommentID: "c1"}},
+		{name: "praćenje", key: RoutingKeyFollowCreated, body: `{"followerId":2,"followedId":1}`, wantOK: true,
+			want: notification.Activity{Type: notification.TypeFollow, Recipient: "ana", Actor: "marko"}},
+		{name: "zahtev za praćenje", key: RoutingKeyFollowRequested, body: `{"followerId":1,"followedId":2}`, wantOK: true,
+			want: notification.Activity{Type: notification.TypeFollowRequest, Recipient: "marko", Actor: "ana"}},
+		{name: "obrisan korisnik", key: RoutingKeyFollowCreated, body: `{"followerId":3,"followedId":1}`},
+		{name: "stakeholders nedostupan", key: RoutingKeyFollowCreated, body: `{"followerId":99,"followedId":1}`, wantErr: true},
+		{name: "neispravan JSON", key: RoutingKeyBlogLiked, body: `tekst`, malformed: true},
+		{name: "nepoznat routing key", key: "blog.published", body: blog, malformed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := toActivity(context.Background(), tt.key, []byte(tt.body), fakeUsers{})
+			if errors.Is(err, errMalformed) != tt.malformed || (err != nil) != (tt.wantErr || tt.malformed) {
+				t.Fatalf("err = %v", err)
+			}
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %t, očekivano %t", ok, tt.wantOK)
+			}
+			if ok && (got.Type != tt.want.Type || got.Recipient != tt.want.Recipient || got.Actor != tt.want.Actor ||
+				got.BlogID != tt.want.BlogID || got.BlogTitle != tt.want.BlogTitle || got.CommentID != tt.want.CommentID) {
+				t.Errorf("aktivnost = %+v, očekivano %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeAck pamti kako je poruka potvrđena.
+type fakeAck struct {
+	acked, nacked, requeued bool
+}
+
+func (a *fakeAck) Ack(tag uint64, multiple bool) error { a.acked = true; return nil }
+func (a *fakeAck) Nack(tag uint64, multiple, requeue bool) error {
+	a.nacked, a.requeued = true, requeue
+	return nil
+}
+func (a *fakeAck) Reject(tag uint64, requeue bool) error { return a.Nack(tag, false, requeue) }
+
+// fakeRetries pamti poruke vraćene u retryQueue.
+type fakeRetries struct {
+	published []amqp.Publishing
+}
+
+func (r *fakeRetries) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	if key != retryQueue {
+		return errors.New("poruka nije poslata u retryQueue")
+	}
+	r.published = append(r.published, msg)
+	return nil
+}
+
+type recorderFunc func(notification.Activity) error
+
+func (f recorderFunc) Record(activity notification.Activity) error { return f(activity) }
+
+func TestHandleRetriesThenDeadLetters(t *testing.T) {
+	follow := `{"followerId":99,"followedId":1}`
+	recorder := recorderFunc(func(notification.Activity) error { return nil })
+
+	tests := []struct {
+		name        string
+		delivery    amqp.Delivery
+		wantAck     bool
+		wantRetry   bool
+		wantAttempt int32
+	}{
+		{name: "prvi neuspeh ide u retry", delivery: amqp.Delivery{RoutingKey: RoutingKeyFollowCreated, Body: []byte(follow)},
+			wantAck: true, wantRetry: true, wantAttempt: 1},
+		{name: "ponovljen neuspeh čuva routing key", delivery: amqp.Delivery{RoutingKey: queueName, Body: []byte(follow),
+			Headers: amqp.Table{headerRoutingKey: RoutingKeyFollowCreated, headerAttempts: int32(2)}},
+			wantAck: true, wantRetry: true, wantAttempt: 3},
+		{name: "poslednji pokušaj ide u dead letter", delivery: amqp.Delivery{RoutingKey: queueName, Body: []byte(follow),
+			Headers: amqp.Table{headerRoutingKey: RoutingKeyFollowCreated, headerAttempts: int32(maxAttempts - 1)}}},
+		{name: "neispravan događaj odmah ide u dead letter", delivery: amqp.Delivery{RoutingKey: RoutingKeyBlogLiked, Body: []byte(`tekst`)}},
+		{name: "obrađen događaj", delivery: amqp.Delivery{RoutingKey: RoutingKeyFollowCreated, Body: []byte(`{"followerId":2,"followedId":1}`)},
+			wantAck: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ack, retries := &fakeAck{}, &fakeRetries{}
+			tt.delivery.Acknowledger = ack
+			handle(context.Background(), tt.delivery, retries, recorder, fakeUsers{})
+
+			if ack.acked != tt.wantAck || ack.nacked == tt.wantAck || ack.requeued {
+				t.Fatalf("ack = %+v, očekivan ack %t bez vraćanja u red", *ack, tt.wantAck)
+			}
+			if (len(retries.published) == 1) != tt.wantRetry {
+				t.Fatalf("retry poruke = %d", len(retries.published))
+			}
+			if tt.wantRetry {
+				headers := retries.published[0].Headers
+				if headers[headerAttempts] != tt.wantAttempt || headers[headerRoutingKey] != RoutingKeyFollowCreated {
+					t.Errorf("zaglavlja = %v", headers)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Package migrations održava indekse baze obaveštenja.
+// Primenjene verzije se čuvaju u kolekciji "migrations".
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const collectionName = "migrations"
+
+// Migration je jedan verzionisani korak. Up mora biti idempotentan: ako
+// dve replike krenu istovremeno, obe mogu da ga izvrše pre nego što se
+// verzija upiše.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+type record struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"appliedAt"`
+}
+
+// Run primenjuje sve migracije koje još nisu upisane, redom po verziji.
+func Run(ctx context.Context, db *mongo.Database) error {
+	migrations := append([]Migration(nil), all...)
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	coll := db.Collection(collectionName)
+	for _, m := range migrations {
+		if applied[m.Version] {
+			continue
+		}
+
+		log.Printf("🔧 Primena migracije %d: %s", m.Version, m.Description)
+		if err := m.Up(ctx, db); err != nil {
+			return fmt.Errorf("migracija %d (%s): %w", m.Version, m.Description, err)
+		}
+
+		_, err := coll.InsertOne(ctx, record{
+			Version:     m.Version,
+			Description: m.Description,
+			AppliedAt:   time.Now(),
+		})
+		// Druga replika je već upisala istu verziju; to je u redu.
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func appliedVersions(ctx context.Context, db *mongo.Database) (map[int]bool, error) {
+	cursor, err := db.Collection(collectionName).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var records []record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]bool, len(records))
+	for _, r := range records {
+		applied[r.Version] = true
+	}
+	return applied, nil
+}
@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// all je lista svih migracija baze obaveštenja. Nova migracija dobija
+// sledeći broj; postojeće se nikad ne menjaju kada su jednom puštene.
+var all = []Migration{
+	{
+		Version:     1,
+		Description: "indeksi nad notifications kolekcijom",
+		Up:          createNotificationIndexes,
+	},
+}
+
+// createNotificationIndexes: jedinstveni indeks drži najviše jednu
+// nepročitanu grupu po primaocu, tipu i blogu; pročitane grupe nisu u
+// njemu, pa sledeći događaj otvara novu.
+func createNotificationIndexes(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection("notifications").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "recipient", Value: 1}, {Key: "type", Value: 1}, {Key: "blogId", Value: 1}},
+			Options: options.Index().SetName("unread_group").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"read": false}),
+		},
+		{
+			Keys:    bson.D{{Key: "recipient", Value: 1}, {Key: "read", Value: 1}, {Key: "updatedAt", Value: -1}},
+			Options: options.Index().SetName("recipient_read_updatedAt"),
+		},
+	})
+	return err
+}
@@ -0,0 +1,12 @@
+package notification
+
+import "net/http"
+
+// Gateway posle validacije tokena dodaje ovaj header.
+const headerUsername = "X-Username"
+
+// callerID: primalac je uvek korisnik koji šalje zahtev, i to samo iz
+// X-Username; ?user= bi dozvolio čitanje tuđih obaveštenja.
+func callerID(r *http.Request) string {
+	return r.Header.Get(headerUsername)
+}
@@ -0,0 +1,89 @@
+package notification
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"shared/problem"
+)
+
+type ErrorKind int
+
+const (
+	KindInternal ErrorKind = iota
+	KindNotFound
+	KindInvalid
+	KindUnauthorized
+)
+
+// Error je domenska greška paketa. Message se šalje klijentu, a Err (ako
+// postoji) samo završava u logu.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+func Invalid(code, message string) *Error {
+	return &Error{Kind: KindInvalid, Code: code, Message: message}
+}
+
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+var (
+	ErrNotificationNotFound  = NotFound("notification.not_found", "Obaveštenje nije pronađeno.")
+	ErrInvalidNotificationID = Invalid("notification.invalid_id", "Neispravan ID obaveštenja.")
+	ErrUnknownType           = Invalid("notification.unknown_type", "Nepoznat tip obaveštenja.")
+)
+
+func (k ErrorKind) status() int {
+	switch k {
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindInvalid:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeError je jedino mesto gde se greške prevode u HTTP odgovor.
+// Sve što nije domenska greška loguje se i vraća kao generički 500.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var domainErr *Error
+	if errors.As(err, &domainErr) && domainErr.Kind != KindInternal {
+		if domainErr.Err != nil {
+			log.Printf("%s %s: %v", r.Method, r.URL.Path, domainErr.Err)
+		}
+		problem.Write(w, r, problem.New(domainErr.Kind.status(), domainErr.Code, domainErr.Message))
+		return
+	}
+
+	log.Printf("❌ %s %s: %v", r.Method, r.URL.Path, err)
+	problem.Write(w, r, problem.New(http.StatusInternalServerError, "internal_error", "Došlo je do greške na serveru."))
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	problem.Write(w, r, problem.New(http.StatusMethodNotAllowed, "method_not_allowed", "Metoda nije dozvoljena."))
+}
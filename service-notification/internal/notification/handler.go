@@ -0,0 +1,172 @@
+package notification
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+var (
+	errInvalidPagination = Invalid("request.invalid_pagination", "offset i limit moraju biti nenegativni celi brojevi.")
+	errInvalidUnread     = Invalid("request.invalid_unread", "unread mora biti true ili false.")
+	errMissingUser       = Unauthorized("auth.missing_user", "Nedostaje korisnik (X-Username).")
+)
+
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/notifications", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			h.GetNotifications(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/notifications/unread-count", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "GET":
+			h.GetUnreadCount(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/notifications/read", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "POST":
+			h.MarkAllRead(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+
+	mux.HandleFunc("/notifications/{id}/read", func(w http.ResponseWriter, r *http.Request) {
+
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		switch r.Method {
+		case "POST":
+			h.MarkRead(w, r)
+		default:
+			writeMethodNotAllowed(w, r)
+		}
+	})
+}
+
+// GetNotifications: GET /notifications?offset=&limit=&unread=true vraća
+// obaveštenja korisnika od poslednjeg, zajedno sa brojem nepročitanih.
+func (h *Handler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	user := callerID(r)
+	if user == "" {
+		writeError(w, r, errMissingUser)
+		return
+	}
+
+	offset, err1 := queryInt(r, "offset")
+	limit, err2 := queryInt(r, "limit")
+	if err1 != nil || err2 != nil || offset < 0 || limit < 0 {
+		writeError(w, r, errInvalidPagination)
+		return
+	}
+	filter := Filter{Offset: offset, Limit: limit}
+	if raw := r.URL.Query().Get("unread"); raw != "" {
+		unread, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, r, errInvalidUnread)
+			return
+		}
+		filter.UnreadOnly = unread
+	}
+
+	page, err := h.service.List(user, filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(page)
+}
+
+// GetUnreadCount: GET /notifications/unread-count vraća {"unread": n} za bedž.
+func (h *Handler) GetUnreadCount(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	user := callerID(r)
+	if user == "" {
+		writeError(w, r, errMissingUser)
+		return
+	}
+
+	unread, err := h.service.UnreadCount(user)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]int64{"unread": unread})
+}
+
+// MarkRead: POST /notifications/{id}/read označava jedno obaveštenje kao pročitano.
+func (h *Handler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	user := callerID(r)
+	if user == "" {
+		writeError(w, r, errMissingUser)
+		return
+	}
+
+	if err := h.service.MarkRead(user, r.PathValue("id")); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// MarkAllRead: POST /notifications/read označava sva obaveštenja kao
+// pročitana i vraća {"marked": n}.
+func (h *Handler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	user := callerID(r)
+	if user == "" {
+		writeError(w, r, errMissingUser)
+		return
+	}
+
+	marked, err := h.service.MarkAllRead(user)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]int64{"marked": marked})
+}
+
+// queryInt vraća 0 ako parametar nije poslat.
+func queryInt(r *http.Request, name string) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(raw)
+}
@@ -0,0 +1,177 @@
+package notification
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"shared/problem"
+)
+
+var _ NotificationRepository = (*MemoryRepository)(nil)
+
+const blogID = "65f000000000000000000001"
+
+func newTestServer(t *testing.T) (*http.ServeMux, *Service) {
+	t.Helper()
+	service := NewService(NewMemoryRepository())
+	mux := http.NewServeMux()
+	NewHandler(service).RegisterRoutes(mux)
+	return mux, service
+}
+
+func do(t *testing.T, mux *http.ServeMux, method, path, user string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	if user != "" {
+		req.Header.Set(headerUsername, user)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func list(t *testing.T, mux *http.ServeMux, path, user string) Page {
+	t.Helper()
+	rec := do(t, mux, http.MethodGet, path, user)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s: status = %d; telo: %s", path, rec.Code, rec.Body.String())
+	}
+	var page Page
+	if err := json.NewDecoder(rec.Body).Decode(&page); err != nil {
+		t.Fatal(err)
+	}
+	return page
+}
+
+func TestGrouping(t *testing.T) {
+	mux, service := newTestServer(t)
+	start := time.Now()
+	record := func(a Activity) {
+		t.Helper()
+		if err := service.Record(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Pet različitih lajkova, jedan ponovljen i jedan sopstveni daju jednu grupu.
+	for i, actor := range []string{"jovan", "mila", "petar", "mila", "sara", "ana", "luka"} {
+		record(Activity{Type: TypeLike, Recipient: "ana", Actor: actor, BlogID: blogID, BlogTitle: "Tara", At: start.Add(time.Duration(i) * time.Second)})
+	}
+	record(Activity{Type: TypeComment, Recipient: "ana", Actor: "mila", BlogID: blogID, BlogTitle: "Tara", CommentID: "c1", At: start.Add(10 * time.Second)})
+	record(Activity{Type: TypeFollow, Recipient: "ana", Actor: "petar", At: start.Add(20 * time.Second)})
+	record(Activity{Type: TypeLike, Recipient: "marko", Actor: "ana", BlogID: blogID, BlogTitle: "Drugi", At: start})
+
+	page := list(t, mux, "/notifications", "ana")
+	if page.Unread != 3 || len(page.Notifications) != 3 {
+		t.Fatalf("unread = %d, obaveštenja = %+v", page.Unread, page.Notifications)
+	}
+	follow, comment, like := page.Notifications[0], page.Notifications[1], page.Notifications[2]
+	if follow.Type != TypeFollow || follow.Message != "Korisnik petar vas prati." {
+		t.Errorf("praćenje = %+v", follow)
+	}
+	if comment.Type != TypeComment || comment.CommentID != "c1" || comment.Message != "Korisnik mila komentariše vaš blog „Tara”." {
+		t.Errorf("komentar = %+v", comment)
+	}
+	if like.ActorCount != 5 || like.LastActor != "luka" || like.Message != "Korisnicima luka i još 4 se sviđa vaš blog „Tara”." {
+		t.Errorf("lajkovi = %+v", like)
+	}
+
+	rec := do(t, mux, http.MethodGet, "/notifications/unread-count", "ana")
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"unread":3}` {
+		t.Errorf("unread-count = %d %s", rec.Code, rec.Body)
+	}
+
+	// Tuđe obaveštenje se ne vidi i ne može da se pročita.
+	if rec := do(t, mux, http.MethodPost, "/notifications/"+like.ID+"/read", "marko"); rec.Code != http.StatusNotFound {
+		t.Errorf("tuđe obaveštenje: status = %d", rec.Code)
+	}
+	if rec := do(t, mux, http.MethodPost, "/notifications/"+like.ID+"/read", "ana"); rec.Code != http.StatusNoContent {
+		t.Fatalf("čitanje: status = %d; telo: %s", rec.Code, rec.Body.String())
+	}
+
+	// Posle čitanja novi lajk otvara novu grupu.
+	record(Activity{Type: TypeLike, Recipient: "ana", Actor: "jovan", BlogID: blogID, BlogTitle: "Tara", At: start.Add(30 * time.Second)})
+	unread := list(t, mux, "/notifications?unread=true", "ana")
+	if unread.Unread != 3 || len(unread.Notifications) != 3 {
+		t.Fatalf("nepročitana = %+v", unread)
+	}
+	if n := unread.Notifications[0]; n.ActorCount != 1 || n.Message != "Korisniku jovan se sviđa vaš blog „Tara”." {
+		t.Errorf("nova grupa = %+v", n)
+	}
+	if all := list(t, mux, "/notifications?limit=2&offset=2", "ana"); len(all.Notifications) != 2 || all.Notifications[1].ID != like.ID || !all.Notifications[1].Read {
+		t.Errorf("druga strana = %+v", all.Notifications)
+	}
+
+	rec = do(t, mux, http.MethodPost, "/notifications/read", "ana")
+	if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"marked":3}` {
+		t.Errorf("čitanje svih = %d %s", rec.Code, rec.Body)
+	}
+	if page := list(t, mux, "/notifications", "ana"); page.Unread != 0 || len(page.Notifications) != 4 {
+		t.Errorf("posle čitanja svih: unread = %d, obaveštenja = %d", page.Unread, len(page.Notifications))
+	}
+}
+
+func TestActorsAreCapped(t *testing.T) {
+	repo := NewMemoryRepository()
+	start := time.Now()
+	for i := range maxActors + 10 {
+		if err := repo.Record(Activity{Type: TypeLike, Recipient: "ana", Actor: fmt.Sprintf("korisnik%d", i), BlogID: blogID, At: start}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Ponovljen lajk zapamćenog aktera se ne broji.
+	if err := repo.Record(Activity{Type: TypeLike, Recipient: "ana", Actor: "korisnik50", BlogID: blogID, At: start}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, n := range repo.notifications {
+		if n.ActorCount != maxActors+10 || len(n.Actors) != maxActors || n.Actors[len(n.Actors)-1] != "korisnik50" || n.Actors[0] != "korisnik10" {
+			t.Errorf("grupa: actorCount = %d, actors = %d (%s … %s)", n.ActorCount, len(n.Actors), n.Actors[0], n.Actors[len(n.Actors)-1])
+		}
+	}
+}
+
+func TestHandlerErrors(t *testing.T) {
+	mux, _ := newTestServer(t)
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		user       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "bez korisnika", method: http.MethodGet, path: "/notifications", wantStatus: http.StatusUnauthorized, wantCode: "auth.missing_user"},
+		{name: "korisnik iz upita", method: http.MethodGet, path: "/notifications?user=ana", wantStatus: http.StatusUnauthorized, wantCode: "auth.missing_user"},
+		{name: "brojač bez korisnika", method: http.MethodGet, path: "/notifications/unread-count", wantStatus: http.StatusUnauthorized, wantCode: "auth.missing_user"},
+		{name: "čitanje bez korisnika", method: http.MethodPost, path: "/notifications/" + blogID + "/read", wantStatus: http.StatusUnauthorized, wantCode: "auth.missing_user"},
+		{name: "neispravan limit", method: http.MethodGet, path: "/notifications?limit=-1", user: "ana", wantStatus: http.StatusBadRequest, wantCode: "request.invalid_pagination"},
+		{name: "neispravan unread", method: http.MethodGet, path: "/notifications?unread=mozda", user: "ana", wantStatus: http.StatusBadRequest, wantCode: "request.invalid_unread"},
+		{name: "neispravan ID", method: http.MethodPost, path: "/notifications/abc/read", user: "ana", wantStatus: http.StatusBadRequest, wantCode: "notification.invalid_id"},
+		{name: "nepostojeće obaveštenje", method: http.MethodPost, path: "/notifications/" + blogID + "/read", user: "ana", wantStatus: http.StatusNotFound, wantCode: "notification.not_found"},
+		{name: "pogrešna metoda", method: http.MethodDelete, path: "/notifications", user: "ana", wantStatus: http.StatusMethodNotAllowed, wantCode: "method_not_allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(t, mux, tt.method, tt.path, tt.user)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, očekivano %d; telo: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			var p problem.Problem
+			if err := json.NewDecoder(rec.Body).Decode(&p); err != nil {
+				t.Fatal(err)
+			}
+			if p.Code != tt.wantCode {
+				t.Errorf("code = %q, očekivano %q", p.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package notification
+
+import (
+	"slices"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MemoryRepository čuva obaveštenja u memoriji i vraća iste greške kao
+// Repository, pa handleri i servis mogu da se testiraju bez MongoDB-a.
+type MemoryRepository struct {
+	mu            sync.Mutex
+	notifications map[string]Notification
+}
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{notifications: map[string]Notification{}}
+}
+
+func (m *MemoryRepository) Record(activity Activity) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var group Notification
+	for _, n := range m.notifications {
+		if n.Recipient == activity.Recipient && n.Type == activity.Type && n.BlogID == activity.BlogID && !n.Read {
+			group = n
+			break
+		}
+	}
+	if group.ID == "" {
+		group = Notification{
+			ID:        primitive.NewObjectID().Hex(),
+			Recipient: activity.Recipient,
+			Type:      activity.Type,
+			BlogID:    activity.BlogID,
+			CreatedAt: activity.At,
+		}
+	}
+
+	if !slices.Contains(group.Actors, activity.Actor) {
+		group.ActorCount++
+	}
+	actors := slices.DeleteFunc(slices.Clone(group.Actors), func(a string) bool { return a == activity.Actor })
+	actors = append(actors, activity.Actor)
+	group.Actors = actors[max(0, len(actors)-maxActors):]
+	group.LastActor = activity.Actor
+	if activity.BlogTitle != "" {
+		group.BlogTitle = activity.BlogTitle
+	}
+	if activity.CommentID != "" {
+		group.CommentID = activity.CommentID
+	}
+	if activity.At.After(group.UpdatedAt) {
+		group.UpdatedAt = activity.At
+	}
+	m.notifications[group.ID] = group
+	return nil
+}
+
+func (m *MemoryRepository) List(recipient string, filter Filter) ([]Notification, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	notifications := []Notification{}
+	for _, n := range m.notifications {
+		if n.Recipient != recipient || (filter.UnreadOnly && n.Read) {
+			continue
+		}
+		n.Actors = nil
+		notifications = append(notifications, n)
+	}
+	sort.Slice(notifications, func(i, j int) bool {
+		a, b := notifications[i], notifications[j]
+		if !a.UpdatedAt.Equal(b.UpdatedAt) {
+			return a.UpdatedAt.After(b.UpdatedAt)
+		}
+		return a.ID > b.ID
+	})
+
+	if filter.Offset >= len(notifications) {
+		return []Notification{}, nil
+	}
+	notifications = notifications[filter.Offset:]
+	if len(notifications) > filter.Limit {
+		notifications = notifications[:filter.Limit]
+	}
+	return notifications, nil
+}
+
+func (m *MemoryRepository) UnreadCount(recipient string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var count int64
+	for _, n := range m.notifications {
+		if n.Recipient == recipient && !n.Read {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MemoryRepository) MarkRead(recipient, id string) error {
+	if _, err := parseNotificationID(id); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	n, ok := m.notifications[id]
+	if !ok || n.Recipient != recipient {
+		return ErrNotificationNotFound
+	}
+	n.Read = true
+	m.notifications[id] = n
+	return nil
+}
+
+func (m *MemoryRepository) MarkAllRead(recipient string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var marked int64
+	for id, n := range m.notifications {
+		if n.Recipient == recipient && !n.Read {
+			n.Read = true
+			m.notifications[id] = n
+			marked++
+		}
+	}
+	return marked, nil
+}
@@ -0,0 +1,60 @@
+package notification
+
+import "time"
+
+// Tipovi obaveštenja; svaki odgovara jednom događaju iz blog ili follower
+// servisa.
+const (
+	TypeLike          = "like"
+	TypeComment       = "comment"
+	TypeMention       = "mention"
+	TypeFollow        = "follow"
+	TypeFollowRequest = "follow_request"
+)
+
+// Notification je grupa nepročitanih događaja istog tipa za isti blog:
+// "ana i još 4" su jedno obaveštenje sa pet aktera. Kada se grupa pročita,
+// sledeći događaj otvara novu. Praćenja nemaju blog, pa se grupišu po tipu.
+type Notification struct {
+	ID         string    `json:"id" bson:"_id,omitempty"`
+	Recipient  string    `json:"recipient" bson:"recipient"`
+	Type       string    `json:"type" bson:"type"`
+	BlogID     string    `json:"blogId,omitempty" bson:"blogId"`
+	BlogTitle  string    `json:"blogTitle,omitempty" bson:"blogTitle,omitempty"`
+	CommentID  string    `json:"commentId,omitempty" bson:"commentId,omitempty"`
+	Actors     []string  `json:"-" bson:"actors"`
+	LastActor  string    `json:"lastActor" bson:"lastActor"`
+	ActorCount int       `json:"actorCount" bson:"actorCount"`
+	Message    string    `json:"message" bson:"-"`
+	Read       bool      `json:"read" bson:"read"`
+	CreatedAt  time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Activity je jedan događaj koji treba da stigne do primaoca: Actor je
+// lajkovao, komentarisao, pomenuo ili zapratio Recipient-a.
+type Activity struct {
+	Type      string
+	Recipient string
+	Actor     string
+	BlogID    string
+	BlogTitle string
+	CommentID string
+	At        time.Time
+}
+
+// Page je jedna strana obaveštenja sa brojem nepročitanih, da klijent ne
+// bi slao poseban zahtev za bedž.
+type Page struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int64          `json:"unread"`
+	Offset        int            `json:"offset"`
+	Limit         int            `json:"limit"`
+}
+
+// Filter sužava listu obaveštenja.
+type Filter struct {
+	UnreadOnly bool
+	Offset     int
+	Limit      int
+}
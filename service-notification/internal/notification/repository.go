@@ -0,0 +1,162 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NotificationRepository je skladište koje Service koristi. Repository ga
+// implementira nad MongoDB-om, a MemoryRepository u memoriji za testove.
+type NotificationRepository interface {
+	// Record dodaje aktera u nepročitanu grupu (Recipient, Type, BlogID) ili
+	// otvara novu. Grupa pamti samo poslednjih maxActors aktera, a ActorCount
+	// broji sve; akter koji je među zapamćenima se ne broji dvaput, pa je
+	// ponovljena poruka iz reda bezopasna.
+	Record(activity Activity) error
+	// List vraća obaveštenja od poslednje izmenjenog.
+	List(recipient string, filter Filter) ([]Notification, error)
+	UnreadCount(recipient string) (int64, error)
+	// MarkRead vraća ErrNotificationNotFound i za tuđe obaveštenje.
+	MarkRead(recipient, id string) error
+	// MarkAllRead vraća broj obaveštenja koja su tek sada pročitana.
+	MarkAllRead(recipient string) (int64, error)
+}
+
+// maxActors ograničava niz actors, da popularan blog ne bi napravio
+// dokument koji raste bez granice. Akter koji je ispao iz niza se, ako
+// ponovo lajkuje, broji još jednom.
+const maxActors = 100
+
+type Repository struct {
+	collection *mongo.Collection
+}
+
+func NewRepository(db *mongo.Database) *Repository {
+	return &Repository{collection: db.Collection("notifications")}
+}
+
+// Record je jedan upsert nad jedinstvenim indeksom nepročitanih grupa.
+// Kada dve replike istovremeno otvaraju istu grupu, jedna dobija grešku
+// duplikata; ponovljen upsert tada nalazi grupu koju je druga upisala.
+func (r *Repository) Record(activity Activity) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"recipient": activity.Recipient,
+		"type":      activity.Type,
+		"blogId":    activity.BlogID,
+		"read":      false,
+	}
+	// Oba izraza vide actors pre izmene; akter se premešta na kraj niza.
+	actors := bson.M{"$ifNull": bson.A{"$actors", bson.A{}}}
+	known := bson.M{"$in": bson.A{activity.Actor, actors}}
+	set := bson.M{
+		"actors": bson.M{"$slice": bson.A{
+			bson.M{"$concatArrays": bson.A{
+				bson.M{"$filter": bson.M{"input": actors, "cond": bson.M{"$ne": bson.A{"$$this", activity.Actor}}}},
+				bson.A{activity.Actor},
+			}},
+			-maxActors,
+		}},
+		"actorCount": bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$actorCount", 0}}, bson.M{"$cond": bson.A{known, 0, 1}}}},
+		"lastActor":  activity.Actor,
+		"createdAt":  bson.M{"$ifNull": bson.A{"$createdAt", activity.At}},
+		"updatedAt":  bson.M{"$max": bson.A{"$updatedAt", activity.At}},
+	}
+	if activity.BlogTitle != "" {
+		set["blogTitle"] = activity.BlogTitle
+	}
+	if activity.CommentID != "" {
+		set["commentId"] = activity.CommentID
+	}
+	update := mongo.Pipeline{{{Key: "$set", Value: set}}}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := r.collection.UpdateOne(ctx, filter, update, opts)
+	if mongo.IsDuplicateKeyError(err) {
+		_, err = r.collection.UpdateOne(ctx, filter, update, opts)
+	}
+	return err
+}
+
+func (r *Repository) List(recipient string, filter Filter) ([]Notification, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	match := bson.M{"recipient": recipient}
+	if filter.UnreadOnly {
+		match["read"] = false
+	}
+	opts := options.Find().
+		SetProjection(bson.M{"actors": 0}).
+		SetSort(bson.D{{Key: "updatedAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(filter.Offset)).
+		SetLimit(int64(filter.Limit))
+	cursor, err := r.collection.Find(ctx, match, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	notifications := []Notification{}
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+func (r *Repository) UnreadCount(recipient string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.collection.CountDocuments(ctx, bson.M{"recipient": recipient, "read": false})
+}
+
+// MarkRead je idempotentan: već pročitano obaveštenje ne vraća grešku.
+func (r *Repository) MarkRead(recipient, id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	objID, err := parseNotificationID(id)
+	if err != nil {
+		return err
+	}
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objID, "recipient": recipient},
+		bson.M{"$set": bson.M{"read": true}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (r *Repository) MarkAllRead(recipient string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"recipient": recipient, "read": false},
+		bson.M{"$set": bson.M{"read": true}},
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func parseNotificationID(id string) (primitive.ObjectID, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, ErrInvalidNotificationID
+	}
+	return objID, nil
+}
@@ -0,0 +1,121 @@
+//go:build integration
+
+// Integracioni testovi rade nad pravim MongoDB-om, u privremenoj bazi koja
+// se briše posle testa:
+//
+//	MONGO_TEST_URI=mongodb://localhost:27017 go test -tags integration ./internal/notification/
+//
+// Bez MONGO_TEST_URI testovi se preskaču.
+package notification
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"notification-service/internal/migrations"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func integrationRepository(t *testing.T) *Repository {
+	t.Helper()
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI nije postavljen")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("konekcija: %v", err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		client.Disconnect(context.Background())
+		t.Skipf("MongoDB nije dostupan: %v", err)
+	}
+
+	database := client.Database(fmt.Sprintf("notificationDB_test_%d", time.Now().UnixNano()))
+	t.Cleanup(func() {
+		database.Drop(context.Background())
+		client.Disconnect(context.Background())
+	})
+
+	if err := migrations.Run(ctx, database); err != nil {
+		t.Fatalf("migracije: %v", err)
+	}
+	return NewRepository(database)
+}
+
+func TestMongoGrouping(t *testing.T) {
+	repo := integrationRepository(t)
+	start := time.Now().UTC().Truncate(time.Millisecond)
+
+	// Replike istovremeno otvaraju istu grupu; jedinstveni indeks drži jednu.
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			activity := Activity{Type: TypeLike, Recipient: "ana", Actor: fmt.Sprintf("korisnik%d", i%5), BlogID: "b1", BlogTitle: "Tara", At: start.Add(time.Duration(i) * time.Second)}
+			if err := repo.Record(activity); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if err := repo.Record(Activity{Type: TypeFollow, Recipient: "ana", Actor: "marko", At: start}); err != nil {
+		t.Fatal(err)
+	}
+
+	notifications, err := repo.List("ana", Filter{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notifications) != 2 {
+		t.Fatalf("obaveštenja = %+v", notifications)
+	}
+	like := notifications[0]
+	if like.Type != TypeLike || like.ActorCount != 5 || like.Actors != nil || !like.UpdatedAt.Equal(start.Add(9*time.Second)) || !like.CreatedAt.Before(like.UpdatedAt) {
+		t.Errorf("lajkovi = %+v", like)
+	}
+
+	if err := repo.MarkRead("marko", like.ID); err != ErrNotificationNotFound {
+		t.Errorf("tuđe obaveštenje: %v", err)
+	}
+	if err := repo.MarkRead("ana", like.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Record(Activity{Type: TypeLike, Recipient: "ana", Actor: "korisnik1", BlogID: "b1", At: start.Add(time.Minute)}); err != nil {
+		t.Fatal(err)
+	}
+	if count, err := repo.UnreadCount("ana"); count != 2 || err != nil {
+		t.Errorf("nepročitano = %d, %v", count, err)
+	}
+	// Niz aktera ne raste preko maxActors, a broj aktera raste.
+	for i := range maxActors + 10 {
+		if err := repo.Record(Activity{Type: TypeComment, Recipient: "ana", Actor: fmt.Sprintf("korisnik%d", i), BlogID: "b1", At: start}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var comment Notification
+	if err := repo.collection.FindOne(context.Background(), bson.M{"recipient": "ana", "type": TypeComment}).Decode(&comment); err != nil {
+		t.Fatal(err)
+	}
+	if comment.ActorCount != maxActors+10 || len(comment.Actors) != maxActors || comment.Actors[0] != "korisnik10" {
+		t.Errorf("komentari: actorCount = %d, actors = %d", comment.ActorCount, len(comment.Actors))
+	}
+
+	if marked, err := repo.MarkAllRead("ana"); marked != 3 || err != nil {
+		t.Errorf("MarkAllRead = %d, %v", marked, err)
+	}
+	if unread, _ := repo.List("ana", Filter{UnreadOnly: true, Limit: 10}); len(unread) != 0 {
+		t.Errorf("nepročitana posle MarkAllRead = %+v", unread)
+	}
+}
@@ -0,0 +1,120 @@
+package notification
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+type Service struct {
+	repo NotificationRepository
+}
+
+func NewService(repo NotificationRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// Record upisuje događaj. Korisnik ne dobija obaveštenje o sopstvenoj
+// aktivnosti, npr. kada lajkuje svoj blog.
+func (s *Service) Record(activity Activity) error {
+	if !validType(activity.Type) {
+		return ErrUnknownType
+	}
+	if activity.Recipient == "" || activity.Actor == "" || activity.Recipient == activity.Actor {
+		return nil
+	}
+	if activity.At.IsZero() {
+		activity.At = time.Now()
+	}
+	activity.At = activity.At.UTC().Truncate(time.Millisecond)
+	return s.repo.Record(activity)
+}
+
+func (s *Service) List(recipient string, filter Filter) (Page, error) {
+	filter.Limit = clampLimit(filter.Limit, DefaultPageSize, MaxPageSize)
+	notifications, err := s.repo.List(recipient, filter)
+	if err != nil {
+		return Page{}, err
+	}
+	unread, err := s.repo.UnreadCount(recipient)
+	if err != nil {
+		return Page{}, err
+	}
+	for i := range notifications {
+		notifications[i].Message = message(notifications[i])
+	}
+	return Page{Notifications: notifications, Unread: unread, Offset: filter.Offset, Limit: filter.Limit}, nil
+}
+
+func (s *Service) UnreadCount(recipient string) (int64, error) {
+	return s.repo.UnreadCount(recipient)
+}
+
+func (s *Service) MarkRead(recipient, id string) error {
+	return s.repo.MarkRead(recipient, id)
+}
+
+func (s *Service) MarkAllRead(recipient string) (int64, error) {
+	return s.repo.MarkAllRead(recipient)
+}
+
+func validType(t string) bool {
+	switch t {
+	case TypeLike, TypeComment, TypeMention, TypeFollow, TypeFollowRequest:
+		return true
+	}
+	return false
+}
+
+// message sastavlja tekst obaveštenja: "Korisnicima ana i još 4 se sviđa
+// vaš blog „Tara”." Glagol je u sadašnjem vremenu, pa ne zavisi od roda.
+func message(n Notification) string {
+	one := n.ActorCount <= 1
+	actors := n.LastActor
+	if !one {
+		actors = fmt.Sprintf("%s i još %d", n.LastActor, n.ActorCount-1)
+	}
+
+	switch n.Type {
+	case TypeLike:
+		if one {
+			return fmt.Sprintf("Korisniku %s se sviđa vaš blog „%s”.", actors, n.BlogTitle)
+		}
+		return fmt.Sprintf("Korisnicima %s se sviđa vaš blog „%s”.", actors, n.BlogTitle)
+	case TypeComment:
+		if one {
+			return fmt.Sprintf("Korisnik %s komentariše vaš blog „%s”.", actors, n.BlogTitle)
+		}
+		return fmt.Sprintf("Korisnici %s komentarišu vaš blog „%s”.", actors, n.BlogTitle)
+	case TypeMention:
+		if one {
+			return fmt.Sprintf("Korisnik %s vas pominje na blogu „%s”.", actors, n.BlogTitle)
+		}
+		return fmt.Sprintf("Korisnici %s vas pominju na blogu „%s”.", actors, n.BlogTitle)
+	case TypeFollow:
+		if one {
+			return fmt.Sprintf("Korisnik %s vas prati.", actors)
+		}
+		return fmt.Sprintf("Korisnici %s vas prate.", actors)
+	case TypeFollowRequest:
+		if one {
+			return fmt.Sprintf("Korisnik %s želi da vas prati.", actors)
+		}
+		return fmt.Sprintf("Korisnici %s žele da vas prate.", actors)
+	}
+	return ""
+}
+
+func clampLimit(limit, def, max int) int {
+	if limit <= 0 {
+		return def
+	}
+	if limit > max {
+		return max
+	}
+	return limit
+}
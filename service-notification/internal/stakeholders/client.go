@@ -0,0 +1,31 @@
+// Package stakeholders prevodi numeričke ID-jeve korisnika iz follower
+// servisa u korisnička imena, koja ostali servisi koriste kao identitet.
+package stakeholders
+
+import (
+	"context"
+	"time"
+
+	"shared/stakeholders"
+)
+
+// serviceName je identitet koji notification servis šalje service-stakeholders.
+const serviceName = "notification-service"
+
+// Client čita korisnike preko REST API-ja service-stakeholders.
+type Client struct {
+	client *stakeholders.Client
+}
+
+func NewClient(baseURL string) *Client {
+	return &Client{client: stakeholders.NewClient(baseURL, serviceName, 3*time.Second)}
+}
+
+// Username vraća korisničko ime; found je false ako korisnik ne postoji.
+func (c *Client) Username(ctx context.Context, userID int64) (string, bool, error) {
+	u, found, err := c.client.UserByID(ctx, userID)
+	if err != nil || !found {
+		return "", false, err
+	}
+	return u.Username, u.Username != "", nil
+}
@@ -0,0 +1,18 @@
+package com.tours.config;
+
+import org.springframework.amqp.core.TopicExchange;
+import org.springframework.context.annotation.Bean;
+import org.springframework.context.annotation.Configuration;
+
+@Configuration
+public class RabbitMQConfig {
+
+    // Isti exchange deklariše i service-blog koji prati tour.deleted.
+    public static final String TOUR_EVENTS_EXCHANGE = "tour-events-exchange";
+    public static final String TOUR_DELETED_ROUTING_KEY = "tour.deleted";
+
+    @Bean
+    public TopicExchange tourEventsExchange() {
+        return new TopicExchange(TOUR_EVENTS_EXCHANGE, true, false);
+    }
+}
@@ -47,6 +47,10 @@ public class KeyPointController {
         try {
             List<KeyPoint> keyPoints = keyPointService.getAllKeyPointsByTour(tourId, autorUsername);
             return ResponseEntity.ok(keyPoints);
+        } catch (IllegalArgumentException e) {
+            // Nepostojeća tura je 404, da bi je pozivaoci razlikovali od loših zahteva.
+            return ResponseEntity.status(HttpStatus.NOT_FOUND)
+                    .body(Map.of("error", e.getMessage()));
         } catch (Exception e) {
             return ResponseEntity.status(HttpStatus.BAD_REQUEST)
                     .body(Map.of("error", e.getMessage()));
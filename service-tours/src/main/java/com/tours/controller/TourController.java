@@ -2,6 +2,7 @@ package com.tours.controller;
 
 import com.tours.enums.Difficulty;
 import com.tours.enums.TourStatus;
+import com.tours.events.TourEventPublisher;
 import com.tours.model.Tour;
 import com.tours.service.TourService;
 import jakarta.servlet.http.HttpServletRequest;
@@ -21,13 +22,15 @@ import java.util.Optional;
 public class TourController {
 
     private final TourService tourService;
+    private final TourEventPublisher tourEventPublisher;
     
     @Autowired
     private HttpServletRequest httpServletRequest;
 
     @Autowired
-    public TourController(TourService tourService) {
+    public TourController(TourService tourService, TourEventPublisher tourEventPublisher) {
         this.tourService = tourService;
+        this.tourEventPublisher = tourEventPublisher;
     }
 
     private String getCurrentUsername() {
@@ -217,6 +220,7 @@ public class TourController {
 
         boolean deleted = tourService.deleteTour(id, autorUsername);
         if (deleted) {
+            tourEventPublisher.publishTourDeleted(id);
             return ResponseEntity.ok(Map.of("message", "Tura je uspešno obrisana"));
         } else {
             return ResponseEntity.status(HttpStatus.NOT_FOUND)
@@ -0,0 +1,31 @@
+package com.tours.events;
+
+import com.tours.config.RabbitMQConfig;
+import org.springframework.amqp.AmqpException;
+import org.springframework.amqp.rabbit.core.RabbitTemplate;
+import org.springframework.stereotype.Component;
+
+@Component
+public class TourEventPublisher {
+
+    private final RabbitTemplate rabbitTemplate;
+
+    public TourEventPublisher(RabbitTemplate rabbitTemplate) {
+        this.rabbitTemplate = rabbitTemplate;
+    }
+
+    /**
+     * Javlja da je tura obrisana; telo poruke je ID ture kao tekst.
+     * Tura je već obrisana, pa nedostupan RabbitMQ samo završava u logu.
+     */
+    public void publishTourDeleted(Long tourId) {
+        try {
+            rabbitTemplate.convertAndSend(
+                    RabbitMQConfig.TOUR_EVENTS_EXCHANGE,
+                    RabbitMQConfig.TOUR_DELETED_ROUTING_KEY,
+                    String.valueOf(tourId));
+        } catch (AmqpException e) {
+            System.out.println("❌ Događaj tour.deleted za turu " + tourId + " nije poslat: " + e.getMessage());
+        }
+    }
+}
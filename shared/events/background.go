@@ -0,0 +1,55 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+var errQueueFull = errors.New("red događaja je pun")
+
+// Sender je ono što Background prosleđuje; u produkciji Publisher.
+type Sender interface {
+	Publish(routingKey string, event any) error
+}
+
+type message struct {
+	routingKey string
+	event      any
+}
+
+// Background šalje događaje iz zasebne gorutine, da korisnički zahtev ne
+// bi čekao broker. Događaj koji ne može da se pošalje se odbacuje uz log,
+// pa Background služi samo za obaveštenja, gde je gubitak prihvatljiv.
+type Background struct {
+	next  Sender
+	queue chan message
+}
+
+func NewBackground(next Sender, size int) *Background {
+	return &Background{next: next, queue: make(chan message, size)}
+}
+
+// Publish ne blokira; vraća grešku samo kada je red pun.
+func (b *Background) Publish(routingKey string, event any) error {
+	select {
+	case b.queue <- message{routingKey: routingKey, event: event}:
+		return nil
+	default:
+		return errQueueFull
+	}
+}
+
+// Run šalje događaje iz reda dok se ctx ne otkaže.
+func (b *Background) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m := <-b.queue:
+			if err := b.next.Publish(m.routingKey, m.event); err != nil {
+				log.Printf("⚠️ Događaj %s nije poslat: %s", m.routingKey, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Package events šalje JSON događaje Go servisa na RabbitMQ topic exchange.
+//
+// Publisher šalje sinhrono i čeka potvrdu brokera; Background ga obavija
+// kada zahtev ne sme da čeka broker, pa se događaj šalje iz zasebne
+// gorutine.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	exchangeType = "topic"
+
+	publishTimeout = 5 * time.Second
+)
+
+var errNotAcked = errors.New("RabbitMQ nije potvrdio događaj")
+
+// Publisher šalje događaje na RabbitMQ. Veza se otvara pri prvom slanju i
+// obnavlja posle greške, pa nedostupan broker ne sprečava pokretanje servisa.
+type Publisher struct {
+	url      string
+	exchange string
+
+	mu   sync.Mutex
+	conn *amqp.Connection
+	ch   *amqp.Channel
+}
+
+// NewPublisher pravi Publisher koji šalje na exchange (npr.
+// "blog-events-exchange").
+func NewPublisher(url, exchange string) *Publisher {
+	return &Publisher{url: url, exchange: exchange}
+}
+
+// Publish čeka potvrdu brokera; greška znači da događaj možda nije isporučen.
+func (p *Publisher) Publish(routingKey string, event any) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.connect(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	confirmation, err := p.ch.PublishWithDeferredConfirmWithContext(ctx, p.exchange, routingKey, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Body:         body,
+	})
+	if err == nil {
+		var acked bool
+		acked, err = confirmation.WaitContext(ctx)
+		if err == nil && !acked {
+			err = errNotAcked
+		}
+	}
+	if err != nil {
+		p.reset()
+		return err
+	}
+	return nil
+}
+
+// Close zatvara vezu; sledeći Publish je ponovo otvara.
+func (p *Publisher) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reset()
+}
+
+func (p *Publisher) connect() error {
+	if p.ch != nil && !p.ch.IsClosed() {
+		return nil
+	}
+	p.reset()
+
+	conn, err := amqp.Dial(p.url)
+	if err != nil {
+		return err
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if err := ch.ExchangeDeclare(p.exchange, exchangeType, true, false, false, false, nil); err != nil {
+		conn.Close()
+		return err
+	}
+	if err := ch.Confirm(false); err != nil {
+		conn.Close()
+		return err
+	}
+	p.conn, p.ch = conn, ch
+	return nil
+}
+
+func (p *Publisher) reset() {
+	if p.conn != nil {
+		p.conn.Close()
+	}
+	p.conn, p.ch = nil, nil
+}
@@ -0,0 +1,50 @@
+// Package problem piše greške kao application/problem+json (RFC 7807);
+// isti oblik vraćaju svi Go servisi.
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ContentType je media tip definisan u RFC 7807.
+const ContentType = "application/problem+json"
+
+// Problem je telo greške po RFC 7807. Code je stabilan, mašinski čitljiv
+// identifikator greške na koji klijenti mogu da se oslone.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError opisuje grešku vezanu za jedno polje tela zahteva.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func New(status int, code, detail string) Problem {
+	return Problem{
+		Type:   "/problems/" + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Write upisuje problem u odgovor; instance je putanja zahteva.
+func Write(w http.ResponseWriter, r *http.Request, p Problem) {
+	if r != nil {
+		p.Instance = r.URL.Path
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const sweepInterval = time.Minute
+
+// MemoryStore čuva kofe u memoriji procesa; svaka instanca servisa ima
+// svoja ograničenja. Pune kofe se povremeno brišu.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	nextSweep time.Time
+	now       func() time.Time
+}
+
+type memoryBucket struct {
+	bucket
+	full time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*memoryBucket{}, now: time.Now}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, p Policy) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.After(s.nextSweep) {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{bucket: bucket{tokens: float64(p.Limit), at: now}}
+		s.buckets[key] = b
+	}
+	result := b.take(p, now)
+	b.full = now.Add(result.Reset)
+	return result, nil
+}
+
+// sweep briše kofe koje su se u međuvremenu napunile; nova kofa bi imala
+// isto stanje.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if now.After(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	s.nextSweep = now.Add(sweepInterval)
+}
@@ -0,0 +1,120 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"shared/problem"
+)
+
+// headerUsername upisuje gateway posle validacije tokena.
+const headerUsername = "X-Username"
+
+const storeTimeout = 500 * time.Millisecond
+
+// Limiter bira politiku po ruti i odbija zahteve kada je kofa prazna.
+// Rute se zadaju šablonima net/http ServeMux-a ("POST /blogs/like"), pa
+// middleware radi ispred bilo kog rutera.
+type Limiter struct {
+	store      Store
+	routes     *http.ServeMux
+	policies   map[string]Policy
+	fallback   *Policy
+	trustProxy bool
+}
+
+// New: sa trustProxy IP adresa klijenta se čita iz poslednje vrednosti
+// X-Forwarded-For, koju je dodao gateway; bez njega iz RemoteAddr.
+func New(store Store, trustProxy bool) *Limiter {
+	return &Limiter{store: store, routes: http.NewServeMux(), policies: map[string]Policy{}, trustProxy: trustProxy}
+}
+
+// Route dodeljuje politiku šablonu rute.
+func (l *Limiter) Route(pattern string, p Policy) {
+	l.routes.HandleFunc(pattern, func(http.ResponseWriter, *http.Request) {})
+	l.policies[pattern] = p
+}
+
+// Default važi za rute bez sopstvene politike.
+func (l *Limiter) Default(p Policy) {
+	l.fallback = &p
+}
+
+func (l *Limiter) policy(r *http.Request) (Policy, bool) {
+	if _, pattern := l.routes.Handler(r); pattern != "" {
+		return l.policies[pattern], true
+	}
+	if l.fallback != nil {
+		return *l.fallback, true
+	}
+	return Policy{}, false
+}
+
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, ok := l.policy(r)
+		if !ok || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), storeTimeout)
+		result, err := l.store.Take(ctx, "ratelimit:"+p.Name+":"+l.key(r, p), p)
+		cancel()
+		// Nedostupno skladište ne sme da obori servis: zahtev prolazi.
+		if err != nil {
+			log.Printf("⚠️ Rate limit %s nije proveren: %s", p.Name, err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", p.Limit, int(p.Window.Seconds())))
+		h.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		h.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		h.Set("RateLimit-Reset", strconv.Itoa(seconds(result.Reset)))
+		if !result.Allowed {
+			retry := seconds(result.RetryAfter)
+			h.Set("Retry-After", strconv.Itoa(retry))
+			problem.Write(w, r, problem.New(http.StatusTooManyRequests, "rate_limit.exceeded",
+				fmt.Sprintf("Previše zahteva, pokušajte ponovo za %d s.", retry)))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *Limiter) key(r *http.Request, p Policy) string {
+	if p.By == ByUser {
+		if user := r.Header.Get(headerUsername); user != "" {
+			return "user:" + user
+		}
+	}
+	return "ip:" + l.clientIP(r)
+}
+
+func (l *Limiter) clientIP(r *http.Request) string {
+	if l.trustProxy {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			hops := strings.Split(forwarded, ",")
+			return strings.TrimSpace(hops[len(hops)-1])
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// seconds zaokružuje naviše, da klijent ne bi pokušao pre vremena.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
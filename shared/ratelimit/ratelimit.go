@@ -0,0 +1,71 @@
+// Package ratelimit ograničava broj zahteva algoritmom token bucket: svaki
+// ključ (korisnik ili IP adresa) ima kofu od Limit tokena koja se puni
+// brzinom Limit po Window. Stanje kofa čuva Store, u memoriji za jednu
+// instancu ili u Redis-u kada ih ima više.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// KeyBy određuje čiji zahtevi dele kofu.
+type KeyBy int
+
+const (
+	// ByUser koristi korisnika koga je gateway upisao u X-Username, a za
+	// anonimne zahteve IP adresu.
+	ByUser KeyBy = iota
+	ByIP
+)
+
+// Policy je jedno ograničenje. Rute sa istim Name dele kofu.
+type Policy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+	By     KeyBy
+}
+
+// Result je stanje kofe posle jednog zahteva. RetryAfter je vreme do
+// sledećeg tokena (samo kada zahtev nije propušten), a Reset vreme do pune kofe.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	Reset      time.Duration
+}
+
+// Store uzima jedan token iz kofe za key.
+type Store interface {
+	Take(ctx context.Context, key string, p Policy) (Result, error)
+}
+
+// bucket je stanje jedne kofe; take ga pomera do now i uzima token.
+type bucket struct {
+	tokens float64
+	at     time.Time
+}
+
+func (b *bucket) take(p Policy, now time.Time) Result {
+	capacity := float64(p.Limit)
+	rate := capacity / float64(p.Window) // tokena po nanosekundi
+
+	if elapsed := now.Sub(b.at); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+float64(elapsed)*rate)
+	}
+	b.at = now
+
+	result := Result{Limit: p.Limit}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((1 - b.tokens) / rate))
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = time.Duration(math.Ceil((capacity - b.tokens) / rate))
+	return result
+}
@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestMemoryStoreRefills(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Unix(0, 0)
+	store.now = func() time.Time { return now }
+	p := Policy{Name: "test", Limit: 2, Window: 10 * time.Second}
+
+	for i := range 2 {
+		if result, _ := store.Take(context.Background(), "k", p); !result.Allowed || result.Remaining != 1-i {
+			t.Fatalf("zahtev %d = %+v", i, result)
+		}
+	}
+	result, _ := store.Take(context.Background(), "k", p)
+	if result.Allowed || result.RetryAfter != 5*time.Second || result.Reset != 10*time.Second {
+		t.Fatalf("prazna kofa = %+v", result)
+	}
+
+	now = now.Add(5 * time.Second)
+	if result, _ := store.Take(context.Background(), "k", p); !result.Allowed || result.Remaining != 0 {
+		t.Errorf("posle 5 s = %+v", result)
+	}
+	if result, _ := store.Take(context.Background(), "drugi", p); !result.Allowed {
+		t.Errorf("drugi ključ deli kofu: %+v", result)
+	}
+
+	now = now.Add(time.Hour)
+	store.Take(context.Background(), "k", p)
+	if len(store.buckets) != 1 {
+		t.Errorf("pune kofe nisu obrisane: %d", len(store.buckets))
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	limiter := New(NewMemoryStore(), true)
+	limiter.Route("POST /like/{id}", Policy{Name: "like", Limit: 1, Window: time.Minute})
+	limiter.Default(Policy{Name: "default", Limit: 100, Window: time.Minute, By: ByIP})
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	do := func(method, path, user, forwarded string) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, path, nil)
+		if user != "" {
+			req.Header.Set(headerUsername, user)
+		}
+		if forwarded != "" {
+			req.Header.Set("X-Forwarded-For", forwarded)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodPost, "/like/1", "ana", ""); rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Remaining") != "0" || rec.Header().Get("RateLimit-Policy") != "1;w=60" {
+		t.Fatalf("prvi lajk: %d %v", rec.Code, rec.Header())
+	}
+	rec := do(http.MethodPost, "/like/2", "ana", "")
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != "60" || !strings.Contains(rec.Body.String(), "rate_limit.exceeded") {
+		t.Fatalf("drugi lajk: %d %v %s", rec.Code, rec.Header(), rec.Body.String())
+	}
+	if rec := do(http.MethodPost, "/like/2", "marko", ""); rec.Code != http.StatusOK {
+		t.Errorf("drugi korisnik: status = %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/like/2", "", "10.0.0.9, 10.0.0.1"); rec.Code != http.StatusOK {
+		t.Errorf("anoniman zahtev: status = %d", rec.Code)
+	}
+	if rec := do(http.MethodPost, "/like/2", "", "10.0.0.1"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("ista IP adresa: status = %d, očekivano 429", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/like/2", "ana", ""); rec.Code != http.StatusOK || rec.Header().Get("RateLimit-Limit") != "100" {
+		t.Errorf("podrazumevana politika: %d %v", rec.Code, rec.Header())
+	}
+}
+
+// TestRedisStore izvršava takeScript na miniredis serveru, koji pokreće
+// pravu Lua skriptu.
+func TestRedisStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.RequireAuth("tajna")
+
+	store, err := NewRedisStore("redis://:tajna@" + server.Addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := Policy{Limit: 2, Window: time.Minute}
+	ctx := context.Background()
+
+	for i := range 2 {
+		if result, err := store.Take(ctx, "ratelimit:like:user:ana", p); err != nil || !result.Allowed || result.Remaining != 1-i {
+			t.Fatalf("zahtev %d = %+v, %v", i, result, err)
+		}
+	}
+	result, err := store.Take(ctx, "ratelimit:like:user:ana", p)
+	if err != nil {
+		t.Fatalf("Take: %v", err)
+	}
+	if result.Allowed || result.RetryAfter <= 0 || result.RetryAfter > 30*time.Second || result.Limit != 2 {
+		t.Errorf("prazna kofa = %+v", result)
+	}
+	if ttl := server.TTL("ratelimit:like:user:ana"); ttl <= 0 {
+		t.Errorf("kofa nema rok trajanja: %s", ttl)
+	}
+
+	if _, err := NewRedisStore("http://localhost:6379"); err == nil {
+		t.Error("očekivana greška za adresu koja nije redis://")
+	}
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript je ista računica kao bucket.take, izvršena atomično u Redis-u.
+// Vreme dolazi od Redis-a, da razlike u satovima instanci ne bi uticale na
+// punjenje. Vraća {propušten, preostalo, retryAfter ms, reset ms};
+// replicate_commands dozvoljava TIME pre upisa na Redis-u starijem od 5.
+var takeScript = redis.NewScript(`
+redis.replicate_commands()
+local capacity = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'at')
+local tokens = tonumber(state[1]) or capacity
+local at = tonumber(state[2]) or now
+local rate = capacity / window
+if now > at then
+	tokens = math.min(capacity, tokens + (now - at) * rate)
+end
+local allowed, retry = 0, 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+local reset = math.ceil((capacity - tokens) / rate)
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'at', now)
+redis.call('PEXPIRE', KEYS[1], reset + 1000)
+return {allowed, math.floor(tokens), retry, reset}
+`)
+
+// RedisStore čuva kofe u Redis-u (ili serveru koji govori isti protokol,
+// npr. Valkey), pa sve instance servisa dele ograničenja.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore prima adresu oblika redis://[:lozinka@]host:port[/baza].
+func NewRedisStore(rawURL string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisStore{client: redis.NewClient(opts)}, nil
+}
+
+// Take izvršava takeScript; Run šalje EVALSHA, a EVAL samo kada Redis
+// skriptu još nema u kešu.
+func (s *RedisStore) Take(ctx context.Context, key string, p Policy) (Result, error) {
+	n, err := takeScript.Run(ctx, s.client, []string{key}, p.Limit, p.Window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(n) != 4 {
+		return Result{}, fmt.Errorf("neočekivan odgovor Redis-a: %v", n)
+	}
+	return Result{
+		Allowed:    n[0] == 1,
+		Limit:      p.Limit,
+		Remaining:  int(n[1]),
+		RetryAfter: time.Duration(n[2]) * time.Millisecond,
+		Reset:      time.Duration(n[3]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,111 @@
+// Package stakeholders je klijent za REST API service-stakeholders.
+//
+// service-stakeholders pušta samo zahteve koji nose X-Username i
+// X-User-Role (inače odgovara 403), pa Client uz svaki zahtev šalje ime
+// servisa koji ga zove i ulogu RoleService.
+package stakeholders
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderUsername = "X-Username"
+	HeaderUserRole = "X-User-Role"
+
+	// RoleService je uloga servisa koji zove drugi servis, a ne korisnika.
+	RoleService = "ROLE_SERVICE"
+)
+
+// User je korisnik kako ga vraća service-stakeholders.
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Blocked  bool   `json:"blocked"`
+}
+
+// Client čita korisnike preko REST API-ja service-stakeholders.
+type Client struct {
+	baseURL    string
+	service    string
+	httpClient *http.Client
+}
+
+// NewClient pravi klijent koji se service-stakeholders predstavlja kao
+// service (npr. "blog-service").
+func NewClient(baseURL, service string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		service:    service,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// UserByID vraća korisnika; found je false ako ne postoji.
+func (c *Client) UserByID(ctx context.Context, id int64) (User, bool, error) {
+	var u User
+	found, err := c.get(ctx, "/users/"+strconv.FormatInt(id, 10), &u)
+	return u, found && u.ID != 0, err
+}
+
+// UserByUsername vraća korisnika; found je false ako ne postoji.
+func (c *Client) UserByUsername(ctx context.Context, username string) (User, bool, error) {
+	var u User
+	found, err := c.get(ctx, "/users/username/"+url.PathEscape(username), &u)
+	return u, found && u.ID != 0, err
+}
+
+// ActiveUsers vraća sve korisnike koji nisu blokirani.
+func (c *Client) ActiveUsers(ctx context.Context) ([]User, error) {
+	return c.list(ctx, "/users/active")
+}
+
+// BlockedUsers vraća korisnike koje je administrator blokirao.
+func (c *Client) BlockedUsers(ctx context.Context) ([]User, error) {
+	return c.list(ctx, "/users/blocked")
+}
+
+func (c *Client) list(ctx context.Context, path string) ([]User, error) {
+	var users []User
+	found, err := c.get(ctx, path, &users)
+	if err == nil && !found {
+		err = fmt.Errorf("service-stakeholders nema %s", path)
+	}
+	return users, err
+}
+
+// get čita JSON sa path u out; 404 nije greška nego found=false.
+func (c *Client) get(ctx context.Context, path string, out any) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set(HeaderUsername, c.service)
+	req.Header.Set(HeaderUserRole, RoleService)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("service-stakeholders je vratio %d za %s", resp.StatusCode, path)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("neispravan odgovor service-stakeholders sa %s: %w", path, err)
+	}
+	return true, nil
+}
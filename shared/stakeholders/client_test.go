@@ -0,0 +1,81 @@
+package stakeholders
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newStakeholders oponaša service-stakeholders: kao JwtAuthFilter i
+// anyRequest().authenticated(), bez X-Username i X-User-Role odgovara 403.
+func newStakeholders(t *testing.T) *httptest.Server {
+	t.Helper()
+	users := map[string]User{
+		"/users/7":            {ID: 7, Username: "ana"},
+		"/users/username/ana": {ID: 7, Username: "ana"},
+	}
+	lists := map[string][]User{
+		"/users/active":  {{ID: 7, Username: "ana"}},
+		"/users/blocked": {{ID: 9, Username: "zoran", Blocked: true}},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(HeaderUsername) == "" || r.Header.Get(HeaderUserRole) == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if u, ok := users[r.URL.Path]; ok {
+			json.NewEncoder(w).Encode(u)
+			return
+		}
+		if list, ok := lists[r.URL.Path]; ok {
+			json.NewEncoder(w).Encode(list)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClientAuthenticates(t *testing.T) {
+	client := NewClient(newStakeholders(t).URL, "test-service", time.Second)
+	ctx := context.Background()
+
+	if u, found, err := client.UserByID(ctx, 7); err != nil || !found || u.Username != "ana" {
+		t.Errorf("UserByID = %+v, %t, %v", u, found, err)
+	}
+	if u, found, err := client.UserByUsername(ctx, "ana"); err != nil || !found || u.ID != 7 {
+		t.Errorf("UserByUsername = %+v, %t, %v", u, found, err)
+	}
+	if _, found, err := client.UserByUsername(ctx, "nema"); err != nil || found {
+		t.Errorf("nepostojeći korisnik: found = %t, %v", found, err)
+	}
+	if active, err := client.ActiveUsers(ctx); err != nil || len(active) != 1 {
+		t.Errorf("ActiveUsers = %+v, %v", active, err)
+	}
+	if blocked, err := client.BlockedUsers(ctx); err != nil || len(blocked) != 1 || !blocked[0].Blocked {
+		t.Errorf("BlockedUsers = %+v, %v", blocked, err)
+	}
+}
+
+func TestClientWithoutIdentityIsForbidden(t *testing.T) {
+	server := newStakeholders(t)
+
+	resp, err := http.Get(server.URL + "/users/active")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("zahtev bez identiteta: status = %d, očekivano 403", resp.StatusCode)
+	}
+
+	// Prazno ime servisa je isto što i zahtev bez identiteta.
+	if _, err := NewClient(server.URL, "", time.Second).ActiveUsers(context.Background()); err == nil {
+		t.Error("očekivana greška za 403")
+	}
+}
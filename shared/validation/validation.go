@@ -0,0 +1,201 @@
+// Package validation čita JSON telo zahteva i proverava validate tagove;
+// greške vraća po poljima, u obliku problem.FieldError.
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+
+	"shared/problem"
+)
+
+// Errors je lista grešaka po poljima; vraća se kao "errors" u problem odgovoru.
+type Errors []problem.FieldError
+
+func (e Errors) Error() string {
+	parts := make([]string, 0, len(e))
+	for _, fe := range e {
+		parts = append(parts, fe.Field+": "+fe.Message)
+	}
+	return "neispravan zahtev: " + strings.Join(parts, "; ")
+}
+
+var (
+	ErrBodyTooLarge = errors.New("telo zahteva je preveliko")
+	ErrTrailingData = errors.New("telo zahteva sadrži više od jednog JSON objekta")
+)
+
+var imageExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".gif":  true,
+	".webp": true,
+}
+
+var (
+	allowedImageHostsMu sync.RWMutex
+	allowedImageHosts   map[string]bool
+)
+
+// SetAllowedImageHosts ograničava hostove za slike. Prazna lista znači
+// da je dozvoljen bilo koji http(s) host.
+func SetAllowedImageHosts(hosts []string) {
+	allowed := make(map[string]bool, len(hosts))
+	for _, h := range hosts {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h != "" {
+			allowed[h] = true
+		}
+	}
+
+	allowedImageHostsMu.Lock()
+	defer allowedImageHostsMu.Unlock()
+	allowedImageHosts = allowed
+}
+
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// U greškama koristimo JSON imena polja, ne Go imena.
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		if name == "-" || name == "" {
+			return f.Name
+		}
+		return name
+	})
+
+	v.RegisterValidation("notblank", func(fl validator.FieldLevel) bool {
+		return strings.TrimSpace(fl.Field().String()) != ""
+	})
+	v.RegisterValidation("imageurl", func(fl validator.FieldLevel) bool {
+		return isAllowedImageURL(fl.Field().String())
+	})
+	return v
+}
+
+func isAllowedImageURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	if !imageExtensions[strings.ToLower(path.Ext(u.Path))] {
+		return false
+	}
+
+	allowedImageHostsMu.RLock()
+	defer allowedImageHostsMu.RUnlock()
+	return len(allowedImageHosts) == 0 || allowedImageHosts[strings.ToLower(u.Hostname())]
+}
+
+// RegisterAlias uvodi validate tag alias kao skraćenicu za tags, da bi
+// servis granice izveo iz svojih konstanti umesto da ih ponavlja u tagovima.
+// Poziva se pre prve provere, npr. iz init.
+func RegisterAlias(alias, tags string) {
+	validate.RegisterAlias(alias, tags)
+}
+
+// DecodeJSON čita tačno jedan JSON objekat, najviše maxBytes bajtova,
+// i odbija polja koja ne postoje u dst.
+func DecodeJSON(w http.ResponseWriter, r *http.Request, dst any, maxBytes int64) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return ErrBodyTooLarge
+		}
+		if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+			return Errors{{
+				Field:   strings.Trim(field, `"`),
+				Code:    "unknown_field",
+				Message: "Polje nije dozvoljeno.",
+			}}
+		}
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			return Errors{{
+				Field:   typeErr.Field,
+				Code:    "invalid_type",
+				Message: "Polje je pogrešnog tipa.",
+			}}
+		}
+		return err
+	}
+	if dec.More() {
+		return ErrTrailingData
+	}
+	return nil
+}
+
+// Struct proverava validate tagove i vraća Errors ili nil.
+func Struct(v any) error {
+	err := validate.Struct(v)
+	if err == nil {
+		return nil
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+
+	out := make(Errors, 0, len(verrs))
+	for _, fe := range verrs {
+		out = append(out, problem.FieldError{
+			Field:   fieldPath(fe),
+			Code:    fe.ActualTag(),
+			Message: message(fe),
+		})
+	}
+	return out
+}
+
+// fieldPath skida ime korenske strukture: "CreateBlogRequest.images[0]" -> "images[0]".
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return ns
+}
+
+func message(fe validator.FieldError) string {
+	// ActualTag je pravilo koje je palo i kada je zadato preko aliasa.
+	switch fe.ActualTag() {
+	case "required", "notblank":
+		return "Polje je obavezno."
+	case "max":
+		if fe.Kind() == reflect.Slice {
+			return fmt.Sprintf("Dozvoljeno je najviše %s stavki.", fe.Param())
+		}
+		return fmt.Sprintf("Dozvoljeno je najviše %s karaktera.", fe.Param())
+	case "min":
+		return fmt.Sprintf("Potrebno je najmanje %s karaktera.", fe.Param())
+	case "gt":
+		return fmt.Sprintf("Vrednost mora biti veća od %s.", fe.Param())
+	case "nefield":
+		return fmt.Sprintf("Vrednost mora da se razlikuje od polja %s.", fe.Param())
+	case "imageurl":
+		return "URL slike nije dozvoljen."
+	default:
+		return "Neispravna vrednost."
+	}
+}